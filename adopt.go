@@ -0,0 +1,46 @@
+package errx
+
+// Adopt turns err into an errx error holding frames captured elsewhere,
+// such as the stack recorded by another error library, instead of
+// scanning the stack of the caller. Frames are listed in the order Frames
+// returns them: wrap sites newest first, then the stack of the first one,
+// innermost call first; set WrapSite on the ones recorded by wrap calls.
+// The result gets a support ID, the default
+// attributes and attrs like a first wrap, and is passed to the OnWrap
+// hooks. If err already is an errx error, the frames are listed after
+// its own instead, as by WithForeignFrames, and attrs are attached.
+// Returns nil if err is nil.
+func Adopt(err error, frames []Frame, attrs ...Attr) error {
+	if err == nil {
+		return nil
+	}
+
+	if extErr, ok := err.(*extendedError); ok {
+		adopted := &extendedError{
+			err:   extErr.err,
+			top:   extErr.top,
+			depth: extErr.depth,
+			base:  appendForeign(extErr.base[:len(extErr.base):len(extErr.base)], frames),
+			attrs: append(extErr.attrs[:len(extErr.attrs):len(extErr.attrs)], attrs...),
+			cfg:   extErr.cfg,
+		}
+		runHooks(adopted)
+		return adopted
+	}
+
+	c := loadConfig()
+	extErr := &extendedError{err: err, base: appendForeign(nil, frames)}
+	// like on first wrap, the innermost errx error of a chain holds its
+	// ID and the details of its cause
+	origin := !hasExtendedError(err)
+	if origin {
+		extErr.attrs = append(extErr.attrs, Attr{Key: keyID, Value: newID(c)})
+	}
+	extErr.attrs = append(extErr.attrs, c.DefaultAttrs...)
+	extErr.attrs = append(extErr.attrs, attrs...)
+	if origin {
+		extErr.attrs = append(extErr.attrs, enrich(err)...)
+	}
+	runHooks(extErr)
+	return extErr
+}
@@ -0,0 +1,60 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdopt(t *testing.T) {
+	t.Parallel()
+
+	erisStack := []Frame{
+		{Function: "github.com/acme/users.Load", File: "/src/users/load.go", Line: 30, WrapSite: true},
+		{Function: "github.com/acme/users.find", File: "/src/users/find.go", Line: 12, WrapSite: true},
+		{Function: "github.com/acme/users.query", File: "/src/users/query.go", Line: 8},
+	}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Adopt(nil, erisStack))
+	})
+
+	t.Run("uses the frames instead of the stack", func(t *testing.T) {
+		t.Parallel()
+
+		cause := errors.New("not found")
+		err := Adopt(cause, erisStack, Attr{Key: "user_id", Value: 42})
+
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, erisStack, Frames(err))
+		assert.Equal(t, "users.Load (load.go:30): users.find (find.go:12): users.query (query.go:8): not found", err.Error())
+		assert.NotEmpty(t, ID(err))
+		assert.Equal(t, []Attr{{Key: "user_id", Value: 42}}, Attrs(err))
+	})
+
+	t.Run("appends to errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := Wrap(errors.New("not found"))
+		err := Adopt(wrapped, erisStack)
+
+		require.Len(t, Frames(err), len(Frames(wrapped))+len(erisStack))
+		assert.Equal(t, erisStack, Frames(err)[len(Frames(wrapped)):])
+		assert.Equal(t, ID(wrapped), ID(err))
+	})
+
+	t.Run("wrapped later like any errx error", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(Adopt(errors.New("not found"), erisStack))
+
+		frames := Frames(err)
+		require.Len(t, frames, len(erisStack)+1)
+		assert.Equal(t, "github.com/alesr/errx.TestAdopt.func4", frames[0].Function)
+		assert.Equal(t, erisStack, frames[1:])
+	})
+}
@@ -0,0 +1,71 @@
+//go:build !race
+
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocs guards the allocations of the hot paths. Raising a budget
+// should be a deliberate decision, made alongside the benchmarks in
+// bench_test.go. The race detector changes what escapes to the heap, so
+// the budgets are only checked in regular builds.
+func TestAllocs(t *testing.T) {
+	// AllocsPerRun can't be used by parallel tests
+
+	boom := errors.New("boom")
+	wrapped := Wrap(Wrap(boom))
+	buf := make([]byte, 0, 1024)
+
+	tests := []struct {
+		name   string
+		budget float64
+		fn     func()
+	}{
+		{
+			name:   "first wrap",
+			budget: 6,
+			fn:     func() { _ = Wrap(boom) },
+		},
+		{
+			name:   "first wrap with message",
+			budget: 8,
+			fn:     func() { _ = Wrapf(boom, "loading user %d", 42) },
+		},
+		{
+			name:   "re-wrap",
+			budget: 1,
+			fn:     func() { _ = Wrap(wrapped) },
+		},
+		{
+			name:   "error",
+			budget: 1,
+			fn:     func() { _ = wrapped.Error() },
+		},
+		{
+			name:   "append error",
+			budget: 0,
+			fn:     func() { buf = AppendError(buf[:0], wrapped) },
+		},
+		{
+			name:   "verbose",
+			budget: 7,
+			fn:     func() { _ = fmt.Sprintf("%+v", wrapped) },
+		},
+		{
+			name:   "frames",
+			budget: 4,
+			fn:     func() { _ = Frames(wrapped) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.LessOrEqual(t, testing.AllocsPerRun(100, tt.fn), tt.budget)
+		})
+	}
+}
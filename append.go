@@ -0,0 +1,37 @@
+package errx
+
+import "fmt"
+
+// AppendError appends the output of err.Error() to dst and returns the
+// extended buffer, for loggers managing their own buffers. For errx
+// errors, it renders into dst directly and doesn't allocate when dst has
+// room, unless the cause or a lazy message allocates to render.
+// Appends nothing if err is nil.
+func AppendError(dst []byte, err error) []byte {
+	switch e := err.(type) {
+	case nil:
+		return dst
+	case *extendedError:
+		return e.appendError(dst)
+	default:
+		return append(dst, err.Error()...)
+	}
+}
+
+// AppendVerbose appends the %+v output of err to dst and returns the
+// extended buffer, like AppendError. Attributes and capture times still
+// allocate to render.
+// Appends nothing if err is nil.
+func AppendVerbose(dst []byte, err error) []byte {
+	switch e := err.(type) {
+	case nil:
+		return dst
+	case *extendedError:
+		if leakTracking.Load() {
+			Handled(e)
+		}
+		return e.appendVerbose(dst)
+	default:
+		return fmt.Appendf(dst, "%+v", err)
+	}
+}
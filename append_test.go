@@ -0,0 +1,37 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendError(t *testing.T) {
+	t.Parallel()
+
+	wrapped := Wrapf(Wrap(With(errors.New("boom"), "user", 42)), "loading %s", "profile")
+	tests := map[string]error{
+		"errx error":  wrapped,
+		"plain error": errors.New("boom"),
+		"nested":      fmt.Errorf("handler: %w", wrapped),
+		"formatter":   Wrap(detailedError{}),
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix := []byte("err=")
+			assert.Equal(t, "err="+err.Error(), string(AppendError(prefix, err)))
+			assert.Equal(t, "err="+fmt.Sprintf("%+v", err), string(AppendVerbose(prefix, err)))
+		})
+	}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "err=", string(AppendError([]byte("err="), nil)))
+		assert.Equal(t, "err=", string(AppendVerbose([]byte("err="), nil)))
+	})
+}
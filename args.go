@@ -0,0 +1,47 @@
+package errx
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// maxArgLen bounds the rendering of each value recorded by WithArgs.
+const maxArgLen = 256
+
+// WithArgs wraps err like Wrap and records values on the current frame,
+// such as the inputs of the failing function. Values are rendered right
+// away, so later changes to them don't show: strings quoted, values
+// implementing slog.LogValuer through their LogValue, so types that
+// redact themselves in logs stay redacted here, and everything else with
+// fmt. Renderings longer than 256 bytes are cut.
+// Arguments show in %+v output under their frame, not in Error.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func WithArgs(err error, values ...any) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+	return wrap(nil, err, 2, contextFrame{args: snapshotArgs(values)})
+}
+
+// snapshotArgs renders values for WithArgs as a comma-separated list.
+func snapshotArgs(values []any) string {
+	args := make([]string, 0, len(values))
+	for _, v := range values {
+		var s string
+		switch v := v.(type) {
+		case slog.LogValuer:
+			s = slog.AnyValue(v).Resolve().String()
+		case string:
+			s = strconv.Quote(v)
+		default:
+			s = fmt.Sprint(v)
+		}
+		if len(s) > maxArgLen {
+			s = strings.ToValidUTF8(s[:maxArgLen], "") + "..."
+		}
+		args = append(args, s)
+	}
+	return strings.Join(args, ", ")
+}
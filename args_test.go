@@ -0,0 +1,79 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// password redacts itself in logs.
+type password string
+
+func (password) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+
+func TestWithArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithArgs(nil, 1))
+	})
+
+	t.Run("recorded on the current frame", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithArgs(errors.New("boom"), 42, "alice", password("hunter2"))
+		err = Wrap(err)
+
+		frames := Frames(err)
+		require.GreaterOrEqual(t, len(frames), 2)
+		assert.Empty(t, frames[0].Args)
+		assert.Equal(t, `42, "alice", [REDACTED]`, frames[1].Args)
+
+		assert.NotContains(t, err.Error(), "alice")
+		verbose := fmt.Sprintf("%+v", err)
+		assert.Regexp(t, `\n\[1\] errx\.TestWithArgs\.\S+ \(args_test\.go:\d+\): boom\n    args: 42, "alice", \[REDACTED\]\n`, verbose)
+		assert.NotContains(t, verbose, "hunter2")
+	})
+
+	t.Run("snapshot at wrap time", func(t *testing.T) {
+		t.Parallel()
+
+		user := struct{ Name string }{Name: "alice"}
+		err := WithArgs(errors.New("boom"), &user)
+		user.Name = "bob"
+
+		assert.Equal(t, "&{alice}", Frames(err)[0].Args)
+	})
+
+	t.Run("long values are cut", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithArgs(errors.New("boom"), strings.Repeat("x", 1000))
+
+		args := Frames(err)[0].Args
+		assert.Len(t, args, maxArgLen+len("..."))
+		assert.True(t, strings.HasSuffix(args, "..."))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithArgs(errors.New("boom"), 42)
+		data, encErr := Encode(err)
+		require.NoError(t, encErr)
+
+		decoded, decErr := Decode(data)
+		require.NoError(t, decErr)
+		assert.Equal(t, "42", Frames(decoded)[0].Args)
+		assert.Equal(t, fmt.Sprintf("%+v", err), fmt.Sprintf("%+v", decoded))
+	})
+}
@@ -0,0 +1,145 @@
+package errx
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// reservedPrefix marks attribute keys used internally by errx.
+const reservedPrefix = "errx."
+
+// Attr is a key/value pair attached to an error with With.
+type Attr struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// With attaches a structured attribute to err. If err is already an errx
+// error the attribute is added to its chain without capturing a new frame,
+// otherwise err is wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func With(err error, key string, value any) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: key, Value: value}, 3)
+}
+
+// Attrs returns the attributes attached anywhere along err's chain, in the
+// order they were attached. When the same key was set more than once the
+// outermost value wins, unless the AttrPolicy of err says otherwise.
+// Keys prefixed with "errx." are reserved for the package and left out.
+func Attrs(err error) []Attr {
+	var (
+		attrs []Attr
+		chain visited
+	)
+	for e := err; e != nil && chain.add(e); e = errors.Unwrap(e) {
+		extErr, ok := e.(*extendedError)
+		if !ok {
+			continue
+		}
+
+		for i := len(extErr.attrs) - 1; i >= 0; i-- {
+			if !strings.HasPrefix(extErr.attrs[i].Key, reservedPrefix) {
+				attrs = append(attrs, extErr.attrs[i])
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	// attrs goes from the outermost attribute to the innermost one here
+	switch attrPolicy(err, "") {
+	case KeepAll:
+		slices.Reverse(attrs)
+	case InnermostWins:
+		slices.Reverse(attrs)
+		attrs = firstPerKey(attrs)
+	default:
+		attrs = firstPerKey(attrs)
+		slices.Reverse(attrs)
+	}
+	return attrs
+}
+
+// firstPerKey removes from attrs the attributes whose key appeared earlier.
+func firstPerKey(attrs []Attr) []Attr {
+	seen := make(map[string]bool, len(attrs))
+	return slices.DeleteFunc(attrs, func(attr Attr) bool {
+		if seen[attr.Key] {
+			return true
+		}
+		seen[attr.Key] = true
+		return false
+	})
+}
+
+// Value walks err's chain looking for the attribute stored under key and
+// returns its value asserted to T. It reports false when the key is missing
+// or holds a value of a different type. When the key was set more than
+// once, the value returned follows the AttrPolicy of err; see ValuesAll
+// for every value.
+func Value[T any](err error, key string) (T, bool) {
+	var (
+		zero  T
+		found *Attr
+		chain visited
+	)
+	innermost := attrPolicy(err, key) == InnermostWins
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+
+		for i := len(extErr.attrs) - 1; i >= 0; i-- {
+			if extErr.attrs[i].Key != key {
+				continue
+			}
+			found = &extErr.attrs[i]
+			if !innermost {
+				break
+			}
+		}
+		if found != nil && !innermost {
+			break
+		}
+	}
+	if found == nil {
+		return zero, false
+	}
+	v, ok := found.Value.(T)
+	return v, ok
+}
+
+// withAttr attaches attr to err, wrapping it first when it isn't an errx
+// error yet. skip is the number of stack frames between the caller to
+// record and wrap itself.
+func withAttr(err error, attr Attr, skip int) *extendedError {
+	extErr, ok := err.(*extendedError)
+	if !ok {
+		if extErr, ok = wrap(nil, err, skip, contextFrame{}).(*extendedError); !ok {
+			extErr = &extendedError{err: err}
+		}
+	}
+	// History tells who set codes, kinds, severities and public messages
+	if at, ok := extErr.config().setAt(attr, skip-1); ok {
+		return extErr.withAttr(attr, at)
+	}
+	return extErr.withAttr(attr)
+}
+
+// withAttr returns a copy of e carrying attrs.
+func (e *extendedError) withAttr(attrs ...Attr) *extendedError {
+	return &extendedError{
+		err:   e.err,
+		top:   e.top,
+		depth: e.depth,
+		base:  e.base,
+		attrs: append(e.attrs[:len(e.attrs):len(e.attrs)], attrs...),
+		cfg:   e.cfg,
+	}
+}
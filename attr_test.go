@@ -0,0 +1,160 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, With(nil, "key", "value"))
+	})
+
+	t.Run("wraps plain errors", func(t *testing.T) {
+		t.Parallel()
+
+		originalErr := errors.New("test error")
+		err := With(originalErr, "user_id", 42)
+
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, originalErr))
+		assert.Contains(t, err.Error(), "TestWith")
+	})
+
+	t.Run("does not add frames to errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := Wrap(errors.New("test error"))
+		err := With(wrapped, "user_id", 42)
+
+		assert.Equal(t, wrapped.Error(), err.Error())
+	})
+
+	t.Run("preserved through wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		err := With(errors.New("test error"), "user_id", 42)
+		err = Wrap(err)
+		err = fmt.Errorf("context: %w", err)
+		err = Wrap(err)
+
+		v, ok := Value[int](err, "user_id")
+		require.True(t, ok)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("does not mutate the original error", func(t *testing.T) {
+		t.Parallel()
+
+		base := With(errors.New("test error"), "a", 1)
+		first := With(base, "b", 2)
+		second := With(base, "c", 3)
+
+		assert.Len(t, Attrs(base), 1)
+		assert.Len(t, Attrs(first), 2)
+		assert.Len(t, Attrs(second), 2)
+
+		_, ok := Value[int](second, "b")
+		assert.False(t, ok)
+	})
+}
+
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no attributes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Attrs(errors.New("plain")))
+		assert.Empty(t, Attrs(Wrap(errors.New("plain"))))
+		assert.Empty(t, Attrs(nil))
+	})
+
+	t.Run("outermost value wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := With(errors.New("test error"), "attempt", 1)
+		err = fmt.Errorf("retrying: %w", err)
+		err = With(err, "attempt", 2)
+		err = With(err, "host", "db-1")
+
+		assert.Equal(t, []Attr{
+			{Key: "attempt", Value: 2},
+			{Key: "host", Value: "db-1"},
+		}, Attrs(err))
+	})
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	err := With(errors.New("test error"), "timeout", 3*time.Second)
+	err = With(err, "user_id", 42)
+
+	testCases := []struct {
+		name     string
+		check    func() (any, bool)
+		expected any
+		found    bool
+	}{
+		{
+			name: "duration",
+			check: func() (any, bool) {
+				return Value[time.Duration](err, "timeout")
+			},
+			expected: 3 * time.Second,
+			found:    true,
+		},
+		{
+			name: "int",
+			check: func() (any, bool) {
+				return Value[int](err, "user_id")
+			},
+			expected: 42,
+			found:    true,
+		},
+		{
+			name: "wrong type",
+			check: func() (any, bool) {
+				return Value[string](err, "user_id")
+			},
+			expected: "",
+			found:    false,
+		},
+		{
+			name: "missing key",
+			check: func() (any, bool) {
+				return Value[int](err, "missing")
+			},
+			expected: 0,
+			found:    false,
+		},
+		{
+			name: "plain error",
+			check: func() (any, bool) {
+				return Value[int](errors.New("plain"), "user_id")
+			},
+			expected: 0,
+			found:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, ok := tc.check()
+			assert.Equal(t, tc.found, ok)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
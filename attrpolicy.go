@@ -0,0 +1,71 @@
+package errx
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// AttrPolicy selects the value Attrs and Value return for an attribute
+// key set at more than one layer of a chain, such as a "user_id" attached
+// by the repository and again by the handler.
+type AttrPolicy int
+
+const (
+	// OutermostWins keeps the value set last, closest to the caller.
+	// It is the default.
+	OutermostWins AttrPolicy = iota
+	// InnermostWins keeps the value set first, closest to the cause.
+	InnermostWins
+	// KeepAll makes Attrs list every value set, in the order they were
+	// attached. Value returns the outermost one.
+	KeepAll
+)
+
+// ResolveAttrs sets how the Wrapper's errors resolve attribute keys set
+// more than once, see Config.AttrPolicy.
+func ResolveAttrs(p AttrPolicy) Option {
+	return optionFunc(func(c *Config) {
+		c.AttrPolicy = p
+	})
+}
+
+// ValuesAll returns every value of type T stored under key along err's
+// chain, in the order they were attached, innermost first, whatever the
+// AttrPolicy. Values of other types are skipped.
+func ValuesAll[T any](err error, key string) []T {
+	var (
+		values []T
+		chain  visited
+	)
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+
+		for i := len(extErr.attrs) - 1; i >= 0; i-- {
+			if extErr.attrs[i].Key != key {
+				continue
+			}
+			if v, ok := extErr.attrs[i].Value.(T); ok {
+				values = append(values, v)
+			}
+		}
+	}
+	slices.Reverse(values)
+	return values
+}
+
+// attrPolicy returns the policy resolving key along err's chain, the one
+// of its outermost errx error. Reserved keys always resolve to their
+// outermost value.
+func attrPolicy(err error, key string) AttrPolicy {
+	if strings.HasPrefix(key, reservedPrefix) {
+		return OutermostWins
+	}
+	if extErr := firstExtended(err); extErr != nil {
+		return extErr.config().AttrPolicy
+	}
+	return OutermostWins
+}
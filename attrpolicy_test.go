@@ -0,0 +1,74 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttrPolicy(t *testing.T) {
+	t.Parallel()
+
+	// layered sets user_id at two layers, the handler's on top
+	layered := func(w *Wrapper) error {
+		err := With(errors.New("no rows"), "user_id", 7)
+		err = With(err, "table", "users")
+		err = w.Wrap(fmt.Errorf("loading profile: %w", err))
+		return With(err, "user_id", 42)
+	}
+
+	t.Run("outermost wins by default", func(t *testing.T) {
+		t.Parallel()
+
+		err := layered(NewWrapper())
+
+		assert.Equal(t, []Attr{{Key: "table", Value: "users"}, {Key: "user_id", Value: 42}}, Attrs(err))
+		v, ok := Value[int](err, "user_id")
+		assert.True(t, ok)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("innermost wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := layered(NewWrapper(ResolveAttrs(InnermostWins)))
+
+		assert.Equal(t, []Attr{{Key: "user_id", Value: 7}, {Key: "table", Value: "users"}}, Attrs(err))
+		v, ok := Value[int](err, "user_id")
+		assert.True(t, ok)
+		assert.Equal(t, 7, v)
+
+		// reserved keys keep resolving to their outermost value
+		assert.Equal(t, "outer", Hint(WithHint(WithHint(err, "inner"), "outer")))
+	})
+
+	t.Run("keep all", func(t *testing.T) {
+		t.Parallel()
+
+		err := layered(NewWrapper(ResolveAttrs(KeepAll)))
+
+		assert.Equal(t, []Attr{
+			{Key: "user_id", Value: 7},
+			{Key: "table", Value: "users"},
+			{Key: "user_id", Value: 42},
+		}, Attrs(err))
+		v, _ := Value[int](err, "user_id")
+		assert.Equal(t, 42, v)
+	})
+}
+
+func TestValuesAll(t *testing.T) {
+	t.Parallel()
+
+	err := With(errors.New("no rows"), "user_id", 7)
+	err = With(err, "user_id", "seven")
+	err = Wrap(fmt.Errorf("loading profile: %w", err))
+	err = With(err, "user_id", 42)
+
+	assert.Equal(t, []int{7, 42}, ValuesAll[int](err, "user_id"))
+	assert.Equal(t, []string{"seven"}, ValuesAll[string](err, "user_id"))
+	assert.Empty(t, ValuesAll[int](err, "missing"))
+	assert.Empty(t, ValuesAll[int](nil, "user_id"))
+}
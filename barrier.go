@@ -0,0 +1,51 @@
+package errx
+
+// barrierError stands in for a cause hidden by Barrier. It doesn't
+// unwrap, so errors.Is and errors.As stop at it.
+type barrierError struct {
+	msg string
+}
+
+func (e *barrierError) Error() string {
+	return e.msg
+}
+
+// Barrier returns an error safe to send across a trust boundary, such as
+// to a client or a partner service: it keeps err's frames, support ID,
+// public message, code and kind, but hides the cause behind an opaque
+// error whose message is the public message, or "internal error" if none
+// was set. errors.Is and errors.As stop at the barrier, so sentinel and
+// concrete types of the internal chain can't be matched, and neither
+// frame messages nor attributes, which may hold internal details, are
+// kept.
+// Returns nil if err is nil.
+func Barrier(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg, ok := Value[string](err, keyPublicMessage)
+	if !ok {
+		msg = defaultPublicMessage
+	}
+	barrier := &extendedError{err: &barrierError{msg: msg}}
+	for _, frame := range Frames(err) {
+		frame.Message = ""
+		frame.Args = ""
+		barrier.base = append(barrier.base, importFrame(frame))
+	}
+
+	if id := ID(err); id != "" {
+		barrier.attrs = append(barrier.attrs, Attr{Key: keyID, Value: id})
+	}
+	if ok {
+		barrier.attrs = append(barrier.attrs, Attr{Key: keyPublicMessage, Value: msg})
+	}
+	if code := CodeOf(err); code != "" {
+		barrier.attrs = append(barrier.attrs, Attr{Key: keyCode, Value: code})
+	}
+	if kind := KindOf(err); kind != "" {
+		barrier.attrs = append(barrier.attrs, Attr{Key: keyKind, Value: kind})
+	}
+	return barrier
+}
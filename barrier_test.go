@@ -0,0 +1,63 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarrier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Barrier(nil))
+	})
+
+	t.Run("hides the cause", func(t *testing.T) {
+		t.Parallel()
+
+		internal := Wrapf(fmt.Errorf("open config: %w", fs.ErrNotExist), "loading %s", "/etc/app/secret.yaml")
+		internal = WithCode(With(internal, "path", "/etc/app/secret.yaml"), "config.missing")
+		err := Barrier(internal)
+
+		assert.False(t, errors.Is(err, fs.ErrNotExist))
+		var pathErr *fs.PathError
+		assert.False(t, errors.As(err, &pathErr))
+		assert.NotContains(t, err.Error(), "secret.yaml")
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "secret.yaml")
+		assert.Equal(t, "internal error", errors.Unwrap(err).Error())
+
+		assert.Empty(t, Attrs(err))
+		assert.Equal(t, ID(internal), ID(err))
+		assert.Equal(t, Code("config.missing"), CodeOf(err))
+		assert.Equal(t, KindNotFound, KindOf(err))
+	})
+
+	t.Run("keeps the frames", func(t *testing.T) {
+		t.Parallel()
+
+		internal := Wrap(Wrap(errors.New("boom")))
+		err := Barrier(internal)
+
+		frames := Frames(err)
+		require.Len(t, frames, len(Frames(internal)))
+		assert.Equal(t, "github.com/alesr/errx.TestBarrier.func3", frames[0].Function)
+		assert.Equal(t, strings.TrimSuffix(internal.Error(), "boom")+"internal error", err.Error())
+	})
+
+	t.Run("uses the public message", func(t *testing.T) {
+		t.Parallel()
+
+		err := Barrier(WithPublicMessage(errors.New("no rows"), "user not found"))
+
+		assert.Equal(t, "user not found", errors.Unwrap(err).Error())
+		assert.Equal(t, "user not found (ref: "+ID(err)+")", PublicMessage(err))
+	})
+}
@@ -0,0 +1,83 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var benchErr = errors.New("boom")
+
+// recurse calls fn n levels deeper in the stack.
+func recurse(n int, fn func()) {
+	if n == 0 {
+		fn()
+		return
+	}
+	recurse(n-1, fn)
+}
+
+// BenchmarkCapture measures first wraps, where the same call sites are
+// captured over and over.
+func BenchmarkCapture(b *testing.B) {
+	b.Run("default depth", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = Wrap(benchErr)
+		}
+	})
+
+	b.Run("deep stack", func(b *testing.B) {
+		w := NewWrapper(Depth(32))
+
+		b.ReportAllocs()
+		recurse(40, func() {
+			for b.Loop() {
+				_ = w.Wrap(benchErr)
+			}
+		})
+	})
+}
+
+// BenchmarkRewrap measures wrapping an error that already holds frames,
+// as each function on the way up the stack does.
+func BenchmarkRewrap(b *testing.B) {
+	err := Wrap(benchErr)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = Wrap(err)
+	}
+}
+
+// BenchmarkError measures the compact rendering of a chain.
+func BenchmarkError(b *testing.B) {
+	err := Wrap(Wrapf(benchErr, "loading user %d", 42))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = err.Error()
+	}
+}
+
+// BenchmarkAppendError measures the compact rendering of a chain into a
+// reused buffer.
+func BenchmarkAppendError(b *testing.B) {
+	err := Wrap(Wrapf(benchErr, "loading user %d", 42))
+	buf := make([]byte, 0, 1024)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = AppendError(buf[:0], err)
+	}
+}
+
+// BenchmarkVerbose measures the %+v rendering of a chain.
+func BenchmarkVerbose(b *testing.B) {
+	err := With(Wrap(Wrapf(benchErr, "loading user %d", 42)), "user_id", 42)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = fmt.Sprintf("%+v", err)
+	}
+}
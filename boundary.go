@@ -0,0 +1,18 @@
+package errx
+
+// boundarySeparator is written by %+v between the sections of a chain.
+const boundarySeparator = "----"
+
+// Boundary wraps err like Wrap and marks the current frame as an
+// architectural boundary: the point where an error coming from a lower
+// layer enters the caller's, such as the service calling the repository
+// or the transport calling the service. Verbose output draws a separator
+// line below each boundary frame, so long chains read as one section per
+// layer; other formatters can use Frame.Boundary to the same effect.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func Boundary(err error) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+	return wrap(nil, err, 2, contextFrame{boundary: true})
+}
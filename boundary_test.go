@@ -0,0 +1,66 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Boundary(nil))
+	})
+
+	t.Run("separates sections", func(t *testing.T) {
+		t.Parallel()
+
+		repo := func() error {
+			return Wrap(errors.New("connection refused"))
+		}
+		service := func() error {
+			return Boundary(repo())
+		}
+		transport := func() error {
+			return Wrapf(Boundary(service()), "handling request")
+		}
+
+		err := transport()
+
+		frames := Frames(err)
+		require.GreaterOrEqual(t, len(frames), 4)
+		assert.False(t, frames[0].Boundary)
+		assert.True(t, frames[1].Boundary)
+		assert.True(t, frames[2].Boundary)
+		assert.False(t, frames[3].Boundary)
+
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		assert.Regexp(t, `^\[0\] errx\.TestBoundary\.func2\.3 .*: handling request: `, lines[0])
+		assert.Regexp(t, `^\[1\] errx\.TestBoundary\.func2\.3 `, lines[1])
+		assert.Equal(t, boundarySeparator, lines[2])
+		assert.Regexp(t, `^\[2\] errx\.TestBoundary\.func2\.2 `, lines[3])
+		assert.Equal(t, boundarySeparator, lines[4])
+		assert.Regexp(t, `^\[3\] errx\.TestBoundary\.func2\.1 `, lines[5])
+
+		// the compact form is unchanged
+		assert.NotContains(t, err.Error(), boundarySeparator)
+	})
+
+	t.Run("survives encoding", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Encode(Boundary(errors.New("boom")))
+		require.NoError(t, err)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		assert.True(t, Frames(decoded)[0].Boundary)
+	})
+}
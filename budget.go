@@ -0,0 +1,23 @@
+package errx
+
+import "time"
+
+const keyTruncated = "errx.truncated"
+
+// truncatedMarker ends the frames of truncated chains in %+v output.
+const truncatedMarker = "... frames truncated: capture budget exceeded"
+
+// CaptureBudget sets the time the Wrapper may spend resolving frames on
+// first wrap, see Config.CaptureBudget.
+func CaptureBudget(d time.Duration) Option {
+	return optionFunc(func(c *Config) {
+		c.CaptureBudget = d
+	})
+}
+
+// Truncated reports whether the stack scan of err's chain was cut short
+// by the capture budget, so frames below the last one are missing.
+func Truncated(err error) bool {
+	truncated, _ := Value[bool](err, keyTruncated)
+	return truncated
+}
@@ -0,0 +1,50 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops the scan past the budget", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(CaptureBudget(time.Nanosecond))
+		err := func() error {
+			return w.Wrap(errors.New("slow path"))
+		}()
+
+		assert.True(t, Truncated(err))
+		frames := Frames(err)
+		require.NotEmpty(t, frames, "the wrap site is always recorded")
+		assert.Less(t, len(frames), 3)
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\n"+truncatedMarker+"\n")
+	})
+
+	t.Run("untouched within the budget", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper(CaptureBudget(time.Minute)).Wrap(errors.New("slow path"))
+
+		assert.False(t, Truncated(err))
+		assert.NotContains(t, fmt.Sprintf("%+v", err), truncatedMarker)
+	})
+
+	t.Run("survives encoding", func(t *testing.T) {
+		t.Parallel()
+
+		r := Record{Message: "slow path", Truncated: true}
+		data, err := json.Marshal(r)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"truncated":true`)
+		assert.True(t, Truncated(r.Err()))
+	})
+}
@@ -0,0 +1,125 @@
+//go:build !tinygo
+
+package errx
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// callSite is a resolved stack frame.
+type callSite struct {
+	funcName string
+	file     string
+	line     int
+}
+
+// callSites interns resolved call sites by program counter, so capturing
+// the same sites again costs a map lookup instead of decoding the symbol
+// tables, and every capture shares the same name strings. Programs have a
+// bounded number of call sites, so the cache is never pruned.
+var (
+	callSitesMu sync.RWMutex
+	callSites   = make(map[uintptr]callSite)
+	// runtimeFrames keeps the runtime frame each call site was first
+	// resolved from, for Frame.Runtime
+	runtimeFrames = make(map[callSite]runtime.Frame)
+)
+
+// caller resolves the function, file and line of the frame skip levels
+// above its own caller. It reports false when the runtime can't.
+func caller(skip int) (funcName, file string, line int, ok bool) {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) == 0 {
+		return "", "", 0, false
+	}
+
+	site, ok := resolve(pc[0])
+	return site.funcName, site.file, site.line, ok
+}
+
+// callers resolves the frames starting skip levels above its own caller
+// into sites, walking the stack once. It returns how many it resolved,
+// and whether it stopped early because resolving a frame the cache
+// didn't hold found the deadline past. A zero deadline means none.
+func callers(skip int, sites []callSite, deadline time.Time) (int, bool) {
+	var buf [32]uintptr
+	pcs := buf[:]
+	if len(sites) > len(buf) {
+		pcs = make([]uintptr, len(sites))
+	}
+
+	n := runtime.Callers(skip+2, pcs[:len(sites)])
+	for i, pc := range pcs[:n] {
+		site, ok := cachedSite(pc)
+		if !ok {
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return i, true
+			}
+			site, ok = resolve(pc)
+		}
+		if !ok {
+			return i, false
+		}
+		sites[i] = site
+	}
+	return n, false
+}
+
+// cachedSite returns the call site at pc if it was resolved before.
+func cachedSite(pc uintptr) (callSite, bool) {
+	callSitesMu.RLock()
+	site, ok := callSites[pc]
+	callSitesMu.RUnlock()
+	return site, ok
+}
+
+// resolve returns the call site at pc, as recorded by runtime.Callers.
+func resolve(pc uintptr) (callSite, bool) {
+	if site, ok := cachedSite(pc); ok {
+		return site, true
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" {
+		return callSite{}, false
+	}
+	site := callSite{funcName: frame.Function, file: frame.File, line: frame.Line}
+
+	callSitesMu.Lock()
+	callSites[pc] = site
+	if _, ok := runtimeFrames[site]; !ok {
+		runtimeFrames[site] = frame
+	}
+	callSitesMu.Unlock()
+	return site, true
+}
+
+// runtimeFrame returns the runtime frame site was first resolved from,
+// if this process resolved it.
+func runtimeFrame(site callSite) (runtime.Frame, bool) {
+	callSitesMu.RLock()
+	frame, ok := runtimeFrames[site]
+	callSitesMu.RUnlock()
+	return frame, ok
+}
+
+// sitesAt resolves pcs, as recorded by runtime.Callers, into call sites,
+// stopping at the first one the runtime can't resolve.
+func sitesAt(pcs []uintptr) []callSite {
+	sites := make([]callSite, 0, len(pcs))
+	for _, pc := range pcs {
+		site, ok := resolve(pc)
+		if !ok {
+			break
+		}
+		sites = append(sites, site)
+	}
+	return sites
+}
+
+// absoluteTime renders t for TimeAbsolute, in t's own location.
+func absoluteTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
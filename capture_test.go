@@ -0,0 +1,99 @@
+//go:build !tinygo
+
+package errx
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller(t *testing.T) {
+	t.Parallel()
+
+	funcName, file, line, ok := caller(0)
+	_, expectedFile, expectedLine, _ := runtime.Caller(0)
+
+	require.True(t, ok)
+	assert.Equal(t, "github.com/alesr/errx.TestCaller", funcName)
+	assert.Equal(t, expectedFile, file)
+	assert.Equal(t, expectedLine-1, line)
+
+	_, _, _, ok = caller(1000)
+	assert.False(t, ok)
+}
+
+func TestCallers(t *testing.T) {
+	t.Parallel()
+
+	sites := make([]callSite, 3)
+	n, truncated := callers(0, sites, time.Time{})
+	require.Equal(t, 3, n)
+	assert.False(t, truncated)
+
+	assert.Equal(t, "github.com/alesr/errx.TestCallers", sites[0].funcName)
+
+	// above this function, the sites match the ones resolved one by one
+	for i, site := range sites[1:] {
+		funcName, file, line, ok := caller(i + 1)
+		require.True(t, ok)
+		assert.Equal(t, callSite{funcName: funcName, file: file, line: line}, site)
+	}
+
+	n, _ = callers(1000, sites, time.Time{})
+	assert.Zero(t, n)
+
+	t.Run("past the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		// the closure's frame was never resolved, so resolving it is
+		// already over budget
+		func() {
+			n, truncated = callers(0, sites, time.Now().Add(-time.Second))
+		}()
+		assert.Zero(t, n)
+		assert.True(t, truncated)
+	})
+}
+
+func TestInterning(t *testing.T) {
+	t.Parallel()
+
+	wrapHere := func() error {
+		return Wrap(errors.New("boom"))
+	}
+
+	first := wrapHere().(*extendedError)
+	second := wrapHere().(*extendedError)
+
+	// the same call sites share their name storage
+	require.Equal(t, first.base[0].funcName, second.base[0].funcName)
+	assert.Equal(t, unsafe.StringData(first.base[0].funcName), unsafe.StringData(second.base[0].funcName))
+	assert.Equal(t, unsafe.StringData(first.base[0].file), unsafe.StringData(second.base[0].file))
+}
+
+// BenchmarkResolve compares resolving a call site through the interning
+// cache with asking the runtime every time.
+func BenchmarkResolve(b *testing.B) {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+
+	b.Run("interned", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = resolve(pcs[0])
+		}
+	})
+
+	b.Run("runtime", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = runtime.CallersFrames(pcs[:]).Next()
+		}
+	})
+}
@@ -0,0 +1,91 @@
+//go:build tinygo
+
+package errx
+
+import (
+	"runtime"
+	"time"
+)
+
+// callSite is a resolved stack frame.
+type callSite struct {
+	funcName string
+	file     string
+	line     int
+}
+
+// caller resolves what the restricted runtime knows about the frame skip
+// levels above its own caller. TinyGo and some WASM targets can't walk the
+// stack or name functions, so any part may be missing. It never panics,
+// and reports false when nothing could be resolved.
+func caller(skip int) (funcName, file string, line int, ok bool) {
+	defer func() {
+		if recover() != nil {
+			funcName, file, line, ok = "", "", 0, false
+		}
+	}()
+
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "", "", 0, false
+	}
+
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		funcName = fn.Name()
+	}
+	if funcName == "" && file == "" {
+		return "", "", 0, false
+	}
+	return funcName, file, line, true
+}
+
+// callers resolves the frames starting skip levels above its own caller
+// into sites, one at a time. It returns how many it resolved, and whether
+// it stopped early because the deadline was past. A zero deadline means
+// none.
+func callers(skip int, sites []callSite, deadline time.Time) (int, bool) {
+	for i := range sites {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return i, true
+		}
+		funcName, file, line, ok := caller(skip + 1 + i)
+		if !ok {
+			return i, false
+		}
+		sites[i] = callSite{funcName: funcName, file: file, line: line}
+	}
+	return len(sites), false
+}
+
+// sitesAt resolves what the restricted runtime knows about pcs, as
+// recorded by runtime.Callers, stopping at the first one it can't.
+func sitesAt(pcs []uintptr) (sites []callSite) {
+	defer func() {
+		recover()
+	}()
+
+	for _, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			break
+		}
+		file, line := fn.FileLine(pc - 1)
+		sites = append(sites, callSite{funcName: fn.Name(), file: file, line: line})
+	}
+	return sites
+}
+
+// runtimeFrame reports false: the restricted runtime resolves frames
+// without keeping them.
+func runtimeFrame(callSite) (runtime.Frame, bool) {
+	return runtime.Frame{}, false
+}
+
+// absoluteTime renders t for TimeAbsolute in UTC. Restricted runtimes
+// often ship without time zone data, and some report the Unix epoch
+// when the host gives them no clock: such times render as
+// 1970-01-01T00:00:00Z, and the TimeAgo and TimeElapsed durations
+// measured from them are meaningless.
+func absoluteTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,19 @@
+package errx
+
+import "fmt"
+
+// CauseType returns the Go type of the innermost error of err's chain,
+// e.g. "*net.OpError" or "*errors.errorString", which error trackers
+// group by and which is lost once the error is rendered as a string.
+// Errors rebuilt from a record report the type of the original cause.
+// Returns an empty string if err is nil.
+func CauseType(err error) string {
+	root := Root(err)
+	if root == nil {
+		return ""
+	}
+	if remote, ok := root.(*remoteError); ok && remote.typ != "" {
+		return remote.typ
+	}
+	return fmt.Sprintf("%T", root)
+}
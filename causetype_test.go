@@ -0,0 +1,43 @@
+package errx
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitError is a cause with a type of its own that doesn't unwrap.
+type limitError struct{ volume string }
+
+func (e *limitError) Error() string { return "limit reached on " + e.volume }
+
+func TestCauseType(t *testing.T) {
+	t.Parallel()
+
+	t.Run("type of the root cause", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(fmt.Errorf("saving: %w", Wrap(&limitError{volume: "data"})))
+		assert.Equal(t, "*errx.limitError", CauseType(err))
+
+		// the root is the innermost error, not the first typed one
+		pathErr := &fs.PathError{Op: "open", Path: "config.yaml", Err: fs.ErrNotExist}
+		assert.Equal(t, "*errors.errorString", CauseType(Wrap(pathErr)))
+		assert.Empty(t, CauseType(nil))
+	})
+
+	t.Run("kept by records", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Encode(Wrap(&limitError{volume: "data"}))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"cause_type":"*errx.limitError"`)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, "*errx.limitError", CauseType(decoded))
+	})
+}
@@ -0,0 +1,111 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DebugEnv is the environment variable that makes Fatal print the verbose
+// %+v rendering of errors instead of the compact one, when set to a
+// non-empty value other than "0".
+const DebugEnv = "ERRX_DEBUG"
+
+// Exit statuses used by Fatal when no code-specific status is registered.
+const (
+	ExitFailure  = 1
+	ExitUsage    = 2
+	ExitCanceled = 130
+)
+
+// stderr and exit are replaced in tests.
+var (
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
+)
+
+var (
+	exitCodesMu sync.RWMutex
+	exitCodes   = make(map[Code]int)
+)
+
+// RegisterExitCode makes Fatal exit with status for errors carrying code.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterExitCode(code Code, status int) {
+	exitCodesMu.Lock()
+	defer exitCodesMu.Unlock()
+	exitCodes[code] = status
+}
+
+// ExitCode returns the process exit status for err: 0 for nil, the status
+// registered for its code, ExitCanceled for cancellations (context.Canceled
+// or KindCanceled), ExitUsage for KindInvalid, and ExitFailure otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	exitCodesMu.RLock()
+	status, ok := exitCodes[CodeOf(err)]
+	exitCodesMu.RUnlock()
+	if ok {
+		return status
+	}
+
+	switch {
+	case canceled(err):
+		return ExitCanceled
+	case KindOf(err) == KindInvalid:
+		return ExitUsage
+	default:
+		return ExitFailure
+	}
+}
+
+// Main runs a command-line program: it calls run and passes the error it
+// returns, if any, to Fatal.
+//
+//	func main() {
+//		errx.Main(run)
+//	}
+func Main(run func() error) {
+	Fatal(run())
+}
+
+// Fatal prints err to stderr and exits with ExitCode(err). The compact
+// rendering is printed, followed by the hint if err has one, or the
+// verbose one, which lists the hint already, when DebugEnv is set.
+// Cancellations, such as a command interrupted with Ctrl+C, exit without
+// printing anything outside of debug mode.
+// It does nothing if err is nil.
+func Fatal(err error) {
+	if err == nil {
+		return
+	}
+
+	// the verbose rendering of errx errors ends with the hint
+	_, listsHint := err.(*extendedError)
+	debug := os.Getenv(DebugEnv)
+	switch {
+	case debug != "" && debug != "0":
+		fmt.Fprintf(stderr, "error: %+v", err)
+	case canceled(err):
+		exit(ExitCode(err))
+		return
+	default:
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		listsHint = false
+	}
+
+	if hint := Hint(err); hint != "" && !listsHint {
+		fmt.Fprintf(stderr, "hint: %s\n", hint)
+	}
+	exit(ExitCode(err))
+}
+
+// canceled reports whether err means the work was called off.
+func canceled(err error) bool {
+	return is(err, context.Canceled) || KindOf(err) == KindCanceled
+}
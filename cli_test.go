@@ -0,0 +1,144 @@
+package errx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureExit redirects Fatal's output and exit calls for the duration of
+// the test. Tests using it must not run in parallel.
+func captureExit(t *testing.T) (*bytes.Buffer, *int) {
+	t.Helper()
+
+	var (
+		buf    bytes.Buffer
+		status = -1
+	)
+	prevStderr, prevExit := stderr, exit
+	stderr, exit = &buf, func(code int) { status = code }
+	t.Cleanup(func() { stderr, exit = prevStderr, prevExit })
+	return &buf, &status
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	// registrations are global, so the code is unique to this test
+	RegisterExitCode("cli_test.locked", 75)
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "nil", err: nil, expected: 0},
+		{name: "plain error", err: errors.New("boom"), expected: ExitFailure},
+		{name: "context canceled", err: Wrap(fmt.Errorf("copying: %w", context.Canceled)), expected: ExitCanceled},
+		{name: "canceled kind", err: WithKind(errors.New("interrupted"), KindCanceled), expected: ExitCanceled},
+		{name: "invalid kind", err: WithKind(errors.New("unknown flag"), KindInvalid), expected: ExitUsage},
+		{name: "registered code", err: WithCode(WithKind(errors.New("locked"), KindInvalid), "cli_test.locked"), expected: 75},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, ExitCode(tc.err))
+		})
+	}
+}
+
+func TestFatal(t *testing.T) {
+	t.Run("nil input", func(t *testing.T) {
+		buf, status := captureExit(t)
+
+		Fatal(nil)
+
+		assert.Empty(t, buf.String())
+		assert.Equal(t, -1, *status)
+	})
+
+	t.Run("compact message and hint", func(t *testing.T) {
+		buf, status := captureExit(t)
+		t.Setenv(DebugEnv, "")
+
+		err := WithHint(WithKind(errors.New("unknown flag --frce"), KindInvalid), "did you mean --force?")
+		Fatal(err)
+
+		assert.Equal(t, "error: "+err.Error()+"\nhint: did you mean --force?\n", buf.String())
+		assert.Equal(t, ExitUsage, *status)
+	})
+
+	t.Run("verbose in debug mode", func(t *testing.T) {
+		buf, status := captureExit(t)
+		t.Setenv(DebugEnv, "1")
+
+		err := Wrap(errors.New("boom"))
+		Fatal(err)
+
+		assert.Equal(t, fmt.Sprintf("error: %+v", err), buf.String())
+		assert.Equal(t, ExitFailure, *status)
+	})
+
+	t.Run("hint listed once in debug mode", func(t *testing.T) {
+		buf, _ := captureExit(t)
+		t.Setenv(DebugEnv, "1")
+
+		err := WithHint(Wrap(errors.New("boom")), "try x")
+		Fatal(err)
+		assert.Equal(t, fmt.Sprintf("error: %+v", err), buf.String())
+		assert.Equal(t, 1, strings.Count(buf.String(), "hint: try x"))
+
+		// errors wrapped by other packages don't render it
+		buf.Reset()
+		Fatal(fmt.Errorf("running: %w", err))
+		assert.Equal(t, 1, strings.Count(buf.String(), "hint: try x"))
+	})
+
+	t.Run("debug disabled with 0", func(t *testing.T) {
+		buf, _ := captureExit(t)
+		t.Setenv(DebugEnv, "0")
+
+		err := Wrap(errors.New("boom"))
+		Fatal(err)
+
+		assert.Equal(t, "error: "+err.Error()+"\n", buf.String())
+	})
+
+	t.Run("cancellation is quiet", func(t *testing.T) {
+		buf, status := captureExit(t)
+		t.Setenv(DebugEnv, "")
+
+		Fatal(Wrap(context.Canceled))
+
+		assert.Empty(t, buf.String())
+		assert.Equal(t, ExitCanceled, *status)
+	})
+}
+
+func TestMainRun(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		buf, status := captureExit(t)
+
+		Main(func() error { return nil })
+
+		assert.Empty(t, buf.String())
+		assert.Equal(t, -1, *status)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		buf, status := captureExit(t)
+		t.Setenv(DebugEnv, "")
+
+		Main(func() error { return errors.New("boom") })
+
+		require.Equal(t, "error: boom\n", buf.String())
+		assert.Equal(t, ExitFailure, *status)
+	})
+}
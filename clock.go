@@ -0,0 +1,75 @@
+package errx
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TimeSource sets the function the Wrapper reads capture times from,
+// see Config.Clock. Services wrapping at high rates can pass the Now
+// method of a CoarseClock, trading precision for a cheaper read.
+func TimeSource(now func() time.Time) Option {
+	return optionFunc(func(c *Config) {
+		c.Clock = now
+	})
+}
+
+// CoarseClock is a time source that reads a time cached by a background
+// goroutine instead of the system clock, so its times lag behind by up to
+// the resolution it was created with:
+//
+//	clock := errx.NewCoarseClock(time.Millisecond)
+//	defer clock.Stop()
+//	wrapper := errx.NewWrapper(errx.TimeSource(clock.Now))
+//
+// A CoarseClock is safe for concurrent use.
+type CoarseClock struct {
+	now  atomic.Pointer[time.Time]
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewCoarseClock returns a CoarseClock updated every resolution, or
+// every millisecond if resolution is not positive. Call Stop to release
+// its goroutine.
+func NewCoarseClock(resolution time.Duration) *CoarseClock {
+	if resolution <= 0 {
+		resolution = time.Millisecond
+	}
+
+	c := &CoarseClock{stop: make(chan struct{}), done: make(chan struct{})}
+	now := time.Now()
+	c.now.Store(&now)
+
+	ticker := time.NewTicker(resolution)
+	go func() {
+		defer close(c.done)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				c.now.Store(&t)
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// Now returns the cached time. After Stop, it keeps returning the last
+// time cached.
+func (c *CoarseClock) Now() time.Time {
+	return *c.now.Load()
+}
+
+// Stop stops updating the cached time, returning once the goroutine has
+// exited. It is safe to call more than once.
+func (c *CoarseClock) Stop() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+}
@@ -0,0 +1,56 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("per wrapper", func(t *testing.T) {
+		t.Parallel()
+
+		at := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+		w := NewWrapper(TimeSource(func() time.Time { return at }))
+
+		frames := Frames(w.Wrap(errors.New("boom")))
+		require.NotEmpty(t, frames)
+		for _, frame := range frames {
+			assert.Equal(t, at, frame.Time)
+		}
+		assert.NotEqual(t, at, Frames(Wrap(errors.New("boom")))[0].Time)
+	})
+
+	t.Run("coarse clock", func(t *testing.T) {
+		t.Parallel()
+
+		clock := NewCoarseClock(time.Millisecond)
+		defer clock.Stop()
+
+		first := clock.Now()
+		assert.WithinDuration(t, time.Now(), first, time.Second)
+		assert.Eventually(t, func() bool {
+			return clock.Now().After(first)
+		}, time.Second, time.Millisecond)
+
+		frames := Frames(NewWrapper(TimeSource(clock.Now)).Wrap(errors.New("boom")))
+		assert.WithinDuration(t, time.Now(), frames[0].Time, time.Second)
+	})
+
+	t.Run("stopped coarse clock", func(t *testing.T) {
+		t.Parallel()
+
+		clock := NewCoarseClock(0)
+		clock.Stop()
+		clock.Stop()
+
+		last := clock.Now()
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, last, clock.Now())
+	})
+}
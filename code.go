@@ -0,0 +1,62 @@
+package errx
+
+const (
+	keyCode = "errx.code"
+	keyKind = "errx.kind"
+)
+
+// Code is an application-defined identifier for a specific failure,
+// e.g. "billing.invoice.not_found". Codes are meant to be declared as
+// constants and matched by callers, alerting rules and documentation.
+type Code string
+
+// Kind classifies a failure by how callers should react to it,
+// independently of where it happened.
+type Kind string
+
+// Kinds shared by most services. Applications may declare their own.
+const (
+	KindInternal         Kind = "internal"
+	KindInvalid          Kind = "invalid"
+	KindNotFound         Kind = "not_found"
+	KindConflict         Kind = "conflict"
+	KindPermissionDenied Kind = "permission_denied"
+	KindUnauthenticated  Kind = "unauthenticated"
+	KindUnavailable      Kind = "unavailable"
+	KindTimeout          Kind = "timeout"
+	KindCanceled         Kind = "canceled"
+)
+
+// WithCode sets err's code. If err is not an errx error yet it is
+// wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyCode, Value: code}, 3)
+}
+
+// CodeOf returns the code set on err with WithCode, or an empty Code.
+// When set more than once, the outermost code wins.
+func CodeOf(err error) Code {
+	code, _ := Value[Code](err, keyCode)
+	return code
+}
+
+// WithKind sets err's kind. If err is not an errx error yet it is
+// wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyKind, Value: kind}, 3)
+}
+
+// KindOf returns the kind set on err with WithKind, or an empty Kind.
+// When set more than once, the outermost kind wins.
+func KindOf(err error) Kind {
+	kind, _ := Value[Kind](err, keyKind)
+	return kind
+}
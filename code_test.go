@@ -0,0 +1,62 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithCode(nil, "users.not_found"))
+		assert.Empty(t, CodeOf(nil))
+	})
+
+	t.Run("preserved through wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithCode(errors.New("no rows"), "users.not_found")
+		err = Wrap(fmt.Errorf("loading user: %w", err))
+
+		assert.Equal(t, Code("users.not_found"), CodeOf(err))
+		assert.Empty(t, Attrs(err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\ncode: users.not_found\n")
+	})
+
+	t.Run("outermost wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithCode(errors.New("no rows"), "db.no_rows")
+		err = WithCode(err, "users.not_found")
+
+		assert.Equal(t, Code("users.not_found"), CodeOf(err))
+	})
+}
+
+func TestKind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithKind(nil, KindNotFound))
+		assert.Empty(t, KindOf(nil))
+		assert.Empty(t, KindOf(errors.New("plain")))
+	})
+
+	t.Run("preserved through wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithKind(errors.New("no rows"), KindNotFound)
+		err = Wrap(err)
+
+		assert.Equal(t, KindNotFound, KindOf(err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nkind: not_found\n")
+	})
+}
@@ -0,0 +1,59 @@
+package errx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// maxCompactSize bounds how much DecodeCompact is willing to inflate.
+const maxCompactSize = 1 << 20
+
+// EncodeCompact serializes err's chain like Encode, gzipped and encoded
+// as unpadded URL-safe base64, for size-limited places such as message
+// queue headers, database columns or support tickets.
+// Returns an empty string if err is nil.
+func EncodeCompact(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+
+	data, encErr := Encode(err)
+	if encErr != nil {
+		return "", encErr
+	}
+
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if _, err := zw.Write(data); err != nil {
+		return "", fmt.Errorf("could not compress error record: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("could not compress error record: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeCompact rebuilds an error from a string produced by EncodeCompact.
+// See Record.Err for how the result relates to the original.
+func DecodeCompact(s string) (error, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode compact error record: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress error record: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(zr, maxCompactSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress error record: %w", err)
+	}
+	if len(data) > maxCompactSize {
+		return nil, fmt.Errorf("could not decompress error record: larger than %d bytes", maxCompactSize)
+	}
+	return Decode(data)
+}
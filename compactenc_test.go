@@ -0,0 +1,70 @@
+package errx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCompact(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := EncodeCompact(nil)
+		require.NoError(t, err)
+		assert.Empty(t, s)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		original := WithCode(With(errors.New("connection refused"), "host", "db-1"), "users.db_unavailable")
+		original = Wrapf(original, "loading user %d", 42)
+
+		s, err := EncodeCompact(original)
+		require.NoError(t, err)
+		assert.NotContains(t, s, "=", "unpadded")
+
+		data, err := Encode(original)
+		require.NoError(t, err)
+		assert.Less(t, len(s), len(data), "smaller than JSON")
+
+		decoded, err := DecodeCompact(s)
+		require.NoError(t, err)
+		assert.Equal(t, original.Error(), decoded.Error())
+		assert.Equal(t, ID(original), ID(decoded))
+		assert.Equal(t, Code("users.db_unavailable"), CodeOf(decoded))
+	})
+
+	t.Run("malformed input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeCompact("not base64!")
+		assert.ErrorContains(t, err, "could not decode compact error record")
+
+		_, err = DecodeCompact(base64.RawURLEncoding.EncodeToString([]byte("not gzip")))
+		assert.ErrorContains(t, err, "could not decompress error record")
+	})
+
+	t.Run("oversized input", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := fmt.Fprintf(zw, `{"message":"%s"}`, strings.Repeat("a", maxCompactSize))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		_, err = DecodeCompact(base64.RawURLEncoding.EncodeToString(buf.Bytes()))
+		assert.ErrorContains(t, err, "larger than")
+	})
+}
@@ -0,0 +1,221 @@
+package errx
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds the package-wide settings used when wrapping errors.
+// The zero value is the default configuration.
+type Config struct {
+	// Ignore lists expected control-flow errors, such as io.EOF or
+	// context.Canceled, that Wrap returns untouched. Errors are matched
+	// with errors.Is, so wrapped occurrences are ignored too.
+	Ignore []error
+
+	// Deterministic makes rendered output stable for examples and golden
+	// tests: line numbers are printed as NN, frames found by scanning the
+	// stack are left out so only wrap sites remain, and new errors get the
+	// support ID 0000-0000 and the capture time of the Unix epoch.
+	Deterministic bool
+
+	// Quiet makes Error, and so %v, show only the outermost frame
+	// followed by the cause, e.g. "svc.Process (svc.go:42): connection
+	// refused", leaving the full chain to %+v. Errors nested with
+	// fmt.Errorf each show their own outermost frame.
+	Quiet bool
+
+	// Token prefixes Error, and so %v, with the code and severity of
+	// errors that have either set, e.g. "[billing.invoice.not_found:
+	// critical] ", for rules matching plain-text logs. The severity
+	// defaults to error like SeverityOf, the code is left empty when not
+	// set. Errors nested with fmt.Errorf each show their own token.
+	Token bool
+
+	// HeadFrames and TailFrames make Error and %+v show only the first
+	// HeadFrames and last TailFrames frames of chains holding more than
+	// both together, with "… k frames omitted …" in their place, for
+	// alert bodies or chat messages where a long dump gets in the way but
+	// both ends matter. Frames keep their indices in %+v output. Zero
+	// values show every frame.
+	HeadFrames int
+	TailFrames int
+
+	// Clock returns the time recorded on captured frames, such as the
+	// Now method of a CoarseClock. Defaults to time.Now.
+	Clock func() time.Time
+
+	// TimeFormat selects how %+v output shows when each frame was
+	// captured. Times are hidden by default and in deterministic mode.
+	TimeFormat TimeFormat
+
+	// NormalizeSkew offsets the capture times shown for frames received
+	// from other services, so each remote run ends at the time of the
+	// local frame that received it. Without it, frames whose times go
+	// backwards are shown as they are, followed by a warning.
+	NormalizeSkew bool
+
+	// CaptureFields selects the frame fields recorded when wrapping, e.g.
+	// FieldFunction|FieldLine to keep file paths out of errors
+	// altogether. Function names are always recorded. Zero means all.
+	CaptureFields FrameFields
+
+	// ErrorFields selects the frame fields Error renders. Zero means the
+	// function, file and line; with FieldTime, capture times follow as in
+	// %+v, as timestamps unless TimeFormat is TimeAgo.
+	ErrorFields FrameFields
+
+	// VerboseFields selects the frame fields %+v renders. Zero means all
+	// of them, times still depending on TimeFormat.
+	VerboseFields FrameFields
+
+	// RecordFields selects the frame fields NewRecord and Encode keep.
+	// Zero means all.
+	RecordFields FrameFields
+
+	// Depth is the maximum number of frames captured on first wrap.
+	// Defaults to 10.
+	Depth int
+
+	// CaptureBudget bounds the time spent resolving the frames scanned
+	// on first wrap, for request paths where the tail latency of errors
+	// matters. Deep stacks in large binaries can take a while to resolve
+	// the first time; past the budget, the scan stops and the chain is
+	// marked as truncated, see Truncated. Frames already resolved once
+	// are cached and don't count. Zero disables the budget.
+	CaptureBudget time.Duration
+
+	// DefaultAttrs are attached to every error when it is first wrapped,
+	// or when it is wrapped by a Wrapper other than the one that wrapped
+	// it last.
+	DefaultAttrs []Attr
+
+	// AttrPolicy selects the value Attrs and Value return for attribute
+	// keys set at more than one layer of a chain. The outermost value
+	// wins by default.
+	AttrPolicy AttrPolicy
+
+	// Runtime attaches a snapshot of the process, such as the Go version
+	// and the number of goroutines, to errors on first wrap. It shows in
+	// %+v output and serialized records, see RuntimeOf.
+	Runtime bool
+
+	// RuntimeEnv lists the environment variables recorded in runtime
+	// snapshots. Only the listed ones are read, keeping secrets out.
+	RuntimeEnv []string
+
+	// MemStats attaches the heap size and GC count at the time of the
+	// first wrap to errors, as the AttrHeapAlloc and AttrNumGC attributes,
+	// for spotting errors that correlate with memory pressure.
+	MemStats bool
+
+	// HistoryFrames records the call setting each code, kind, severity
+	// and public message, as the Frame of the changes History lists. It
+	// costs a stack lookup per call setting one.
+	HistoryFrames bool
+
+	// Reporter receives the errors passed to Report.
+	Reporter Reporter
+
+	// Translator provides the messages returned by LocalizedMessage.
+	Translator Translator
+
+	// SizeBudget is the number of bytes, as estimated by SizeOf, that a
+	// wrap may leave an error holding. Past it, the frames found by
+	// scanning the stack are dropped, then the wrap sites in the middle
+	// of the chain, keeping the newest one and the origin.
+	// Zero disables the budget.
+	SizeBudget int
+
+	// Debug enables checks for misuse, reported as selected: a wrap
+	// call wrapping an error it already wrapped, in a loop for instance,
+	// or a function wrapping an error again right after wrapping it.
+	// Recursive functions that wrap at every level are flagged too.
+	// Wrapping a non-nil error holding a nil pointer, a typed nil, is
+	// flagged as well, and returns nil when the mode doesn't panic.
+	Debug DebugMode
+
+	// LeakCheck logs a warning through slog.Default, with the origin
+	// frame, for every error chain garbage collected without having been
+	// formatted, reported or marked with Handled: errors silently
+	// swallowed somewhere. It costs a cleanup registration per chain and
+	// is meant for debug builds.
+	LeakCheck bool
+
+	// TrimBelow lists functions where the stack scan done on first wrap
+	// stops. The matching frame and everything that called it are left
+	// out, e.g. "testing.tRunner" or an HTTP router's entrypoint.
+	// Functions match by full name ("net/http.HandlerFunc.ServeHTTP")
+	// or by the shortened one ("http.HandlerFunc.ServeHTTP").
+	TrimBelow []string
+
+	// TrimAbove lists functions such as shared helpers that wrap on behalf
+	// of their callers. On first wrap, the matching frame and everything it
+	// called are left out, and its caller takes the place of the wrap site.
+	// Functions match like in TrimBelow.
+	TrimAbove []string
+}
+
+var globalConfig atomic.Pointer[Config]
+
+// SetConfig replaces the package-wide configuration.
+// It is safe for concurrent use, but is meant to be called once at startup.
+func SetConfig(c Config) {
+	c = c.clone()
+	globalConfig.Store(&c)
+}
+
+// CurrentConfig returns a copy of the package-wide configuration.
+func CurrentConfig() Config {
+	return loadConfig().clone()
+}
+
+// loadConfig returns the active configuration without copying it.
+// Callers must not modify the result.
+func loadConfig() *Config {
+	if c := globalConfig.Load(); c != nil {
+		return c
+	}
+	return &Config{}
+}
+
+// clone returns a copy of c that shares no slices with it.
+func (c Config) clone() Config {
+	c.Ignore = slices.Clone(c.Ignore)
+	c.TrimBelow = slices.Clone(c.TrimBelow)
+	c.TrimAbove = slices.Clone(c.TrimAbove)
+	c.DefaultAttrs = slices.Clone(c.DefaultAttrs)
+	c.RuntimeEnv = slices.Clone(c.RuntimeEnv)
+	return c
+}
+
+// depth returns the number of frames to capture on first wrap.
+func (c *Config) depth() int {
+	if c.Depth > 0 {
+		return c.Depth
+	}
+	return maxDepth
+}
+
+// now returns the capture time for new frames.
+func (c *Config) now() time.Time {
+	switch {
+	case c.Deterministic:
+		return time.Unix(0, 0).UTC()
+	case c.Clock != nil:
+		return c.Clock()
+	default:
+		return time.Now()
+	}
+}
+
+// ignored reports whether err matches the configured ignore list.
+func (c *Config) ignored(err error) bool {
+	for _, target := range c.Ignore {
+		if is(err, target) {
+			return true
+		}
+	}
+	return false
+}
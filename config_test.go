@@ -0,0 +1,183 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("zero value by default", func(t *testing.T) {
+		assert.Equal(t, Config{}, CurrentConfig())
+	})
+
+	t.Run("set and read back", func(t *testing.T) {
+		WithTestConfig(t, Config{Ignore: []error{io.EOF}})
+
+		assert.Equal(t, []error{io.EOF}, CurrentConfig().Ignore)
+	})
+
+	t.Run("copies are isolated", func(t *testing.T) {
+		ignore := []error{io.EOF}
+		WithTestConfig(t, Config{Ignore: ignore})
+
+		ignore[0] = context.Canceled
+		got := CurrentConfig()
+		got.Ignore[0] = context.DeadlineExceeded
+
+		assert.Equal(t, []error{io.EOF}, CurrentConfig().Ignore)
+	})
+}
+
+func TestWithTestConfig(t *testing.T) {
+	t.Run("restores the previous configuration", func(t *testing.T) {
+		WithTestConfig(t, Config{Depth: 3})
+
+		t.Run("nested", func(t *testing.T) {
+			WithTestConfig(t, Config{Depth: 5})
+			assert.Equal(t, 5, CurrentConfig().Depth)
+		})
+		assert.Equal(t, 3, CurrentConfig().Depth)
+	})
+
+	t.Run("restored", func(t *testing.T) {
+		assert.Equal(t, Config{}, CurrentConfig())
+	})
+
+	t.Run("refuses parallel tests", func(t *testing.T) {
+		t.Run("parallel", func(t *testing.T) {
+			t.Parallel()
+
+			assert.Panics(t, func() { WithTestConfig(t, Config{Depth: 3}) })
+			assert.Equal(t, Config{}, CurrentConfig())
+		})
+	})
+}
+
+func TestIgnore(t *testing.T) {
+	WithTestConfig(t, Config{Ignore: []error{io.EOF, context.Canceled}})
+
+	testCases := []struct {
+		name    string
+		err     error
+		ignored bool
+	}{
+		{"listed error", io.EOF, true},
+		{"wrapped listed error", fmt.Errorf("reading: %w", context.Canceled), true},
+		{"other error", errors.New("boom"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Wrap(tc.err)
+			if tc.ignored {
+				assert.Equal(t, tc.err, err)
+				assert.Equal(t, tc.err, WrapIf(true, tc.err))
+				return
+			}
+			assert.NotEqual(t, tc.err, err)
+			assert.True(t, errors.Is(err, tc.err))
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	WithTestConfig(t, Config{Deterministic: true})
+
+	inner := func() error {
+		return Wrap(errors.New("boom"))
+	}
+
+	err := Wrap(inner())
+
+	assert.Equal(t,
+		"errx.TestDeterministic (config_test.go:NN): errx.TestDeterministic.func1 (config_test.go:NN): boom",
+		err.Error(),
+	)
+	assert.Equal(t,
+		"[0] errx.TestDeterministic (config_test.go:NN): boom\n"+
+			"[1] errx.TestDeterministic.func1 (config_test.go:NN): boom\n"+
+			"id: 0000-0000\n",
+		fmt.Sprintf("%+v", err),
+	)
+	assert.Equal(t, "internal error (ref: 0000-0000)", PublicMessage(err))
+}
+
+func TestQuiet(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(Config{Deterministic: true}, Quiet())
+
+	inner := func() error {
+		return w.Wrap(errors.New("connection refused"))
+	}
+	err := w.Wrapf(inner(), "processing")
+
+	assert.Equal(t, "errx.TestQuiet (config_test.go:NN): processing: connection refused", err.Error())
+	assert.Equal(t, err.Error(), fmt.Sprint(err))
+	assert.Equal(t,
+		"[0] errx.TestQuiet (config_test.go:NN): processing: connection refused\n"+
+			"[1] errx.TestQuiet.func1 (config_test.go:NN): connection refused\n"+
+			"id: 0000-0000\n",
+		fmt.Sprintf("%+v", err),
+		"the full chain is left to %+v",
+	)
+}
+
+func TestTrim(t *testing.T) {
+	helper := func(err error) error {
+		return Wrap(err)
+	}
+
+	caller := func() error {
+		return helper(errors.New("boom"))
+	}
+
+	t.Run("below", func(t *testing.T) {
+		WithTestConfig(t, Config{TrimBelow: []string{"testing.tRunner"}})
+
+		verboseOutput := fmt.Sprintf("%+v", caller())
+
+		assert.Contains(t, verboseOutput, "TestTrim.func1")
+		assert.Contains(t, verboseOutput, "TestTrim.func3")
+		assert.NotContains(t, verboseOutput, "tRunner")
+		assert.NotContains(t, verboseOutput, "goexit")
+	})
+
+	t.Run("below by full name", func(t *testing.T) {
+		WithTestConfig(t, Config{TrimBelow: []string{"github.com/alesr/errx.TestTrim.func4"}})
+
+		verboseOutput := fmt.Sprintf("%+v", caller())
+
+		assert.Contains(t, verboseOutput, "TestTrim.func2")
+		assert.NotContains(t, verboseOutput, "TestTrim.func4")
+		assert.NotContains(t, verboseOutput, "tRunner")
+	})
+
+	t.Run("above", func(t *testing.T) {
+		WithTestConfig(t, Config{
+			TrimAbove: []string{"errx.TestTrim.func1"},
+			TrimBelow: []string{"testing.tRunner"},
+		})
+
+		err := caller()
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		assert.NotContains(t, verboseOutput, "TestTrim.func1 ")
+		assert.Contains(t, verboseOutput, "[0] errx.TestTrim.func2 ")
+		assert.Contains(t, verboseOutput, "[1] errx.TestTrim.func5 ")
+	})
+
+	t.Run("above moves the wrap site to the caller", func(t *testing.T) {
+		WithTestConfig(t, Config{
+			TrimAbove:     []string{"errx.TestTrim.func1"},
+			Deterministic: true,
+		})
+
+		assert.Equal(t, "errx.TestTrim.func2 (config_test.go:NN): boom", caller().Error())
+	})
+}
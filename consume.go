@@ -0,0 +1,88 @@
+package errx
+
+import (
+	"context"
+	"strconv"
+)
+
+// Attribute keys used for consumed messages, following the OpenTelemetry
+// semantic conventions for messaging.
+const (
+	AttrMessagingDestination = "messaging.destination.name"
+	AttrMessagingPartition   = "messaging.destination.partition.id"
+	AttrMessagingOffset      = "messaging.kafka.offset"
+	AttrMessagingDeliveryTag = "messaging.rabbitmq.message.delivery_tag"
+)
+
+// TopicMessage is implemented by messages that know the topic, queue or
+// subject they were read from, recorded by ConsumeFunc under
+// AttrMessagingDestination.
+type TopicMessage interface {
+	Topic() string
+}
+
+// OffsetMessage is implemented by messages read from partitioned logs
+// such as Kafka, recorded by ConsumeFunc under AttrMessagingPartition and
+// AttrMessagingOffset.
+type OffsetMessage interface {
+	Partition() int
+	Offset() int64
+}
+
+// TaggedMessage is implemented by messages acknowledged by delivery tag
+// such as AMQP ones, recorded by ConsumeFunc under
+// AttrMessagingDeliveryTag.
+type TaggedMessage interface {
+	DeliveryTag() uint64
+}
+
+// ConsumeFunc returns a message handler calling fn, for queue-driven
+// services what errxhttp.RecoverMiddleware is for HTTP ones. Errors
+// returned by fn get the message's topic, partition and offset, or
+// delivery tag as attributes, from the methods of TopicMessage,
+// OffsetMessage and TaggedMessage msg implements, and panics are turned
+// into errors by FromPanic. Either way, the error is passed to Report and
+// returned, for the consumer to retry or dead-letter the message:
+//
+//	handle := errx.ConsumeFunc(func(ctx context.Context, msg *Delivery) error {
+//	    return process(ctx, msg.Body)
+//	})
+//	for msg := range deliveries {
+//	    if err := handle(ctx, msg); err != nil {
+//	        msg.Nack()
+//	    }
+//	}
+//
+// Errors fn returns that are not errx errors yet are wrapped first.
+func ConsumeFunc[M any](fn func(ctx context.Context, msg M) error) func(ctx context.Context, msg M) error {
+	return func(ctx context.Context, msg M) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = FromPanic(v)
+			}
+			if err != nil {
+				err = withWork(err, messageAttrs(msg)...)
+				Report(err)
+			}
+		}()
+		return fn(ctx, msg)
+	}
+}
+
+// messageAttrs returns the attributes describing msg.
+func messageAttrs(msg any) []Attr {
+	var attrs []Attr
+	if m, ok := msg.(TopicMessage); ok {
+		attrs = append(attrs, Attr{Key: AttrMessagingDestination, Value: m.Topic()})
+	}
+	if m, ok := msg.(OffsetMessage); ok {
+		attrs = append(attrs,
+			Attr{Key: AttrMessagingPartition, Value: strconv.Itoa(m.Partition())},
+			Attr{Key: AttrMessagingOffset, Value: strconv.FormatInt(m.Offset(), 10)},
+		)
+	}
+	if m, ok := msg.(TaggedMessage); ok {
+		attrs = append(attrs, Attr{Key: AttrMessagingDeliveryTag, Value: strconv.FormatUint(m.DeliveryTag(), 10)})
+	}
+	return attrs
+}
@@ -0,0 +1,77 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type kafkaMessage struct {
+	topic     string
+	partition int
+	offset    int64
+}
+
+func (m kafkaMessage) Topic() string  { return m.topic }
+func (m kafkaMessage) Partition() int { return m.partition }
+func (m kafkaMessage) Offset() int64  { return m.offset }
+
+type amqpDelivery struct {
+	tag uint64
+}
+
+func (m amqpDelivery) DeliveryTag() uint64 { return m.tag }
+
+var errPoisonMessage = errors.New("poison message")
+
+func panickingConsumer(context.Context, amqpDelivery) error {
+	panic(errPoisonMessage)
+}
+
+func TestConsumeFunc(t *testing.T) {
+	var reported []error
+	WithTestConfig(t, Config{Reporter: ReporterFunc(func(err error) {
+		reported = append(reported, err)
+	})})
+
+	t.Run("returned errors", func(t *testing.T) {
+		reported = nil
+
+		handle := ConsumeFunc(func(context.Context, kafkaMessage) error {
+			return errors.New("invalid payload")
+		})
+		err := handle(context.Background(), kafkaMessage{topic: "invoices", partition: 3, offset: 1042})
+		require.Error(t, err)
+
+		assert.Equal(t, []Attr{
+			{Key: AttrMessagingDestination, Value: "invoices"},
+			{Key: AttrMessagingPartition, Value: "3"},
+			{Key: AttrMessagingOffset, Value: "1042"},
+		}, Attrs(err))
+		assert.Equal(t, []error{err}, reported)
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		reported = nil
+
+		err := ConsumeFunc(panickingConsumer)(context.Background(), amqpDelivery{tag: 7})
+		require.Error(t, err)
+
+		assert.ErrorIs(t, err, errPoisonMessage)
+		assert.Contains(t, err.Error(), ": panic: poison message")
+		assert.Equal(t, "github.com/alesr/errx.panickingConsumer", Frames(err)[0].Function)
+		assert.Equal(t, []Attr{{Key: AttrMessagingDeliveryTag, Value: "7"}}, Attrs(err))
+		assert.Equal(t, []error{err}, reported)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		reported = nil
+
+		handle := ConsumeFunc(func(context.Context, string) error { return nil })
+		require.NoError(t, handle(context.Background(), "payload"))
+		assert.Empty(t, reported)
+	})
+}
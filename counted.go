@@ -0,0 +1,124 @@
+package errx
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// countedError is a sentinel returned by Counted, counting the chains it
+// ends up in.
+type countedError struct {
+	err error
+	n   atomic.Int64
+}
+
+func (e *countedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *countedError) Unwrap() error {
+	return e.err
+}
+
+var (
+	countedMu sync.Mutex
+	counted   atomic.Pointer[[]*countedError]
+)
+
+// SentinelCount is the number of chains a sentinel returned by Counted
+// ended up in.
+type SentinelCount struct {
+	Err   error
+	Count int64
+}
+
+// Counted returns sentinel as a counted sentinel, to be declared at
+// package level in its place:
+//
+//	var ErrNotFound = errx.Counted(errors.New("not found"))
+//
+// Every chain holding it is counted once, when first wrapped, with an
+// atomic increment instead of the fingerprint hashing of a full error
+// registry, so sentinels wrapped constantly can be counted cheaply; see
+// CountOf and Counts. The result matches itself and sentinel with
+// errors.Is, and renders like sentinel.
+// It is meant to be called at startup, but is safe for concurrent use.
+func Counted(sentinel error) error {
+	c := &countedError{err: sentinel}
+
+	countedMu.Lock()
+	defer countedMu.Unlock()
+
+	var registered []*countedError
+	if p := counted.Load(); p != nil {
+		registered = *p
+	}
+	registered = append(slices.Clip(registered), c)
+	counted.Store(&registered)
+	return c
+}
+
+// Uncount removes sentinel, returned by Counted, from the ones listed by
+// Counts, for tests declaring counted sentinels to clean up after
+// themselves:
+//
+//	errQuota := errx.Counted(errors.New("quota exceeded"))
+//	t.Cleanup(func() { errx.Uncount(errQuota) })
+//
+// The sentinel keeps counting the chains it ends up in, see CountOf.
+// It does nothing for other errors.
+func Uncount(sentinel error) {
+	c, ok := sentinel.(*countedError)
+	if !ok {
+		return
+	}
+
+	countedMu.Lock()
+	defer countedMu.Unlock()
+
+	p := counted.Load()
+	if p == nil {
+		return
+	}
+	registered := slices.DeleteFunc(slices.Clone(*p), func(e *countedError) bool { return e == c })
+	counted.Store(&registered)
+}
+
+// CountOf returns the number of chains sentinel, returned by Counted,
+// ended up in so far, or 0 for other errors.
+func CountOf(sentinel error) int64 {
+	if c, ok := sentinel.(*countedError); ok {
+		return c.n.Load()
+	}
+	return 0
+}
+
+// Counts returns the counts of every sentinel returned by Counted, in
+// the order they were declared.
+func Counts() []SentinelCount {
+	p := counted.Load()
+	if p == nil {
+		return nil
+	}
+
+	counts := make([]SentinelCount, len(*p))
+	for i, c := range *p {
+		counts[i] = SentinelCount{Err: c, Count: c.n.Load()}
+	}
+	return counts
+}
+
+// countSentinels counts the chain of err in every counted sentinel it
+// holds. It costs nothing until Counted is first called.
+func countSentinels(err error) {
+	if counted.Load() == nil {
+		return
+	}
+	Walk(err, func(err error) bool {
+		if c, ok := err.(*countedError); ok {
+			c.n.Add(1)
+		}
+		return true
+	})
+}
@@ -0,0 +1,39 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounted(t *testing.T) {
+	t.Parallel()
+
+	inner := errors.New("quota exceeded")
+	errQuota := Counted(inner)
+	t.Cleanup(func() { Uncount(errQuota) })
+
+	assert.Equal(t, "quota exceeded", errQuota.Error())
+	assert.Zero(t, CountOf(errQuota))
+
+	err := Wrap(errQuota)
+	assert.ErrorIs(t, err, errQuota)
+	assert.ErrorIs(t, err, inner)
+
+	// re-wraps belong to the same chain
+	_ = Wrap(Wrap(err))
+	_ = Wrap(fmt.Errorf("charging: %w", errQuota))
+	_ = Wrap(errors.Join(errors.New("other"), errQuota))
+	_ = fmt.Errorf("not wrapped by errx: %w", errQuota)
+
+	assert.Equal(t, int64(3), CountOf(errQuota))
+	assert.Zero(t, CountOf(inner))
+	assert.Contains(t, Counts(), SentinelCount{Err: errQuota, Count: 3})
+
+	Uncount(errQuota)
+	assert.NotContains(t, Counts(), SentinelCount{Err: errQuota, Count: 3})
+	_ = Wrap(errQuota)
+	assert.Equal(t, int64(4), CountOf(errQuota))
+}
@@ -0,0 +1,155 @@
+package errx
+
+import (
+	"errors"
+	"reflect"
+)
+
+// cycleMarker follows the frames in verbose output when err's chain
+// unwraps back to an error it already holds.
+const cycleMarker = "... cycle detected: the chain unwraps to an error it already holds"
+
+// visited records the errors met while walking a chain, so walks stop
+// when a custom Unwrap returns one of its ancestors instead of looping
+// forever. Chains of common lengths are tracked without allocating.
+type visited struct {
+	buf  [16]error
+	n    int
+	more map[error]struct{}
+}
+
+// add records err and reports whether it wasn't met before. Errors whose
+// dynamic type isn't comparable can't be told apart, so they always count
+// as new.
+func (v *visited) add(err error) bool {
+	if !reflect.TypeOf(err).Comparable() {
+		return true
+	}
+	for _, e := range v.buf[:v.n] {
+		if e == err {
+			return false
+		}
+	}
+	if _, ok := v.more[err]; ok {
+		return false
+	}
+
+	if v.n < len(v.buf) {
+		v.buf[v.n] = err
+		v.n++
+		return true
+	}
+	if v.more == nil {
+		v.more = make(map[error]struct{})
+	}
+	v.more[err] = struct{}{}
+	return true
+}
+
+// Walk calls fn with err and every error in its tree, depth first, like
+// errors.As visits them, until fn returns false. Errors met before are
+// skipped, so trees whose Unwrap methods loop back to an ancestor are
+// walked once instead of forever.
+func Walk(err error, fn func(error) bool) {
+	var v visited
+	v.walk(err, fn)
+}
+
+// walk implements Walk, reporting whether fn asked to stop.
+func (v *visited) walk(err error, fn func(error) bool) bool {
+	for err != nil && v.add(err) {
+		if !fn(err) {
+			return true
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, err := range x.Unwrap() {
+				if v.walk(err, fn) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Root returns the innermost error of err's chain, following Unwrap
+// until it returns nil or an error already met along the chain.
+// Returns nil if err is nil.
+func Root(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var v visited
+	v.add(err)
+	for {
+		next := errors.Unwrap(err)
+		if next == nil || !v.add(next) {
+			return err
+		}
+		err = next
+	}
+}
+
+// cyclic reports whether err's chain unwraps back to an error it holds.
+func cyclic(err error) bool {
+	var v visited
+	for ; err != nil; err = errors.Unwrap(err) {
+		if !v.add(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// is works like errors.Is, skipping errors met before along err's tree.
+func is(err, target error) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+
+	comparable := reflect.TypeOf(target).Comparable()
+	var found bool
+	Walk(err, func(err error) bool {
+		if comparable && err == target {
+			found = true
+		} else if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// as reports whether err's tree holds an error that errors.As would
+// assign to a T, skipping errors met before along the tree.
+func as[T error](err error) bool {
+	var found bool
+	Walk(err, func(err error) bool {
+		if _, ok := err.(T); ok {
+			found = true
+		} else if x, ok := err.(interface{ As(any) bool }); ok {
+			var target T
+			found = x.As(&target)
+		}
+		return !found
+	})
+	return found
+}
+
+// firstExtended returns the first errx error of err's tree, in the order
+// errors.As visits them, or nil if there is none.
+func firstExtended(err error) *extendedError {
+	var found *extendedError
+	Walk(err, func(err error) bool {
+		found, _ = err.(*extendedError)
+		return found == nil
+	})
+	return found
+}
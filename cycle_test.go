@@ -0,0 +1,122 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loopError is a custom error whose Unwrap can return one of its ancestors.
+type loopError struct {
+	msg  string
+	next error
+}
+
+func (e *loopError) Error() string { return e.msg }
+func (e *loopError) Unwrap() error { return e.next }
+
+// joinLoop is a custom multi-error whose Unwrap can return itself.
+type joinLoop struct {
+	errs []error
+}
+
+func (e *joinLoop) Error() string   { return "joined" }
+func (e *joinLoop) Unwrap() []error { return e.errs }
+
+// newLoop returns the first of n errors unwrapping into each other in a ring.
+func newLoop(n int) *loopError {
+	first := &loopError{msg: "loop 0"}
+	last := first
+	for i := 1; i < n; i++ {
+		next := &loopError{msg: "loop " + strconv.Itoa(i)}
+		last.next = next
+		last = next
+	}
+	last.next = first
+	return first
+}
+
+func TestCycles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chain helpers stop at the cycle", func(t *testing.T) {
+		t.Parallel()
+
+		for _, n := range []int{1, 3, 40} {
+			loop := newLoop(n)
+			err := WithCode(Wrap(fmt.Errorf("loading: %w", loop)), "loop.detected")
+
+			assert.Equal(t, Code("loop.detected"), CodeOf(err))
+			assert.True(t, IsCode(err, "loop.detected"))
+			assert.NotEmpty(t, ID(err))
+			assert.NotEmpty(t, Frames(err))
+			assert.Empty(t, Attrs(err))
+			assert.NotEmpty(t, Fingerprint(err))
+			assert.Positive(t, SizeOf(err))
+			assert.False(t, Match(err, "^nothing$"))
+			assert.False(t, FirstSeen(err).IsZero())
+			assert.Equal(t, "loop "+strconv.Itoa(n-1), Root(loop).Error(), "the last error before the chain loops back")
+
+			record := NewRecord(err)
+			assert.Equal(t, "loop "+strconv.Itoa(n-1), record.Message)
+
+			replaced := ReplaceCause(err, errors.New("replaced"))
+			assert.Equal(t, "replaced", Root(replaced).Error())
+		}
+	})
+
+	t.Run("verbose output marks the cycle", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(newLoop(2))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\n"+cycleMarker+"\n")
+		assert.NotContains(t, fmt.Sprintf("%+v", Wrap(errors.New("boom"))), cycleMarker)
+	})
+
+	t.Run("walks trees looping back once", func(t *testing.T) {
+		t.Parallel()
+
+		leaf := &fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist}
+		join := &joinLoop{}
+		join.errs = []error{leaf, join, &loopError{msg: "back", next: join}}
+
+		var visits int
+		Walk(join, func(error) bool {
+			visits++
+			return true
+		})
+		assert.Equal(t, 4, visits, "join, path error, its cause and the loop error, once each")
+
+		err := Wrap(join)
+		assert.Equal(t, KindNotFound, KindOf(err))
+		assert.Equal(t, "/etc/app.yaml", Attrs(err)[1].Value)
+		assert.True(t, is(join, fs.ErrNotExist))
+		assert.False(t, is(join, fs.ErrPermission))
+	})
+
+	t.Run("walk stops when asked", func(t *testing.T) {
+		t.Parallel()
+
+		var visits int
+		Walk(fmt.Errorf("a: %w", fmt.Errorf("b: %w", errors.New("c"))), func(error) bool {
+			visits++
+			return visits < 2
+		})
+		assert.Equal(t, 2, visits)
+	})
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Root(nil))
+		Walk(nil, func(error) bool {
+			require.Fail(t, "nothing to walk")
+			return true
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package errx
+
+import (
+	"log/slog"
+	"reflect"
+	"strconv"
+)
+
+// DebugMode selects how the misuse detected by the checks enabled with
+// Config.Debug is reported. The checks cost a few comparisons per wrap
+// and are meant for development and test builds.
+type DebugMode int
+
+const (
+	// DebugOff disables the checks.
+	DebugOff DebugMode = iota
+	// DebugLog logs a warning through slog.Default and carries on.
+	DebugLog
+	// DebugPanic panics, pointing at the offending call site.
+	DebugPanic
+)
+
+// Debug sets the DebugMode of the errors the Wrapper wraps.
+func Debug(mode DebugMode) Option {
+	return optionFunc(func(c *Config) {
+		c.Debug = mode
+	})
+}
+
+// misuse reports problem, found at the call site at, along with the
+// guidance to fix it, as selected by c.Debug.
+func (c *Config) misuse(at contextFrame, problem, guidance string) {
+	site := at.site()
+	switch c.Debug {
+	case DebugLog:
+		slog.Warn("errx: "+problem, "at", site, "fix", guidance)
+	case DebugPanic:
+		panic("errx: " + problem + " at " + site + ": " + guidance)
+	}
+}
+
+// site renders f's location with the full file path, for diagnostics
+// that should lead straight to the code.
+func (f contextFrame) site() string {
+	return shortenFuncName(f.funcName) + " (" + f.file + ":" + strconv.Itoa(f.line) + ")"
+}
+
+// checkRewrap flags redundant wraps of e at the call site current: the
+// same call site wrapping it again, usually in a loop, or the function
+// that wrapped it last wrapping it once more.
+func (c *Config) checkRewrap(e *extendedError, current contextFrame) {
+	last, ok := e.lastSite()
+	if !ok || last.funcName == "" || last.funcName != current.funcName || last.service != "" {
+		return
+	}
+
+	if last.file == current.file && last.line == current.line {
+		c.misuse(current, "error wrapped again at the same call site",
+			"wrap it once, outside the loop or retry that passes it through here")
+		return
+	}
+	c.misuse(current, "error wrapped twice by "+shortenFuncName(current.funcName),
+		"wrap once per function, adding context with Wrapf or With")
+}
+
+// checkTypedNil reports whether err is a typed nil: a non-nil interface
+// holding a nil pointer, usually a function returning a nil *MyError as
+// error. Such errors pass the err != nil checks but crash or render
+// nonsense when used, so it flags the call site at.
+func (c *Config) checkTypedNil(err error, at contextFrame) bool {
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		if !v.IsNil() {
+			return false
+		}
+	default:
+		return false
+	}
+
+	c.misuse(at, "wrapping a nil "+v.Type().String()+" stored in a non-nil error",
+		"return a literal nil instead of a nil pointer typed as error")
+	return true
+}
@@ -0,0 +1,118 @@
+package errx
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugRewrap(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(Debug(DebugPanic))
+
+	t.Run("same call site", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.New("timeout")
+		assert.Panics(t, func() {
+			for range 2 {
+				err = w.Wrap(err)
+			}
+		})
+	})
+
+	t.Run("same function", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			msg, _ := recover().(string)
+			assert.Regexp(t, `^errx: error wrapped twice by errx\.TestDebugRewrap\.\S+ at errx\.TestDebugRewrap\.\S+ \(.*/debug_test\.go:\d+\): wrap once per function`, msg)
+		}()
+
+		err := w.Wrapf(errors.New("timeout"), "first")
+		_ = w.Wrap(err)
+		t.Fatal("wrapping twice didn't panic")
+	})
+
+	t.Run("wraps by different functions", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotPanics(t, func() {
+			err := func() error { return w.Wrap(errors.New("timeout")) }()
+			_ = w.Wrap(err)
+		})
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotPanics(t, func() {
+			err := Wrap(errors.New("timeout"))
+			_ = Wrap(err)
+		})
+	})
+}
+
+func TestDebugLog(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	w := NewWrapper(Debug(DebugLog))
+	err := errors.New("timeout")
+	for range 2 {
+		err = w.Wrap(err)
+	}
+
+	assert.Contains(t, buf.String(), `msg="errx: error wrapped again at the same call site"`)
+	assert.Contains(t, buf.String(), "debug_test.go:")
+	frames := Frames(err)
+	assert.Equal(t, frames[0].Line, frames[1].Line, "the error is wrapped all the same")
+
+	buf.Reset()
+	assert.Nil(t, w.Wrapf(findInvoice(), "finding invoice"), "typed nils are treated as nil")
+	assert.Contains(t, buf.String(), `msg="errx: wrapping a nil *errx.queryError stored in a non-nil error"`)
+}
+
+type queryError struct{}
+
+func (*queryError) Error() string {
+	return "query failed"
+}
+
+// findInvoice returns a typed nil when nothing failed.
+func findInvoice() error {
+	var err *queryError
+	return err
+}
+
+func TestDebugTypedNil(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Debug(DebugPanic))
+		defer func() {
+			msg, _ := recover().(string)
+			assert.Regexp(t, `^errx: wrapping a nil \*errx\.queryError stored in a non-nil error at errx\.TestDebugTypedNil\.\S+ \(.*/debug_test\.go:\d+\): return a literal nil`, msg)
+		}()
+
+		_ = w.Wrap(findInvoice())
+		t.Fatal("wrapping a typed nil didn't panic")
+	})
+
+	t.Run("wrapped as is with debug mode off", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper().Wrap(findInvoice())
+		assert.Error(t, err)
+		var target *queryError
+		assert.ErrorAs(t, err, &target)
+	})
+}
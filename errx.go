@@ -1,106 +1,383 @@
 package errx
 
 import (
+	"bytes"
 	"fmt"
-	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// maxDepth is the default number of frames captured on first wrap.
 const maxDepth = 10
 
+// contextFrame holds the full function name and file path of a call site;
+// both are shortened when rendered.
 type contextFrame struct {
 	funcName string
 	file     string
 	line     int
+	// time is when the frame was captured
+	time time.Time
+	// msg is optional context added at the wrap site
+	msg *lazyMessage
+	// wrapSite is set on frames recorded by an explicit wrap call,
+	// as opposed to the ones found by scanning the stack above it.
+	wrapSite bool
+	// boundary marks the frame where the error crossed an
+	// architectural boundary, see Boundary
+	boundary bool
+	// args lists the values recorded with WithArgs, already rendered
+	args string
+	// tags lists the tags passed to Wrap, separated by commas
+	tags string
+	// service names the service that captured the frame, when it's
+	// not the current process
+	service string
+	// remote is set on frames decoded from a record, whose time was
+	// read from another machine's clock
+	remote bool
+}
+
+// frameNode links a frame added by re-wrapping an errx error to the
+// frames added before it, so re-wraps share everything recorded so far
+// instead of copying it.
+type frameNode struct {
+	frame contextFrame
+	next  *frameNode
 }
 
 type extendedError struct {
-	err    error
-	frames []contextFrame
+	err error
+	// top lists the frames added by re-wraps, newest first
+	top *frameNode
+	// depth is the number of frames in top
+	depth int
+	// base holds the frames captured on first wrap, the wrap site
+	// followed by the frames scanned above it
+	base  []contextFrame
+	attrs []Attr
+	// cfg is the configuration of the Wrapper that last wrapped the
+	// error, nil when it was the package-level API.
+	cfg *Config
+	// node stores the frame this error added on re-wrap, allocated
+	// along with the error itself
+	node frameNode
 }
 
 // Wrap extends an error by capturing context frames from the call stack.
 // It preserves the original error while adding valuable debugging information
 // including function names, file locations, line numbers, and timestamps.
-// Returns nil if err is nil.
-func Wrap(err error) error {
-	if err == nil {
-		return nil
+// tags are stored on the frame of the call, see Tag.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func Wrap(err error, tags ...Tag) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
 	}
+	return wrap(nil, err, 2, contextFrame{tags: joinTags(tags)})
+}
 
-	// get caller stack info
-	// return original error if we can't
+// Wrapf wraps err like Wrap and adds a formatted message to the current frame,
+// rendered as "func (file:line): message: cause".
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+	return wrap(nil, err, 2, contextFrame{msg: eagerMessage(fmt.Sprintf(format, args...))})
+}
 
-	pc, file, line, ok := runtime.Caller(1)
-	if !ok {
+// WrapIf wraps err like Wrap when cond is true and returns it untouched otherwise.
+func WrapIf(cond bool, err error) error {
+	if !cond || err == nil || loadConfig().ignored(err) {
 		return err
 	}
+	return wrap(nil, err, 2, contextFrame{})
+}
 
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
+// WrapOnce wraps err like Wrap, unless the frame err recorded last comes
+// from the calling function already, in which case err is returned as is.
+// It keeps code that may run several times for one error, such as
+// re-entered middleware or recursive handlers, from stacking the same
+// function's frame.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func WrapOnce(err error) error {
+	if err == nil || loadConfig().ignored(err) {
 		return err
 	}
 
-	currentFrame := contextFrame{
-		funcName: shortenFuncName(fn.Name()),
-		file:     filepath.Base(file),
-		line:     line,
+	funcName, file, line, resolved := caller(1)
+	if !resolved {
+		return wrap(nil, err, 2, contextFrame{})
+	}
+	if extErr, ok := err.(*extendedError); ok {
+		if last, ok := extErr.lastSite(); ok && last.funcName == funcName && last.service == "" {
+			return err
+		}
+	}
+	return wrap(nil, err, 2, contextFrame{funcName: funcName, file: file, line: line})
+}
+
+// lastSite returns the frame recorded by the latest wrap call of e.
+func (e *extendedError) lastSite() (contextFrame, bool) {
+	switch {
+	case e.top != nil:
+		return e.top.frame, true
+	case len(e.base) > 0 && e.base[0].wrapSite:
+		return e.base[0], true
+	default:
+		return contextFrame{}, false
+	}
+}
+
+// wrap adds the frame found skip levels above it to err, scanning the
+// rest of the call stack when err is not an errx error yet. site holds
+// what the wrap call adds to the frame, such as a message; wrap fills in
+// the location, unless site has one already, and the capture time.
+// cfg is the configuration of the calling Wrapper, nil for the
+// package-level one; on first wrap it is adjusted by the defaults
+// registered for the caller's package. attrs are attached last, before
+// the result is passed to the OnWrap hooks. Where the runtime can't
+// resolve callers, the result holds whatever could be captured, down to
+// no frames at all.
+func wrap(cfg *Config, err error, skip int, site contextFrame, attrs ...Attr) error {
+	// get caller stack info
+	// a frame without a location still carries what the wrap call added
+
+	funcName, file, line, resolved := site.funcName, site.file, site.line, site.funcName != ""
+	if !resolved {
+		funcName, file, line, resolved = caller(skip)
+		// helpers hand the wrap site over to their first caller that isn't one
+		for k := skip + 1; resolved && isHelper(funcName); k++ {
+			if fn, f, l, ok := caller(k); ok {
+				funcName, file, line, skip = fn, f, l, k
+			} else {
+				break
+			}
+		}
+	}
+
+	c := cfg
+	if c == nil {
+		c = loadConfig()
+	}
+	// first wraps pick up the defaults registered for their package
+	if _, ok := err.(*extendedError); !ok {
+		c = packageConfig(c, funcName)
+	}
+	now := c.now()
+
+	currentFrame := site
+	currentFrame.funcName = funcName
+	currentFrame.file = file
+	currentFrame.line = line
+	currentFrame.time = now
+	currentFrame.wrapSite = true
+
+	hasCurrent := resolved || site.msg != nil
+
+	// in debug mode, typed nils are flagged and treated as nil
+	if c.Debug != DebugOff && c.checkTypedNil(err, currentFrame) {
+		return nil
 	}
 
 	// check if already wrapped
-	// yes: just add the current frame to the existing chain
-	// no: capture frames up to 10 levels deep
+	// yes: just link the current frame to the existing chain
+	// no: capture frames up to the configured depth
 
 	if extErr, ok := err.(*extendedError); ok {
-		return &extendedError{
-			err:    extErr.err,
-			frames: append([]contextFrame{currentFrame}, extErr.frames...),
+		if c.Debug != DebugOff && resolved {
+			c.checkRewrap(extErr, currentFrame)
+		}
+
+		kept := extErr.attrs
+		// the error enters the scope of another Wrapper
+		if cfg != nil && extErr.cfg != cfg {
+			kept = append(kept[:len(kept):len(kept)], cfg.DefaultAttrs...)
+		}
+		if len(attrs) > 0 {
+			kept = append(kept[:len(kept):len(kept)], attrs...)
+		}
+
+		wrapped := &extendedError{
+			err:   extErr.err,
+			top:   extErr.top,
+			depth: extErr.depth,
+			base:  extErr.base,
+			attrs: kept,
+			cfg:   cfg,
+		}
+		if hasCurrent {
+			wrapped.node = frameNode{frame: c.captured(currentFrame), next: extErr.top}
+			wrapped.top = &wrapped.node
+			wrapped.depth++
 		}
+		if c.SizeBudget > 0 {
+			wrapped.compact(c.SizeBudget)
+		}
+		runHooks(wrapped)
+		return wrapped
 	}
 
 	// first wrap - capture current frame and scan deeper
-	frames := []contextFrame{currentFrame}
+	frames := make([]contextFrame, 0, c.depth())
+	if hasCurrent && !matchFunc(c.TrimAbove, funcName) {
+		frames = append(frames, c.captured(currentFrame))
+	}
 
-	// keep going while we can extract valid frame information
-	for skip := 2; skip < maxDepth; skip++ {
-		pc, file, line, ok := runtime.Caller(skip)
-		if !ok {
-			break
+	// resolve the rest of the stack in one walk, within the budget
+	var (
+		buf       [maxDepth]callSite
+		sites     []callSite
+		truncated bool
+	)
+	if resolved {
+		sites = buf[:0]
+		if n := c.depth() - 1; n > len(buf) {
+			sites = make([]callSite, n)
+		} else if n > 0 {
+			sites = buf[:n]
 		}
 
-		fn := runtime.FuncForPC(pc)
-		if fn == nil {
+		var deadline time.Time
+		if c.CaptureBudget > 0 {
+			deadline = time.Now().Add(c.CaptureBudget)
+		}
+		var n int
+		n, truncated = callers(skip+1, sites, deadline)
+		sites = sites[:n]
+	}
+
+	frames = c.appendSites(frames, sites, now)
+	return newChain(c, cfg, err, frames, truncated, attrs)
+}
+
+// appendSites appends the frames of the stack sites to frames, from the
+// innermost call up, applying the trimming of c. The first frame left is
+// marked as the wrap site.
+func (c *Config) appendSites(frames []contextFrame, sites []callSite, now time.Time) []contextFrame {
+	for _, site := range sites {
+		funcName, file, line := site.funcName, site.file, site.line
+
+		// trimmed below: drop this frame and everything that called it
+		if matchFunc(c.TrimBelow, funcName) {
 			break
 		}
 
-		frames = append(frames, contextFrame{
-			funcName: shortenFuncName(fn.Name()),
-			file:     filepath.Base(file),
+		// trimmed above: drop this frame and everything it called
+		if matchFunc(c.TrimAbove, funcName) {
+			frames = frames[:0]
+			continue
+		}
+		if isHelper(funcName) {
+			continue
+		}
+
+		frames = append(frames, c.captured(contextFrame{
+			funcName: funcName,
+			file:     file,
 			line:     line,
-		})
+			time:     now,
+			// the first frame left after trimming above is the wrap site
+			wrapSite: len(frames) == 0,
+		}))
+	}
+	return frames
+}
+
+// newChain returns the errx error wrapping err for the first time with
+// frames, along with the attributes a first wrap attaches under c followed
+// by attrs, and passes it to the OnWrap hooks.
+func newChain(c, cfg *Config, err error, frames []contextFrame, truncated bool, attrs []Attr) *extendedError {
+	frames = appendForeign(frames, foreignFrames(err))
+
+	extErr := &extendedError{err: err, base: frames, cfg: cfg}
+	// the innermost errx error of a chain holds its ID and the details
+	// of its cause
+	origin := !hasExtendedError(err)
+	if origin {
+		countSentinels(err)
+		extErr.attrs = append(extErr.attrs, Attr{Key: keyID, Value: newID(c)})
+		if c.Runtime {
+			extErr.attrs = append(extErr.attrs, Attr{Key: keyRuntime, Value: captureRuntime(c)})
+		}
+		if c.MemStats {
+			extErr.attrs = append(extErr.attrs, memAttrs()...)
+		}
+		if c.LeakCheck && len(frames) > 0 {
+			extErr.attrs = append(extErr.attrs, trackLeak(frames[0]))
+		}
+	}
+	extErr.attrs = append(extErr.attrs, c.DefaultAttrs...)
+	if truncated {
+		extErr.attrs = append(extErr.attrs, Attr{Key: keyTruncated, Value: true})
 	}
-	return &extendedError{err: err, frames: frames}
+	// enriched kinds are more specific than default ones
+	if origin {
+		extErr.attrs = append(extErr.attrs, enrich(err)...)
+	}
+	extErr.attrs = append(extErr.attrs, attrs...)
+	if c.SizeBudget > 0 {
+		extErr.compact(c.SizeBudget)
+	}
+	runHooks(extErr)
+	return extErr
 }
 
 // Error returns a string representation of the error with all captured context frames.
 // It formats each frame with function name, file location, line number, and timestamp,
-// followed by the original error message.
+// followed by the original error message. In quiet mode, only the outermost frame is shown,
+// and Config.HeadFrames and TailFrames leave out the frames in the middle of long chains.
 func (e *extendedError) Error() string {
-	if len(e.frames) == 0 {
-		return e.err.Error()
+	var buf [256]byte
+	return string(e.appendError(buf[:0]))
+}
+
+// maxStackFrames is the number of frames rendering sorts out on the stack
+// before falling back to the heap.
+const maxStackFrames = 16
+
+// appendError appends the output of Error to dst.
+func (e *extendedError) appendError(dst []byte) []byte {
+	if leakTracking.Load() {
+		Handled(e)
 	}
+	c := e.config()
+	if c.Token {
+		dst = e.appendToken(dst)
+	}
+
+	var buf [maxStackFrames]contextFrame
+	frames := e.appendVisibleFrames(buf[:0], c)
+	if len(frames) == 0 {
+		return append(dst, e.err.Error()...)
+	}
+	if c.Quiet {
+		frames = frames[:1]
+	}
+
+	fields := c.errorFields()
+	head, tail := c.window(len(frames))
+	for i, frame := range frames {
+		switch {
+		case i == head && head < tail:
+			dst = appendOmitted(dst, tail-head)
+		case i > head && i < tail:
+			continue
+		default:
+			dst = frame.appendRender(dst, c, fields, c.errorTime(frame.time, fields))
+		}
+		dst = append(dst, TextSeparator...)
+	}
+	return appendCause(dst, e.err)
+}
 
-	var parts []string
-	for _, frame := range e.frames {
-		part := fmt.Sprintf("%s (%s:%d)",
-			frame.funcName,
-			frame.file,
-			frame.line,
-		)
-		parts = append(parts, part)
+// appendCause appends cause the way %v renders it.
+func appendCause(dst []byte, cause error) []byte {
+	if _, ok := cause.(fmt.Formatter); ok {
+		return fmt.Appendf(dst, "%v", cause)
 	}
-	return fmt.Sprintf("%s: %v", strings.Join(parts, ": "), e.err)
+	return append(dst, cause.Error()...)
 }
 
 // Unwrap returns the original wrapped error, enabling compatibility with errors.Is and errors.As.
@@ -110,32 +387,329 @@ func (e *extendedError) Unwrap() error {
 
 // Format implements fmt.Formatter to provide detailed error output when using %+v.
 // With %+v, it displays each context frame on a separate line with frame indices.
+// Sections delimited by Boundary are separated, and capture times shown
+// depending on Config.TimeFormat.
+// Config.HeadFrames and TailFrames leave out the frames in the middle of
+// long chains.
+// Values recorded by WithArgs follow their frame on the next line.
+// A marker follows the frames of chains truncated by Config.CaptureBudget
+// or unwrapping back into themselves.
+// The frames are followed by the attributes, then the code, kind,
+// severity, runbook, hint, scopes and runtime snapshot when set, and the
+// support ID.
+// If the cause implements fmt.Formatter itself, frame lines leave the
+// message out and the cause's own %+v output is written once after them.
+// Messages spanning several lines have their continuation lines indented
+// under their entry.
+// With %#v, it writes the output of GoString.
 // For other format verbs, it falls back to the standard Error() output.
 func (e *extendedError) Format(s fmt.State, verb rune) {
+	if leakTracking.Load() {
+		Handled(e)
+	}
+	if verb == 'v' && s.Flag('#') {
+		fmt.Fprint(s, e.GoString())
+		return
+	}
 	if verb == 'v' && s.Flag('+') {
-		if len(e.frames) == 0 {
-			fmt.Fprint(s, e.err.Error())
-			return
+		_, _ = s.Write(e.appendVerbose(nil))
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+// appendVerbose appends the output of %+v to dst.
+func (e *extendedError) appendVerbose(dst []byte) []byte {
+	c := e.config()
+
+	var buf [maxStackFrames]contextFrame
+	frames := e.appendVisibleFrames(buf[:0], c)
+	_, delegate := e.err.(fmt.Formatter)
+
+	if len(frames) == 0 && !delegate {
+		dst = append(dst, e.err.Error()...)
+		dst = append(dst, '\n')
+	}
+
+	fields := c.verboseFields()
+	showTimes := c.TimeFormat != TimeHidden && !c.Deterministic && fields.Has(FieldTime)
+	if showTimes && c.NormalizeSkew {
+		normalizeSkew(frames)
+	}
+
+	cause := e.err.Error()
+	first := earliest(frames)
+	head, tail := c.window(len(frames))
+	for i, frame := range frames {
+		if i == head && head < tail {
+			dst = appendOmitted(dst, tail-head)
+			dst = append(dst, '\n')
+		}
+		if i >= head && i < tail {
+			continue
 		}
 
-		for i, frame := range e.frames {
-			fmt.Fprintf(s, "[%d] %s (%s:%d): %v\n",
-				i,
-				frame.funcName,
-				frame.file,
-				frame.line,
-				e.err,
-			)
+		start := len(dst)
+		dst = append(dst, '[')
+		dst = strconv.AppendInt(dst, int64(i), 10)
+		dst = append(dst, "] "...)
+		var t string
+		if showTimes {
+			t = c.frameTime(frame.time, first)
+		}
+		dst = frame.appendRender(dst, c, fields, t)
+		if !delegate {
+			dst = append(dst, ": "...)
+			dst = append(dst, cause...)
+		}
+		dst = indentContinuation(dst, start)
+		if frame.args != "" {
+			start := len(dst)
+			dst = append(dst, "    args: "...)
+			dst = append(dst, frame.args...)
+			dst = indentContinuation(dst, start)
+		}
+
+		if frame.boundary && i < len(frames)-1 {
+			dst = append(dst, boundarySeparator+"\n"...)
 		}
-		return
 	}
-	fmt.Fprint(s, e.Error())
+	if Truncated(e) {
+		dst = append(dst, truncatedMarker+"\n"...)
+	}
+	if showTimes && skewed(frames) {
+		dst = append(dst, skewMarker+"\n"...)
+	}
+	if cyclic(e.err) {
+		dst = append(dst, cycleMarker+"\n"...)
+	}
+
+	if delegate {
+		start := len(dst)
+		dst = fmt.Appendf(dst, "%+v", e.err)
+		if len(dst) == start || dst[len(dst)-1] != '\n' {
+			dst = append(dst, '\n')
+		}
+	}
+
+	for _, attr := range Attrs(e) {
+		start := len(dst)
+		dst = fmt.Appendf(dst, "%s: %v", attr.Key, attr.Value)
+		dst = indentContinuation(dst, start)
+	}
+
+	line := func(name, value string) {
+		if value != "" {
+			dst = append(dst, name...)
+			dst = append(dst, ": "...)
+			dst = append(dst, value...)
+			dst = append(dst, '\n')
+		}
+	}
+	line("code", string(CodeOf(e)))
+	line("kind", string(KindOf(e)))
+	if severity, ok := Value[Severity](e, keySeverity); ok {
+		line("severity", severity.String())
+	}
+	line("runbook", Runbook(e))
+	line("hint", Hint(e))
+	if fields := FieldErrors(e); len(fields) > 0 {
+		line("fields", formatFieldErrors(fields))
+	}
+	if scopes := ScopesOf(e); len(scopes) > 0 {
+		line("scopes", formatScopes(scopes))
+	}
+	if info, ok := RuntimeOf(e); ok {
+		line("runtime", info.String())
+	}
+	line("id", ID(e))
+	return dst
 }
 
-func shortenFuncName(full string) string {
-	parts := strings.Split(full, "/")
-	if len(parts) > 0 {
-		parts = parts[len(parts)-1:]
+// continuationIndent prefixes the continuation lines of multi-line
+// entries in verbose output.
+const continuationIndent = "    "
+
+// indentContinuation indents every line but the first of the entry
+// appended to dst from start, and ends it with a newline, so multi-line
+// messages such as SQL statements or joined errors stay visually attached
+// to their entry.
+func indentContinuation(dst []byte, start int) []byte {
+	if n := len(dst); n > start && dst[n-1] == '\n' {
+		dst = dst[:n-1]
+	}
+	if bytes.IndexByte(dst[start:], '\n') < 0 {
+		return append(dst, '\n')
+	}
+
+	entry := bytes.ReplaceAll(dst[start:], []byte("\n"), []byte("\n"+continuationIndent))
+	dst = append(dst[:start], entry...)
+	return append(dst, '\n')
+}
+
+// config returns the configuration e renders with.
+func (e *extendedError) config() *Config {
+	if e.cfg != nil {
+		return e.cfg
+	}
+	return loadConfig()
+}
+
+// appendVisibleFrames appends the frames rendered under c to dst.
+// Deterministic mode keeps only wrap sites, whose number doesn't depend
+// on who called the code.
+func (e *extendedError) appendVisibleFrames(dst []contextFrame, c *Config) []contextFrame {
+	start := len(dst)
+	dst = e.appendDedupedFrames(dst)
+	if !c.Deterministic {
+		return dst
+	}
+
+	kept := dst[:start]
+	for _, frame := range dst[start:] {
+		if frame.wrapSite {
+			kept = append(kept, frame)
+		}
+	}
+	return kept
+}
+
+// frames returns all of e's frames: the ones added by re-wraps, newest
+// first, followed by the ones captured on first wrap.
+func (e *extendedError) frames() []contextFrame {
+	if e.top == nil {
+		return e.base
 	}
-	return strings.Join(parts, "/")
+
+	frames := make([]contextFrame, 0, e.depth+len(e.base))
+	for n := e.top; n != nil; n = n.next {
+		frames = append(frames, n.frame)
+	}
+	return append(frames, e.base...)
+}
+
+// dedupedFrames returns e's frames without the stack suffix shared by
+// chained wraps. Re-wrapping an error higher up the same call stack records
+// a function the first wrap already found while scanning; the scanned copy
+// of each such function is left out so every call is listed once.
+func (e *extendedError) dedupedFrames() []contextFrame {
+	if e.top == nil {
+		return e.base
+	}
+	return e.appendDedupedFrames(make([]contextFrame, 0, e.depth+len(e.base)))
+}
+
+// appendDedupedFrames appends the frames returned by dedupedFrames to dst.
+func (e *extendedError) appendDedupedFrames(dst []contextFrame) []contextFrame {
+	start := len(dst)
+	for n := e.top; n != nil; n = n.next {
+		dst = append(dst, n.frame)
+	}
+	dst = append(dst, e.base...)
+	all := dst[start:]
+
+	// frames hold re-wrap sites, newest first, then the first wrap site
+	// and the frames scanned above it
+	origin := -1
+	for i, frame := range all {
+		if !frame.wrapSite {
+			break
+		}
+		origin = i
+	}
+	if origin < 1 {
+		return dst
+	}
+
+	// errors travel up the stack, so each re-wrap matches the nearest
+	// scanned frame past the one matched by the re-wrap before it; as
+	// matches come in stack order, the frames between them are moved
+	// over the matched ones while searching
+	kept, next := origin+1, origin+1
+	for i := origin - 1; i >= 0; i-- {
+		for j := next; j < len(all); j++ {
+			if all[j].funcName != all[i].funcName {
+				continue
+			}
+			kept += copy(all[kept:], all[next:j])
+			next = j + 1
+			break
+		}
+	}
+	kept += copy(all[kept:], all[next:])
+	return dst[:start+kept]
+}
+
+// matchFunc reports whether the function named full matches one of
+// patterns, either by its full name or by its shortened one.
+func matchFunc(patterns []string, full string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	short := shortenFuncName(full)
+	for _, pattern := range patterns {
+		if pattern == full || pattern == short {
+			return true
+		}
+	}
+	return false
+}
+
+// format renders the frame as "func (file:line)", followed by
+// ": message" when the wrap site added one.
+func (f contextFrame) format(c *Config) string {
+	return string(f.appendRender(nil, c, c.errorFields(), ""))
+}
+
+// appendRender appends the frame like format, made of fields, with t in
+// brackets after the location when it's not empty. Frames without a
+// location render as their message alone.
+func (f contextFrame) appendRender(dst []byte, c *Config, fields FrameFields, t string) []byte {
+	start := len(dst)
+	dst = f.appendLocation(dst, c, fields)
+	if f.tags != "" && fields.Has(FieldTags) {
+		dst = f.appendTags(dst, len(dst) > start)
+	}
+	if t != "" && len(dst) > start {
+		dst = append(dst, " ["...)
+		dst = append(dst, t...)
+		dst = append(dst, ']')
+	}
+	if f.msg != nil {
+		if len(dst) > start {
+			dst = append(dst, TextSeparator...)
+		}
+		dst = append(dst, f.msg.String()...)
+	}
+	return dst
+}
+
+// location renders the frame as "func (file:line)", prefixed by
+// "[service] " for frames captured by another service, or returns an
+// empty string when the runtime couldn't resolve it.
+func (f contextFrame) location(c *Config) string {
+	return string(f.appendLocation(nil, c, c.errorFields()))
+}
+
+// appendLocation appends the frame's location made of fields, as
+// returned by location.
+func (f contextFrame) appendLocation(dst []byte, c *Config, fields FrameFields) []byte {
+	start := len(dst)
+	if f.service != "" {
+		dst = append(dst, '[')
+		dst = append(dst, f.service...)
+		dst = append(dst, "] "...)
+	}
+	prefixed := len(dst)
+	if dst = f.appendFields(dst, c, fields); len(dst) == prefixed {
+		return dst[:start]
+	}
+	return dst
+}
+
+// shortenFuncName strips the package path from full, keeping the package
+// name, e.g. "errx.Wrap" for "github.com/alesr/errx.Wrap".
+func shortenFuncName(full string) string {
+	return full[strings.LastIndexByte(full, '/')+1:]
 }
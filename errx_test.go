@@ -145,6 +145,79 @@ func TestWrap(t *testing.T) {
 
 }
 
+func TestWrapIf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WrapIf(true, nil))
+		assert.Nil(t, WrapIf(false, nil))
+	})
+
+	t.Run("condition true", func(t *testing.T) {
+		t.Parallel()
+
+		originalErr := errors.New("test error")
+		err := WrapIf(true, originalErr)
+
+		assert.True(t, errors.Is(err, originalErr))
+		assert.Contains(t, err.Error(), "TestWrapIf")
+	})
+
+	t.Run("condition false", func(t *testing.T) {
+		t.Parallel()
+
+		originalErr := errors.New("test error")
+
+		assert.Equal(t, originalErr, WrapIf(false, originalErr))
+	})
+}
+
+func TestWrapOnce(t *testing.T) {
+	t.Parallel()
+
+	wrapSites := func(err error) []string {
+		var sites []string
+		for _, frame := range Frames(err) {
+			if frame.WrapSite {
+				sites = append(sites, frame.Function)
+			}
+		}
+		return sites
+	}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WrapOnce(nil))
+	})
+
+	t.Run("skips re-entries of the same function", func(t *testing.T) {
+		t.Parallel()
+
+		var handle func(n int, err error) error
+		handle = func(n int, err error) error {
+			err = WrapOnce(err)
+			if n > 0 {
+				return handle(n-1, err)
+			}
+			return err
+		}
+
+		originalErr := errors.New("test error")
+		err := handle(3, originalErr)
+
+		require.ErrorIs(t, err, originalErr)
+		assert.Len(t, wrapSites(err), 1)
+
+		// another function still adds its frame
+		err = WrapOnce(err)
+		assert.Len(t, wrapSites(err), 2)
+		assert.Same(t, err, WrapOnce(err))
+	})
+}
+
 func TestMultipleFrameCapture(t *testing.T) {
 	t.Parallel()
 
@@ -313,3 +386,217 @@ func TestShortenFuncName(t *testing.T) {
 		})
 	}
 }
+
+// detailedError is a cause with its own verbose representation.
+type detailedError struct{}
+
+func (detailedError) Error() string { return "detailed failure" }
+
+func (d detailedError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "detailed failure\nquery: SELECT 1\nhost: db-1")
+		return
+	}
+	fmt.Fprint(s, d.Error())
+}
+
+func TestFormatterDelegation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renders the cause detail once", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(detailedError{})
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		assert.Equal(t, 1, strings.Count(verboseOutput, "detailed failure"))
+		assert.Contains(t, verboseOutput, "query: SELECT 1\nhost: db-1\n")
+		assert.Contains(t, verboseOutput, "[0] ")
+		assert.Contains(t, verboseOutput, "TestFormatterDelegation")
+	})
+
+	t.Run("cause detail follows the frames", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(detailedError{})
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		lines := strings.Split(verboseOutput, "\n")
+		require.GreaterOrEqual(t, len(lines), 2)
+		assert.True(t, strings.HasPrefix(lines[0], "[0] "))
+		assert.NotContains(t, lines[0], "detailed failure")
+		assert.Less(t, strings.LastIndex(verboseOutput, "] "), strings.Index(verboseOutput, "detailed failure"))
+	})
+
+	t.Run("compact output unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(detailedError{})
+
+		assert.True(t, strings.HasSuffix(err.Error(), ": detailed failure"))
+	})
+
+	t.Run("plain causes repeat on each frame", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("plain failure"))
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		lines := strings.Split(verboseOutput, "\n")
+		assert.Contains(t, lines[0], ": plain failure")
+	})
+}
+
+func TestOverlappingFrames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shared suffix listed once", func(t *testing.T) {
+		t.Parallel()
+
+		inner := func() error {
+			return Wrap(errors.New("boom"))
+		}
+
+		outer := func() error {
+			if err := inner(); err != nil {
+				return Wrap(err)
+			}
+			return nil
+		}
+
+		err := outer()
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		assert.Equal(t, 1, strings.Count(verboseOutput, "TestOverlappingFrames.func1.2 "))
+		assert.Equal(t, 1, strings.Count(err.Error(), "TestOverlappingFrames.func1.2 "))
+		assert.Contains(t, verboseOutput, "[0] errx.TestOverlappingFrames.func1.2 ")
+		assert.Contains(t, verboseOutput, "[1] errx.TestOverlappingFrames.func1.1 ")
+		assert.Contains(t, verboseOutput, "[2] errx.TestOverlappingFrames.func1 ")
+	})
+
+	t.Run("one frame per recursive call", func(t *testing.T) {
+		t.Parallel()
+
+		var recurse func(n int) error
+		recurse = func(n int) error {
+			if n == 0 {
+				return Wrap(errors.New("boom"))
+			}
+			return Wrap(recurse(n - 1))
+		}
+
+		err := recurse(3)
+
+		assert.Equal(t, 4, strings.Count(err.Error(), "TestOverlappingFrames.func2.1 "))
+		assert.Equal(t, 1, strings.Count(err.Error(), "TestOverlappingFrames.func2 "))
+	})
+
+	t.Run("unrelated stacks are kept", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- Wrap(errors.New("boom"))
+		}()
+
+		err := Wrap(<-errCh)
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		assert.Contains(t, verboseOutput, "[0] errx.TestOverlappingFrames.func3 ")
+		assert.Contains(t, verboseOutput, "[1] errx.TestOverlappingFrames.func3.1 ")
+	})
+}
+
+func TestUnresolvedCaller(t *testing.T) {
+	t.Parallel()
+
+	// a skip past the top of the stack stands in for runtimes that
+	// can't resolve callers
+	const skip = 1000
+
+	t.Run("still an errx error", func(t *testing.T) {
+		t.Parallel()
+
+		err := wrap(nil, errors.New("boom"), skip, contextFrame{})
+
+		assert.Empty(t, Frames(err))
+		assert.NotEmpty(t, ID(err))
+		assert.Equal(t, "boom", err.Error())
+		assert.Equal(t, "boom\nid: "+ID(err)+"\n", fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("keeps messages", func(t *testing.T) {
+		t.Parallel()
+
+		err := wrap(nil, errors.New("boom"), skip, contextFrame{msg: &lazyMessage{msg: "loading user"}})
+		assert.Equal(t, "loading user: boom", err.Error())
+
+		err = Wrap(err)
+		assert.Regexp(t, `^errx\.TestUnresolvedCaller\.func2 \(errx_test\.go:\d+\): loading user: boom$`, err.Error())
+	})
+}
+
+func TestRewrap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shares the frames of the wrapped error", func(t *testing.T) {
+		t.Parallel()
+
+		first := Wrap(errors.New("boom"))
+		second := Wrap(first)
+		third := Wrap(second)
+
+		require.Len(t, Frames(third), len(Frames(first))+2)
+		assert.Equal(t, Frames(first), Frames(third)[2:])
+		assert.Equal(t, Frames(second), Frames(third)[1:])
+	})
+}
+
+func TestMultiLineMessages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cause", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.Join(errors.New("first failure"), errors.New("second failure")))
+
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		require.Greater(t, len(lines), 2)
+		assert.Regexp(t, `^\[0\] errx\.TestMultiLineMessages\.func1 \(errx_test\.go:\d+\): first failure$`, lines[0])
+		assert.Equal(t, "    second failure", lines[1])
+		assert.Regexp(t, `^\[1\] `, lines[2])
+	})
+
+	t.Run("frame message", func(t *testing.T) {
+		t.Parallel()
+
+		query := "SELECT id\nFROM users\nWHERE email = $1"
+		err := Wrapf(errors.New("timeout"), "running query:\n%s", query)
+
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		require.Greater(t, len(lines), 5)
+		assert.Regexp(t, `^\[0\] .*: running query:$`, lines[0])
+		assert.Equal(t, "    SELECT id", lines[1])
+		assert.Equal(t, "    FROM users", lines[2])
+		assert.Equal(t, "    WHERE email = $1: timeout", lines[3])
+		assert.Regexp(t, `^\[1\] `, lines[4])
+	})
+
+	t.Run("attribute value", func(t *testing.T) {
+		t.Parallel()
+
+		err := With(errors.New("invalid config"), "yaml", "server:\n  port: abc\n")
+
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nyaml: server:\n      port: abc\nid: ")
+	})
+
+	t.Run("trailing newline", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("boom\n"))
+
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		assert.Regexp(t, `: boom$`, lines[0])
+		assert.Regexp(t, `^\[1\] `, lines[1])
+	})
+}
@@ -0,0 +1,13 @@
+// Command errxvet runs the errx wrapping analyzer:
+//
+//	errxvet -packages=github.com/me/app/internal ./...
+package main
+
+import (
+	"github.com/alesr/errx/errxanalyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(errxanalyzer.Analyzer)
+}
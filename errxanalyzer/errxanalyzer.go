@@ -0,0 +1,262 @@
+// Package errxanalyzer provides a go/analysis analyzer enforcing the errx
+// wrapping convention: errors returned from configured packages must pass
+// through errx, and a function shouldn't wrap the same error twice.
+package errxanalyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const errxPath = "github.com/alesr/errx"
+
+// wrapFuncs are the errx functions and Wrapper methods that add a frame
+// unconditionally. WrapIf and WrapOnce, which may not, are left out, so
+// wrapping with them after another wrap is not reported.
+var wrapFuncs = map[string]bool{
+	"Boundary":  true,
+	"WithArgs":  true,
+	"Wrap":      true,
+	"WrapCtx":   true,
+	"WrapFatal": true,
+	"WrapLazy":  true,
+	"WrapPCs":   true,
+	"Wrapf":     true,
+}
+
+// Analyzer reports local error variables returned without passing through
+// errx, in the packages selected with the -packages flag, and errors
+// wrapped more than once within the same function.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errxwrap",
+	Doc:      "check that errors returned at package boundaries are wrapped with errx",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// packages holds the -packages flag: a comma-separated list of import
+// path prefixes where returned errors must be wrapped. Empty means all.
+var packages string
+
+func init() {
+	Analyzer.Flags.StringVar(&packages, "packages", "",
+		"comma-separated import path prefixes where returned errors must be wrapped (default all)")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	checkReturns := enforced(pass.Pkg.Path())
+
+	nodes := []ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body == nil {
+			return
+		}
+
+		f := scanFunc(pass, body)
+		f.reportDoubleWraps(pass)
+		if checkReturns {
+			f.reportUnwrappedReturns(pass)
+		}
+	})
+	return nil, nil
+}
+
+// enforced reports whether returned errors must be wrapped in pkgPath.
+func enforced(pkgPath string) bool {
+	if packages == "" {
+		return true
+	}
+
+	for _, prefix := range strings.Split(packages, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+		if pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// funcInfo collects what the checks need to know about one function body.
+type funcInfo struct {
+	// wrapped holds local variables assigned the result of an errx call
+	wrapped map[types.Object]bool
+	// returns holds local error variables returned as is
+	returns []*ast.Ident
+	// wraps holds wrap calls in source order
+	wraps []wrapCall
+}
+
+type wrapCall struct {
+	call *ast.CallExpr
+	// arg is the local variable being wrapped, if any
+	arg types.Object
+	// assigned is the variable the result is assigned to, if any
+	assigned types.Object
+}
+
+// scanFunc walks body without descending into nested function literals,
+// which are checked on their own.
+func scanFunc(pass *analysis.Pass, body *ast.BlockStmt) *funcInfo {
+	f := &funcInfo{wrapped: make(map[types.Object]bool)}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, rhs := range n.Rhs {
+				call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+				if !ok || !isErrxCall(pass, call) {
+					continue
+				}
+				obj := localObject(pass, n.Lhs[i])
+				if obj == nil {
+					continue
+				}
+				f.wrapped[obj] = true
+				if isWrapCall(pass, call) {
+					// recorded here with its target; seen skips it when the
+					// call expression itself is visited
+					f.wraps = append(f.wraps, wrapCall{call: call, arg: wrapArg(pass, call), assigned: obj})
+				}
+			}
+
+		case *ast.CallExpr:
+			if isWrapCall(pass, n) && !f.seen(n) {
+				f.wraps = append(f.wraps, wrapCall{call: n, arg: wrapArg(pass, n)})
+			}
+			// directly nested wraps: errx.Wrap(errx.Wrap(err))
+			for _, arg := range n.Args {
+				inner, ok := ast.Unparen(arg).(*ast.CallExpr)
+				if ok && isWrapCall(pass, n) && isWrapCall(pass, inner) {
+					pass.Reportf(n.Pos(), "error wrapped twice in the same expression")
+				}
+			}
+
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				ident, ok := ast.Unparen(result).(*ast.Ident)
+				if !ok || !isError(pass.TypesInfo.TypeOf(ident)) {
+					continue
+				}
+				if localObject(pass, ident) != nil {
+					f.returns = append(f.returns, ident)
+				}
+			}
+		}
+		return true
+	})
+	return f
+}
+
+// seen reports whether call was already recorded through an assignment.
+func (f *funcInfo) seen(call *ast.CallExpr) bool {
+	for _, w := range f.wraps {
+		if w.call == call {
+			return true
+		}
+	}
+	return false
+}
+
+// reportUnwrappedReturns flags local error variables returned without
+// ever being assigned the result of an errx call.
+func (f *funcInfo) reportUnwrappedReturns(pass *analysis.Pass) {
+	for _, ident := range f.returns {
+		if f.wrapped[pass.TypesInfo.ObjectOf(ident)] {
+			continue
+		}
+		pass.Reportf(ident.Pos(), "error %s returned without errx.Wrap", ident.Name)
+	}
+}
+
+// reportDoubleWraps flags a wrap of a variable that already holds the
+// result of an earlier wrap of itself in the same function.
+func (f *funcInfo) reportDoubleWraps(pass *analysis.Pass) {
+	rewrapped := make(map[types.Object]bool)
+	for _, w := range f.wraps {
+		if w.arg != nil && rewrapped[w.arg] {
+			pass.Reportf(w.call.Pos(), "error %s already wrapped in this function", w.arg.Name())
+		}
+		if w.assigned != nil && w.assigned == w.arg {
+			rewrapped[w.arg] = true
+		}
+	}
+}
+
+// isErrxCall reports whether call invokes a function or Wrapper method
+// of the errx package.
+func isErrxCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn := calledFunc(pass, call)
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == errxPath
+}
+
+// isWrapCall reports whether call always adds a frame to its argument.
+func isWrapCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	return isErrxCall(pass, call) && wrapFuncs[calledFunc(pass, call).Name()]
+}
+
+// wrapArg returns the local variable holding the error passed to call.
+func wrapArg(pass *analysis.Pass, call *ast.CallExpr) types.Object {
+	for _, arg := range call.Args {
+		if isError(pass.TypesInfo.TypeOf(arg)) {
+			return localObject(pass, arg)
+		}
+	}
+	return nil
+}
+
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+// localObject returns the function-local variable expr refers to,
+// or nil if it is anything else.
+func localObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+
+	obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok || obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+		return nil
+	}
+	return obj
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func isError(t types.Type) bool {
+	return t != nil && types.Identical(t, errorType)
+}
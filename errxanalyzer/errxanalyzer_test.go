@@ -0,0 +1,41 @@
+package errxanalyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	require.NoError(t, Analyzer.Flags.Set("packages", "app/internal"))
+	t.Cleanup(func() { Analyzer.Flags.Set("packages", "") })
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "app/internal/db", "app/other")
+}
+
+func TestEnforced(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flag     string
+		pkg      string
+		expected bool
+	}{
+		{"no packages configured", "", "app/other", true},
+		{"exact match", "app/internal", "app/internal", true},
+		{"sub package", "app/internal", "app/internal/db", true},
+		{"name prefix only", "app/internal", "app/internalx", false},
+		{"several prefixes", "app/api, app/internal", "app/internal/db", true},
+		{"not listed", "app/internal", "app/other", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, Analyzer.Flags.Set("packages", tc.flag))
+			t.Cleanup(func() { Analyzer.Flags.Set("packages", "") })
+
+			assert.Equal(t, tc.expected, enforced(tc.pkg))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/alesr/errx"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func query() error { return nil }
+
+func unwrapped() error {
+	if err := query(); err != nil {
+		return err // want `error err returned without errx.Wrap`
+	}
+	return nil
+}
+
+func unwrappedWithValue() (int, error) {
+	err := query()
+	return 0, err // want `error err returned without errx.Wrap`
+}
+
+func wrapped() error {
+	if err := query(); err != nil {
+		return errx.Wrap(err)
+	}
+	return nil
+}
+
+func wrappedBeforeReturn() error {
+	err := query()
+	if err != nil {
+		err = errx.Wrapf(err, "querying")
+		return err
+	}
+	return nil
+}
+
+func withAttribute() error {
+	err := query()
+	err = errx.With(err, "table", "users")
+	return err
+}
+
+func sentinel() error {
+	return ErrNotFound
+}
+
+func wrapper(w *errx.Wrapper) error {
+	err := query()
+	err = w.Wrap(err)
+	return err
+}
+
+func closure() func() error {
+	return func() error {
+		err := query()
+		return err // want `error err returned without errx.Wrap`
+	}
+}
+
+func doubleWrap() error {
+	err := query()
+	err = errx.Wrap(err)
+	return errx.Wrap(err) // want `error err already wrapped in this function`
+}
+
+func nestedWrap() error {
+	return errx.Wrap(errx.Wrap(query())) // want `error wrapped twice in the same expression`
+}
@@ -0,0 +1,47 @@
+package other
+
+import (
+	"context"
+
+	"github.com/alesr/errx"
+)
+
+func query() error { return nil }
+
+func notEnforced() error {
+	err := query()
+	return err
+}
+
+func doubleWrap() error {
+	err := query()
+	err = errx.Wrap(err)
+	err = errx.Wrap(err) // want `error err already wrapped in this function`
+	return err
+}
+
+func wrapTwoErrors() error {
+	first := errx.Wrap(query())
+	second := errx.Wrap(query())
+	if first != nil {
+		return errx.Wrap(second)
+	}
+	return first
+}
+
+func doubleWrapEntryPoints(ctx context.Context, pcs []uintptr) error {
+	err := query()
+	err = errx.Boundary(err)
+	err = errx.WithArgs(err, 42)                           // want `error err already wrapped in this function`
+	err = errx.WrapCtx(ctx, err)                           // want `error err already wrapped in this function`
+	err = errx.WrapPCs(err, pcs)                           // want `error err already wrapped in this function`
+	err = errx.WrapFatal(err)                              // want `error err already wrapped in this function`
+	return errx.WrapLazy(err, func() string { return "" }) // want `error err already wrapped in this function`
+}
+
+func conditionalWraps(retry bool) error {
+	err := query()
+	err = errx.Wrap(err)
+	err = errx.WrapIf(retry, err)
+	return errx.WrapOnce(err)
+}
@@ -0,0 +1,30 @@
+// Package errx is a stub of the real package for analyzer tests.
+package errx
+
+import "context"
+
+func Wrap(err error) error { return err }
+
+func Wrapf(err error, format string, args ...any) error { return err }
+
+func WrapIf(cond bool, err error) error { return err }
+
+func WrapOnce(err error) error { return err }
+
+func WrapLazy(err error, fn func() string) error { return err }
+
+func WrapCtx(ctx context.Context, err error) error { return err }
+
+func WrapPCs(err error, pcs []uintptr) error { return err }
+
+func WrapFatal(err error) error { return err }
+
+func Boundary(err error) error { return err }
+
+func WithArgs(err error, values ...any) error { return err }
+
+func With(err error, key string, value any) error { return err }
+
+type Wrapper struct{}
+
+func (w *Wrapper) Wrap(err error) error { return err }
@@ -0,0 +1,128 @@
+// Package errxcockroach converts errors built with
+// github.com/cockroachdb/errors into errx errors and back: the stacks
+// recorded by cockroach become the errx frames, and its details, hints,
+// telemetry keys and issue links become errx attributes and hints.
+package errxcockroach
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/errbase"
+
+	"github.com/alesr/errx"
+)
+
+// Keys of the attributes attached by From.
+const (
+	// AttrDetails holds the details attached with errors.WithDetail, as
+	// a []string, innermost first.
+	AttrDetails = "cockroach.details"
+	// AttrTelemetryKeys holds the keys attached with errors.WithTelemetry,
+	// as a []string.
+	AttrTelemetryKeys = "cockroach.telemetry_keys"
+	// AttrIssueLinks holds the URLs of the issues linked with
+	// errors.WithIssueLink, as a []string.
+	AttrIssueLinks = "cockroach.issue_links"
+)
+
+// From returns an errx error holding the stacks recorded along err's chain
+// by cockroach, with err as its cause, so errors.Is and errors.As keep
+// matching. The call site of every cockroach wrap recording a stack is
+// listed first, newest first, followed by the stack recorded by the
+// innermost one, innermost call first, like errx lists the frames it
+// captures. Details, telemetry keys and issue links are attached as the
+// attributes above, and hints are joined into the errx hint.
+// Errors holding no cockroach stack, or holding errx frames already, are
+// returned as they are.
+// Returns nil if err is nil.
+func From(err error) error {
+	if err == nil || len(errx.Frames(err)) > 0 {
+		return err
+	}
+
+	var stacks []errbase.StackTrace
+	errx.Walk(err, func(err error) bool {
+		if p, ok := err.(errbase.StackTraceProvider); ok {
+			if st := p.StackTrace(); len(st) > 0 {
+				stacks = append(stacks, st)
+			}
+		}
+		return true
+	})
+	if len(stacks) == 0 {
+		return err
+	}
+
+	var frames []errx.Frame
+	for _, st := range stacks[:len(stacks)-1] {
+		frames = append(frames, frame(st[0], true))
+	}
+	for i, f := range stacks[len(stacks)-1] {
+		frames = append(frames, frame(f, i == 0))
+	}
+
+	adopted := errx.Adopt(err, frames, attrs(err)...)
+	if hints := errors.GetAllHints(err); len(hints) > 0 {
+		adopted = errx.WithHint(adopted, strings.Join(hints, "\n"))
+	}
+	return adopted
+}
+
+// To returns err annotated the cockroach way, so it reads well in code
+// built around cockroach: every errx attribute becomes a detail, as
+// "key: value", and the errx hint a hint. The attributes set by From are
+// left out, err holding them already.
+// Returns nil if err is nil.
+func To(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, a := range errx.Attrs(err) {
+		if strings.HasPrefix(a.Key, "cockroach.") {
+			continue
+		}
+		err = errors.WithDetailf(err, "%s: %v", a.Key, a.Value)
+	}
+	if hint := errx.Hint(err); hint != "" && len(errors.GetAllHints(err)) == 0 {
+		err = errors.WithHint(err, hint)
+	}
+	return err
+}
+
+// attrs returns the attributes describing the cockroach annotations of err.
+func attrs(err error) []errx.Attr {
+	var attrs []errx.Attr
+	if details := errors.GetAllDetails(err); len(details) > 0 {
+		attrs = append(attrs, errx.Attr{Key: AttrDetails, Value: details})
+	}
+	if keys := errors.GetTelemetryKeys(err); len(keys) > 0 {
+		attrs = append(attrs, errx.Attr{Key: AttrTelemetryKeys, Value: keys})
+	}
+	if links := errors.GetAllIssueLinks(err); len(links) > 0 {
+		urls := make([]string, 0, len(links))
+		for _, link := range links {
+			if link.IssueURL != "" {
+				urls = append(urls, link.IssueURL)
+			}
+		}
+		if len(urls) > 0 {
+			attrs = append(attrs, errx.Attr{Key: AttrIssueLinks, Value: urls})
+		}
+	}
+	return attrs
+}
+
+// frame resolves a frame of a cockroach stack, which holds the return
+// address of the call.
+func frame(f errbase.StackFrame, wrapSite bool) errx.Frame {
+	rf, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+	return errx.Frame{
+		Function: rf.Function,
+		File:     rf.File,
+		Line:     rf.Line,
+		WrapSite: wrapSite,
+	}
+}
@@ -0,0 +1,83 @@
+package errxcockroach
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	crdb "github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/errx"
+)
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("adopts stacks and annotations", func(t *testing.T) {
+		t.Parallel()
+
+		root := crdb.New("not found")
+		err := crdb.Wrap(root, "loading user")
+		err = crdb.WithDetail(err, "user 42")
+		err = crdb.WithHint(err, "check the user ID")
+		err = crdb.WithTelemetry(err, "users.load")
+		err = crdb.WithIssueLink(err, crdb.IssueLink{IssueURL: "https://example.com/issues/1"})
+
+		got := From(err)
+		assert.ErrorIs(t, got, root)
+		assert.Contains(t, got.Error(), "loading user: not found")
+		assert.NotEmpty(t, errx.ID(got))
+		// cockroach lists issue links among the hints too
+		assert.Equal(t, "check the user ID\nSee: https://example.com/issues/1", errx.Hint(got))
+
+		details, _ := errx.Value[[]string](got, AttrDetails)
+		assert.Equal(t, []string{"user 42"}, details)
+		keys, _ := errx.Value[[]string](got, AttrTelemetryKeys)
+		assert.Equal(t, []string{"users.load"}, keys)
+		links, _ := errx.Value[[]string](got, AttrIssueLinks)
+		assert.Equal(t, []string{"https://example.com/issues/1"}, links)
+
+		frames := errx.Frames(got)
+		require.GreaterOrEqual(t, len(frames), 3)
+		// the frame of Wrap, then the stack of New
+		for _, f := range frames[:2] {
+			assert.True(t, f.WrapSite)
+			assert.True(t, strings.HasSuffix(f.File, "errxcockroach_test.go"), f.File)
+		}
+		assert.Less(t, frames[1].Line, frames[0].Line)
+		assert.False(t, frames[2].WrapSite)
+	})
+
+	t.Run("returns other errors as they are", func(t *testing.T) {
+		t.Parallel()
+
+		plain := errors.New("boom")
+		assert.Same(t, plain, From(plain))
+
+		wrapped := errx.Wrap(crdb.New("boom"))
+		assert.Same(t, wrapped, From(wrapped))
+
+		assert.NoError(t, From(nil))
+	})
+}
+
+func TestTo(t *testing.T) {
+	t.Parallel()
+
+	err := errx.WithHint(errx.With(io.EOF, "table", "users"), "retry later")
+
+	got := To(err)
+	assert.ErrorIs(t, got, io.EOF)
+	assert.Equal(t, []string{"table: users"}, crdb.GetAllDetails(got))
+	assert.Equal(t, []string{"retry later"}, crdb.GetAllHints(got))
+
+	// attributes and hints read from cockroach aren't added twice
+	back := To(From(crdb.WithHint(crdb.WithDetail(crdb.New("boom"), "detail"), "hint")))
+	assert.Equal(t, []string{"detail"}, crdb.GetAllDetails(back))
+	assert.Equal(t, []string{"hint"}, crdb.GetAllHints(back))
+
+	assert.NoError(t, To(nil))
+}
@@ -0,0 +1,31 @@
+module github.com/alesr/errx/errxcockroach
+
+go 1.24.0
+
+require github.com/alesr/errx v0.0.0-20261016170111-61bccbc30cb2
+
+require (
+	github.com/cockroachdb/errors v1.12.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/cockroachdb/logtags v0.0.0-20230118201751-21c54148d20b // indirect
+	github.com/cockroachdb/redact v1.1.5 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/getsentry/sentry-go v0.27.0 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.14.1 // indirect
+	golang.org/x/sys v0.37.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// build against the errx of this repository; modules depending on this one
+// ignore the replacement and get the version required above
+replace github.com/alesr/errx => ../
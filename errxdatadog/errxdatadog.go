@@ -0,0 +1,98 @@
+// Package errxdatadog fills the attributes Datadog Error Tracking expects
+// from errx errors, so they are grouped by their captured frames instead
+// of showing up as opaque one-liners.
+package errxdatadog
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/alesr/errx"
+)
+
+// Attribute keys read by Datadog Error Tracking.
+const (
+	KeyMessage     = "error.message"
+	KeyKind        = "error.kind"
+	KeyStack       = "error.stack"
+	KeyFingerprint = "error.fingerprint"
+)
+
+// Error holds the error.* attributes of a log event. It marshals into
+// the nested "error" object Datadog reads, and can be logged with slog
+// under the "error" key for the same result.
+type Error struct {
+	Message     string `json:"message"`
+	Kind        string `json:"kind"`
+	Stack       string `json:"stack,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// New builds the Datadog attributes for err. The message is the compact
+// rendering of the chain, the kind the Go type of the root cause, and the
+// stack the captured frames in the Go stack trace format Datadog parses.
+// Errors carrying frames also get errx.Fingerprint as their fingerprint,
+// which keeps them grouped across line changes.
+// Returns the zero Error if err is nil.
+func New(err error) Error {
+	if err == nil {
+		return Error{}
+	}
+
+	e := Error{
+		Message: err.Error(),
+		Kind:    errx.CauseType(err),
+	}
+
+	frames := errx.Frames(err)
+	if len(frames) == 0 {
+		return e
+	}
+
+	var stack strings.Builder
+	stack.WriteString("goroutine 1 [running]:\n")
+	for _, frame := range frames {
+		stack.WriteString(frame.Function + "()\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + "\n")
+	}
+	e.Stack = stack.String()
+	e.Fingerprint = errx.Fingerprint(err)
+	return e
+}
+
+// Attributes returns the attributes of New(err) under their dotted keys,
+// for loggers taking flat fields. Empty values are left out.
+// Returns nil if err is nil.
+func Attributes(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	e := New(err)
+	attrs := map[string]any{
+		KeyMessage: e.Message,
+		KeyKind:    e.Kind,
+	}
+	if e.Stack != "" {
+		attrs[KeyStack] = e.Stack
+	}
+	if e.Fingerprint != "" {
+		attrs[KeyFingerprint] = e.Fingerprint
+	}
+	return attrs
+}
+
+// LogValue implements slog.LogValuer, rendering e as a group.
+func (e Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("message", e.Message),
+		slog.String("kind", e.Kind),
+	}
+	if e.Stack != "" {
+		attrs = append(attrs, slog.String("stack", e.Stack))
+	}
+	if e.Fingerprint != "" {
+		attrs = append(attrs, slog.String("fingerprint", e.Fingerprint))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,74 @@
+package errxdatadog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Error{}, New(nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Error{Message: "boom", Kind: "*errors.errorString"}, New(errors.New("boom")))
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.Wrap(errors.New("boom"))
+
+		e := New(err)
+
+		assert.Equal(t, err.Error(), e.Message)
+		assert.Equal(t, "*errors.errorString", e.Kind)
+		assert.Equal(t, errx.Fingerprint(err), e.Fingerprint)
+		assert.Regexp(t, `^goroutine 1 \[running\]:\n`+
+			`github\.com/alesr/errx/errxdatadog\.TestNew\.func3\(\)\n\t.*/errxdatadog_test\.go:\d+\n`, e.Stack)
+	})
+}
+
+func TestAttributes(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, Attributes(nil))
+
+	assert.Equal(t, map[string]any{
+		KeyMessage: "boom",
+		KeyKind:    "*errors.errorString",
+	}, Attributes(errors.New("boom")))
+
+	attrs := Attributes(errx.Wrap(errors.New("boom")))
+	assert.Contains(t, attrs, KeyStack)
+	assert.Contains(t, attrs, KeyFingerprint)
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := errx.Wrap(errors.New("boom"))
+	logger.Error("request failed", "error", New(err))
+
+	var entry struct {
+		Error Error `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, New(err), entry.Error)
+}
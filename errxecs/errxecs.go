@@ -0,0 +1,64 @@
+// Package errxecs maps errx errors onto Elastic Common Schema fields, for
+// logs shipped to Elasticsearch indices with ECS-enforced mappings.
+package errxecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alesr/errx"
+)
+
+// Fields holds the ECS fields describing an error. It marshals into the
+// nested "error" and "labels" objects expected by ECS.
+type Fields struct {
+	Error  Error             `json:"error"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Error holds the ECS error.* fields.
+type Error struct {
+	ID         string `json:"id,omitempty"`
+	Message    string `json:"message"`
+	StackTrace string `json:"stack_trace,omitempty"`
+	Type       string `json:"type"`
+}
+
+// New maps err onto ECS fields. error.message is the compact rendering
+// of the chain, error.stack_trace lists one frame per line, error.type is
+// the Go type of the root cause and error.id the support ID. Attributes
+// become labels: ECS labels are flat keyword values, so keys have their
+// dots replaced by underscores and values are formatted with fmt.
+// Returns the zero Fields if err is nil.
+func New(err error) Fields {
+	if err == nil {
+		return Fields{}
+	}
+
+	r := errx.NewRecord(err)
+	f := Fields{
+		Error: Error{
+			ID:      r.ID,
+			Message: r.Error,
+			Type:    errx.CauseType(err),
+		},
+	}
+
+	var stack strings.Builder
+	for _, frame := range r.Frames {
+		stack.WriteString(frame.String())
+		if frame.Message != "" {
+			stack.WriteString(": " + frame.Message)
+		}
+		stack.WriteByte('\n')
+	}
+	f.Error.StackTrace = stack.String()
+
+	if len(r.Attrs) > 0 {
+		f.Labels = make(map[string]string, len(r.Attrs))
+		for _, attr := range r.Attrs {
+			f.Labels[strings.ReplaceAll(attr.Key, ".", "_")] = fmt.Sprint(attr.Value)
+		}
+	}
+	return f
+}
@@ -0,0 +1,70 @@
+package errxecs
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Fields{}, New(nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Fields{
+			Error: Error{Message: "boom", Type: "*errors.errorString"},
+		}, New(errors.New("boom")))
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		cause := &fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist}
+		err := errx.With(cause, "retry.attempt", 2)
+		err = errx.Wrapf(err, "loading config")
+
+		f := New(err)
+
+		assert.Equal(t, errx.ID(err), f.Error.ID)
+		assert.Equal(t, err.Error(), f.Error.Message)
+		assert.Equal(t, "*errors.errorString", f.Error.Type)
+		assert.Equal(t, map[string]string{"os_op": "open", "os_path": "/etc/app.yaml", "retry_attempt": "2"}, f.Labels)
+
+		lines := strings.Split(strings.TrimSuffix(f.Error.StackTrace, "\n"), "\n")
+		require.Len(t, lines, len(errx.Frames(err)))
+		assert.Regexp(t, `^errxecs\.TestNew\.func3 \(errxecs_test\.go:\d+\): loading config$`, lines[0])
+	})
+}
+
+func TestFieldsJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Fields{
+		Error:  Error{ID: "7KQ2-M9XD", Message: "boom", StackTrace: "db.Query (db.go:42)\n", Type: "*errors.errorString"},
+		Labels: map[string]string{"tenant": "acme"},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"error": {
+			"id": "7KQ2-M9XD",
+			"message": "boom",
+			"stack_trace": "db.Query (db.go:42)\n",
+			"type": "*errors.errorString"
+		},
+		"labels": {"tenant": "acme"}
+	}`, string(data))
+}
@@ -0,0 +1,73 @@
+// Package errxeris converts errors built with github.com/rotisserie/eris
+// into errx errors, so codebases mixing both render every error the errx
+// way. The stack recorded by eris becomes the errx frames, its wrap calls
+// the wrap sites.
+package errxeris
+
+import (
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/alesr/errx"
+)
+
+// From returns an errx error holding the frames recorded by the first
+// eris error of err's chain, with err as its cause, so errors.Is and
+// errors.As keep matching. The frames of eris wrap calls are listed first,
+// newest first, followed by the stack recorded by eris.New, innermost
+// call first, like errx lists the frames it captures.
+// Errors holding no eris error, or holding errx frames already, are
+// returned as they are.
+// Returns nil if err is nil.
+func From(err error) error {
+	if err == nil || len(errx.Frames(err)) > 0 {
+		return err
+	}
+
+	var unpacked eris.UnpackedError
+	errx.Walk(err, func(err error) bool {
+		unpacked = eris.Unpack(err)
+		return !isEris(unpacked)
+	})
+	if !isEris(unpacked) {
+		return err
+	}
+	return errx.Adopt(err, Frames(unpacked))
+}
+
+// Frames converts the stack and wrap sites of an unpacked eris error into
+// errx frames, in the order described by From.
+func Frames(unpacked eris.UnpackedError) []errx.Frame {
+	var (
+		frames    []errx.Frame
+		wrapSites = make(map[eris.StackFrame]bool)
+	)
+	for _, link := range slices.Backward(unpacked.ErrChain) {
+		frames = append(frames, frame(link.Frame, true))
+		wrapSites[link.Frame] = true
+	}
+	for i, f := range unpacked.ErrRoot.Stack {
+		// eris inserts the wrap sites in the root stack as well
+		if wrapSites[f] {
+			continue
+		}
+		frames = append(frames, frame(f, i == 0))
+	}
+	return frames
+}
+
+// isEris reports whether unpacked comes from an eris error.
+func isEris(unpacked eris.UnpackedError) bool {
+	return len(unpacked.ErrChain) > 0 || len(unpacked.ErrRoot.Stack) > 0
+}
+
+// frame converts an eris stack frame.
+func frame(f eris.StackFrame, wrapSite bool) errx.Frame {
+	return errx.Frame{
+		Function: f.Name,
+		File:     f.File,
+		Line:     f.Line,
+		WrapSite: wrapSite,
+	}
+}
@@ -0,0 +1,61 @@
+package errxeris
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/errx"
+)
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("adopts the eris stack", func(t *testing.T) {
+		t.Parallel()
+
+		root := eris.New("not found")
+		err := eris.Wrap(root, "loading user")
+
+		got := From(err)
+		// the wrap site comes first, then the frame of eris.New
+		assert.Equal(t, "errxeris.TestFrom.func1 (errxeris_test.go:24): errxeris.TestFrom.func1 (errxeris_test.go:23): "+err.Error(), got.Error())
+		assert.ErrorIs(t, got, root)
+		assert.NotEmpty(t, errx.ID(got))
+
+		frames := errx.Frames(got)
+		require.NotEmpty(t, frames)
+		assert.True(t, frames[0].WrapSite)
+		assert.True(t, strings.HasSuffix(frames[0].File, "errxeris_test.go"))
+		assert.Contains(t, frames[0].Function, "TestFrom")
+		assert.Contains(t, fmt.Sprintf("%+v", got), "errxeris_test.go")
+	})
+
+	t.Run("finds eris errors down the chain", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("handler: %w", eris.Wrap(io.EOF, "reading body"))
+
+		got := From(err)
+		assert.ErrorIs(t, got, io.EOF)
+		assert.NotEmpty(t, errx.Frames(got))
+	})
+
+	t.Run("returns other errors as they are", func(t *testing.T) {
+		t.Parallel()
+
+		plain := errors.New("boom")
+		assert.Same(t, plain, From(plain))
+
+		wrapped := errx.Wrap(eris.New("boom"))
+		assert.Same(t, wrapped, From(wrapped))
+
+		assert.NoError(t, From(nil))
+	})
+}
@@ -0,0 +1,21 @@
+module github.com/alesr/errx/errxeris
+
+go 1.24.0
+
+require github.com/alesr/errx v0.0.0-20261016170111-61bccbc30cb2
+
+require (
+	github.com/rotisserie/eris v0.5.4
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// build against the errx of this repository; modules depending on this one
+// ignore the replacement and get the version required above
+replace github.com/alesr/errx => ../
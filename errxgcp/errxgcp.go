@@ -0,0 +1,98 @@
+// Package errxgcp converts errx errors into Google Cloud Error Reporting
+// events, so services on GKE or Cloud Run get errors grouped by their
+// captured frames.
+package errxgcp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alesr/errx"
+)
+
+// LogType is the "@type" value that makes Cloud Logging forward a
+// structured log entry to Error Reporting.
+const LogType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// Event is the ReportedErrorEvent JSON shape accepted by the Error
+// Reporting API and recognized in structured logs.
+type Event struct {
+	// Type is left empty by New. Set it to LogType when writing the
+	// event as a log entry instead of calling the API.
+	Type           string         `json:"@type,omitempty"`
+	EventTime      time.Time      `json:"eventTime"`
+	ServiceContext ServiceContext `json:"serviceContext"`
+	Message        string         `json:"message"`
+	Context        *Context       `json:"context,omitempty"`
+}
+
+// ServiceContext identifies the service reporting the error.
+type ServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+// Context describes the circumstances of the error.
+type Context struct {
+	User           string          `json:"user,omitempty"`
+	ReportLocation *SourceLocation `json:"reportLocation,omitempty"`
+}
+
+// SourceLocation is a location in the source code.
+type SourceLocation struct {
+	FilePath     string `json:"filePath"`
+	LineNumber   int    `json:"lineNumber"`
+	FunctionName string `json:"functionName"`
+}
+
+// New builds the event for err reported by svc. The message holds the
+// error followed by its frames formatted like a Go stack trace, which
+// Error Reporting parses for grouping, and the report location is the
+// origin frame. The event time is when the error was first wrapped.
+// Returns the zero Event if err is nil.
+func New(err error, svc ServiceContext) Event {
+	if err == nil {
+		return Event{}
+	}
+
+	ev := Event{
+		EventTime:      errx.FirstSeen(err),
+		ServiceContext: svc,
+		Message:        err.Error(),
+	}
+	if ev.EventTime.IsZero() {
+		ev.EventTime = time.Now()
+	}
+
+	if frames := errx.Frames(err); len(frames) > 0 {
+		ev.Message += "\n\n" + Stack(frames)
+	}
+
+	if origin, ok := errx.Origin(err); ok {
+		ev.Context = &Context{
+			ReportLocation: &SourceLocation{
+				FilePath:     origin.File,
+				LineNumber:   origin.Line,
+				FunctionName: origin.Function,
+			},
+		}
+	}
+	return ev
+}
+
+// Stack formats frames the way runtime.Stack does, under a single
+// goroutine header.
+func Stack(frames []errx.Frame) string {
+	var b strings.Builder
+	b.WriteString("goroutine 1 [running]:\n")
+	for _, frame := range frames {
+		b.WriteString(frame.Function)
+		b.WriteString("(...)\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
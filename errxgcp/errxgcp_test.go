@@ -0,0 +1,85 @@
+package errxgcp
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	svc := ServiceContext{Service: "billing", Version: "1.4.2"}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Event{}, New(nil, svc))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		ev := New(errors.New("boom"), svc)
+
+		assert.Equal(t, "boom", ev.Message)
+		assert.Nil(t, ev.Context)
+		assert.WithinDuration(t, time.Now(), ev.EventTime, time.Minute)
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		origin := func() error {
+			return errx.Wrap(errors.New("connection refused"))
+		}
+		err := errx.Wrap(origin())
+
+		ev := New(err, svc)
+
+		assert.Equal(t, svc, ev.ServiceContext)
+		assert.Equal(t, errx.FirstSeen(err), ev.EventTime)
+		assert.Regexp(t, `^`+regexp.QuoteMeta(err.Error())+`\n\ngoroutine 1 \[running\]:\n`+
+			`github\.com/alesr/errx/errxgcp\.TestNew\.func3\(\.\.\.\)\n\t.*/errxgcp_test\.go:\d+\n`+
+			`github\.com/alesr/errx/errxgcp\.TestNew\.func3\.1\(\.\.\.\)\n\t.*/errxgcp_test\.go:\d+\n`, ev.Message)
+
+		require.NotNil(t, ev.Context)
+		require.NotNil(t, ev.Context.ReportLocation)
+		assert.Equal(t, "github.com/alesr/errx/errxgcp.TestNew.func3.1", ev.Context.ReportLocation.FunctionName)
+		assert.Contains(t, ev.Context.ReportLocation.FilePath, "errxgcp_test.go")
+		assert.Positive(t, ev.Context.ReportLocation.LineNumber)
+	})
+}
+
+func TestEventJSON(t *testing.T) {
+	t.Parallel()
+
+	ev := Event{
+		Type:           LogType,
+		EventTime:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		ServiceContext: ServiceContext{Service: "billing"},
+		Message:        "boom",
+		Context: &Context{
+			ReportLocation: &SourceLocation{FilePath: "/app/db.go", LineNumber: 42, FunctionName: "app/db.Query"},
+		},
+	}
+
+	data, err := json.Marshal(ev)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"@type": "`+LogType+`",
+		"eventTime": "2024-05-01T12:00:00Z",
+		"serviceContext": {"service": "billing"},
+		"message": "boom",
+		"context": {
+			"reportLocation": {"filePath": "/app/db.go", "lineNumber": 42, "functionName": "app/db.Query"}
+		}
+	}`, string(data))
+}
@@ -0,0 +1,118 @@
+// Package errxgelf renders errx errors as GELF messages, so they can be
+// sent to Graylog without a translation layer.
+package errxgelf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/alesr/errx"
+)
+
+// Version is the GELF specification version messages comply with.
+const Version = "1.1"
+
+// LevelError is the syslog severity used for messages by default.
+const LevelError = 3
+
+// invalidFieldChars matches characters GELF doesn't allow in field names.
+var invalidFieldChars = regexp.MustCompile(`[^\w.\-]`)
+
+// Message is a GELF message built from an error.
+type Message struct {
+	Host         string
+	ShortMessage string
+	FullMessage  string
+	Timestamp    time.Time
+	Level        int
+	// Fields holds the additional fields, keyed without their leading
+	// underscore. Values are strings or numbers.
+	Fields map[string]any
+}
+
+// New builds a GELF message for err reported by host. The short message
+// is errx.Summary, the full message the verbose %+v rendering, and the
+// timestamp the last time the error was wrapped. The support ID and
+// fingerprint are added as err_id and err_fingerprint, followed by the
+// attributes with their keys sanitized to what GELF allows.
+// Returns the zero Message if err is nil.
+func New(err error, host string) Message {
+	if err == nil {
+		return Message{}
+	}
+
+	m := Message{
+		Host:         host,
+		ShortMessage: errx.Summary(err),
+		FullMessage:  fmt.Sprintf("%+v", err),
+		Timestamp:    errx.LastSeen(err),
+		Level:        LevelError,
+		Fields:       make(map[string]any),
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+
+	for _, attr := range errx.Attrs(err) {
+		m.Fields[fieldName(attr.Key)] = fieldValue(attr.Value)
+	}
+	if id := errx.ID(err); id != "" {
+		m.Fields["err_id"] = id
+	}
+	m.Fields["err_fingerprint"] = errx.Fingerprint(err)
+	return m
+}
+
+// MarshalJSON encodes m as a GELF payload, with additional fields
+// prefixed by an underscore at the top level.
+func (m Message) MarshalJSON() ([]byte, error) {
+	payload := make(map[string]any, len(m.Fields)+6)
+	for key, value := range m.Fields {
+		payload["_"+key] = value
+	}
+
+	payload["version"] = Version
+	payload["host"] = m.Host
+	payload["short_message"] = m.ShortMessage
+	if m.FullMessage != "" {
+		payload["full_message"] = m.FullMessage
+	}
+	if !m.Timestamp.IsZero() {
+		// seconds since the epoch with millisecond precision
+		payload["timestamp"] = math.Round(float64(m.Timestamp.UnixMilli())) / 1000
+	}
+	payload["level"] = m.Level
+	return json.Marshal(payload)
+}
+
+// Marshal is a shorthand for encoding New(err, host).
+func Marshal(err error, host string) ([]byte, error) {
+	return json.Marshal(New(err, host))
+}
+
+// fieldName sanitizes key into a valid GELF additional field name.
+// "id" is reserved by GELF, so it is renamed.
+func fieldName(key string) string {
+	name := invalidFieldChars.ReplaceAllString(key, "_")
+	if name == "id" {
+		return "id_"
+	}
+	return name
+}
+
+// fieldValue converts v into a string or a number, the only additional
+// field value types GELF accepts.
+func fieldValue(v any) any {
+	switch v := v.(type) {
+	case string, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case time.Duration:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,93 @@
+package errxgelf
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Message{}, New(nil, "web-1"))
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.With(errors.New("connection refused"), "user id", 42)
+		err = errx.With(err, "id", "abc")
+		err = errx.With(err, "timeout", 3*time.Second)
+
+		m := New(err, "web-1")
+
+		assert.Equal(t, "web-1", m.Host)
+		assert.Equal(t, errx.Summary(err), m.ShortMessage)
+		assert.Contains(t, m.FullMessage, "[0] ")
+		assert.Equal(t, errx.LastSeen(err), m.Timestamp)
+		assert.Equal(t, LevelError, m.Level)
+		assert.Equal(t, map[string]any{
+			"user_id":         42,
+			"id_":             "abc",
+			"timeout":         "3s",
+			"err_id":          errx.ID(err),
+			"err_fingerprint": errx.Fingerprint(err),
+		}, m.Fields)
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		m := New(errors.New("boom"), "web-1")
+
+		assert.Equal(t, "boom", m.ShortMessage)
+		assert.WithinDuration(t, time.Now(), m.Timestamp, time.Minute)
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	m := Message{
+		Host:         "web-1",
+		ShortMessage: "db.Query (db.go:42): connection refused",
+		FullMessage:  "[0] db.Query (db.go:42): connection refused\n",
+		Timestamp:    time.UnixMilli(1700000000123),
+		Level:        LevelError,
+		Fields:       map[string]any{"err_id": "7KQ2-M9XD", "attempt": 2},
+	}
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"version": "1.1",
+		"host": "web-1",
+		"short_message": "db.Query (db.go:42): connection refused",
+		"full_message": "[0] db.Query (db.go:42): connection refused\n",
+		"timestamp": 1700000000.123,
+		"level": 3,
+		"_err_id": "7KQ2-M9XD",
+		"_attempt": 2
+	}`, string(data))
+
+	t.Run("shorthand", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Marshal(errx.Wrap(errors.New("boom")), "web-1")
+		require.NoError(t, err)
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(data, &payload))
+		assert.Equal(t, "1.1", payload["version"])
+		assert.Contains(t, payload, "_err_id")
+	})
+}
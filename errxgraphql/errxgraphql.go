@@ -0,0 +1,43 @@
+// Package errxgraphql renders errx errors as the extensions of GraphQL
+// errors. It depends on no GraphQL package: the result is a plain map,
+// which gqlgen's gqlerror.Error and graph-gophers' errors both accept.
+package errxgraphql
+
+import "github.com/alesr/errx"
+
+// Extensions returns the members describing err in the extensions of a
+// GraphQL error: its support ID as "id", code as "code" and kind as
+// "kind", its hint as "hint", and the messages recorded with
+// errx.WithFieldError as "fieldErrors", keyed by field:
+//
+//	return &gqlerror.Error{
+//	    Message:    errx.PublicMessage(err),
+//	    Path:       graphql.GetPath(ctx),
+//	    Extensions: errxgraphql.Extensions(err),
+//	}
+//
+// Members that are not set are left out.
+// Returns nil if err is nil.
+func Extensions(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	ext := make(map[string]any)
+	if id := errx.ID(err); id != "" {
+		ext["id"] = id
+	}
+	if code := errx.CodeOf(err); code != "" {
+		ext["code"] = string(code)
+	}
+	if kind := errx.KindOf(err); kind != "" {
+		ext["kind"] = string(kind)
+	}
+	if hint := errx.Hint(err); hint != "" {
+		ext["hint"] = hint
+	}
+	if fields := errx.FieldErrors(err); len(fields) > 0 {
+		ext["fieldErrors"] = fields
+	}
+	return ext
+}
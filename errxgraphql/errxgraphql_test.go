@@ -0,0 +1,41 @@
+package errxgraphql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Extensions(nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Extensions(errors.New("plain")))
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.WithFieldError(nil, "email", "must be a valid address")
+		err = errx.WithCode(err, "signup.invalid")
+		err = errx.WithHint(err, "fix the highlighted fields")
+
+		assert.Equal(t, map[string]any{
+			"id":          errx.ID(err),
+			"code":        "signup.invalid",
+			"kind":        "invalid",
+			"hint":        "fix the highlighted fields",
+			"fieldErrors": map[string]string{"email": "must be a valid address"},
+		}, Extensions(err))
+	})
+}
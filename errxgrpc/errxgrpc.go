@@ -0,0 +1,107 @@
+// Package errxgrpc brings the frames reported by upstream gRPC services
+// into local errx chains, so a failure reads as one trace across services.
+// It depends on no gRPC package: status details are matched by the
+// methods their generated types have.
+package errxgrpc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alesr/errx"
+)
+
+// DebugInfo matches the generated google.rpc.DebugInfo message,
+// *errdetails.DebugInfo, whose stack entries servers fill with their
+// stack trace.
+type DebugInfo interface {
+	GetStackEntries() []string
+	GetDetail() string
+}
+
+// errxEntry matches stack entries rendered by errx, "pkg.Func (file.go:42)".
+var errxEntry = regexp.MustCompile(`^(.+) \((.+):(\d+)\)$`)
+
+// RemoteFrames converts the stack entries of the DebugInfo details found
+// in details into frames labeled with service, ready to be passed to
+// errx.WithForeignFrames:
+//
+//	st := status.Convert(err)
+//	err = errx.WithForeignFrames(errx.Wrap(err), errxgrpc.RemoteFrames("billing", st.Details()...)...)
+//
+// Entries rendered by errx, as "pkg.Func (file.go:42)", and the lines of
+// a Go stack trace, as printed by runtime/debug.Stack, are understood;
+// other entries become frames holding only a function name. Other
+// details are ignored.
+func RemoteFrames(service string, details ...any) []errx.Frame {
+	var frames []errx.Frame
+	for _, detail := range details {
+		info, ok := detail.(DebugInfo)
+		if !ok {
+			continue
+		}
+		for _, frame := range parseStack(info.GetStackEntries()) {
+			frame.Service = service
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// parseStack converts stack entries into frames.
+func parseStack(entries []string) []errx.Frame {
+	var frames []errx.Frame
+	for i := 0; i < len(entries); i++ {
+		entry := strings.TrimSpace(entries[i])
+		if entry == "" || strings.HasPrefix(entry, "goroutine ") {
+			continue
+		}
+
+		if m := errxEntry.FindStringSubmatch(entry); m != nil {
+			line, _ := strconv.Atoi(m[3])
+			frames = append(frames, errx.Frame{Function: m[1], File: m[2], Line: line})
+			continue
+		}
+
+		// Go stack traces list the call, then its location indented
+		frame := errx.Frame{Function: trimCall(entry)}
+		if i+1 < len(entries) && strings.HasPrefix(entries[i+1], "\t") {
+			frame.File, frame.Line = parseLocation(entries[i+1])
+			i++
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// trimCall strips the argument list of a call line of a Go stack trace,
+// e.g. "main.run(0x1, {0x2, 0x3})".
+func trimCall(entry string) string {
+	if !strings.HasSuffix(entry, ")") {
+		return entry
+	}
+	if i := strings.LastIndex(entry, "("); i > 0 {
+		return entry[:i]
+	}
+	return entry
+}
+
+// parseLocation parses a location line of a Go stack trace,
+// "\t/src/main.go:42 +0x1d".
+func parseLocation(entry string) (string, int) {
+	loc := strings.TrimSpace(entry)
+	if i := strings.LastIndex(loc, " +0x"); i > 0 {
+		loc = loc[:i]
+	}
+
+	i := strings.LastIndex(loc, ":")
+	if i < 0 {
+		return loc, 0
+	}
+	line, err := strconv.Atoi(loc[i+1:])
+	if err != nil {
+		return loc, 0
+	}
+	return loc[:i], line
+}
@@ -0,0 +1,84 @@
+package errxgrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// debugInfo stands in for *errdetails.DebugInfo.
+type debugInfo struct {
+	stack  []string
+	detail string
+}
+
+func (d *debugInfo) GetStackEntries() []string { return d.stack }
+func (d *debugInfo) GetDetail() string         { return d.detail }
+
+func TestRemoteFrames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("errx entries", func(t *testing.T) {
+		t.Parallel()
+
+		info := &debugInfo{stack: []string{
+			"billing.Charge (charge.go:42)",
+			"stripe.(*Client).Do (client.go:7)",
+		}}
+
+		frames := RemoteFrames("billing", "other detail", info)
+		assert.Equal(t, []errx.Frame{
+			{Function: "billing.Charge", File: "charge.go", Line: 42, Service: "billing"},
+			{Function: "stripe.(*Client).Do", File: "client.go", Line: 7, Service: "billing"},
+		}, frames)
+	})
+
+	t.Run("go stack traces", func(t *testing.T) {
+		t.Parallel()
+
+		info := &debugInfo{stack: []string{
+			"goroutine 12 [running]:",
+			"github.com/acme/billing.(*Service).Charge(0xc000010000, {0x1, 0x2})",
+			"\t/src/billing/charge.go:42 +0x1d",
+			"main.main()",
+			"\t/src/main.go:9 +0x25",
+			"",
+		}}
+
+		frames := RemoteFrames("billing", info)
+		assert.Equal(t, []errx.Frame{
+			{Function: "github.com/acme/billing.(*Service).Charge", File: "/src/billing/charge.go", Line: 42, Service: "billing"},
+			{Function: "main.main", File: "/src/main.go", Line: 9, Service: "billing"},
+		}, frames)
+	})
+
+	t.Run("unknown entries", func(t *testing.T) {
+		t.Parallel()
+
+		frames := RemoteFrames("billing", &debugInfo{stack: []string{"charge failed"}})
+		assert.Equal(t, []errx.Frame{{Function: "charge failed", Service: "billing"}}, frames)
+	})
+
+	t.Run("no debug info", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, RemoteFrames("billing"))
+		assert.Empty(t, RemoteFrames("billing", "detail", 42))
+	})
+
+	t.Run("listed after the local frames", func(t *testing.T) {
+		t.Parallel()
+
+		info := &debugInfo{stack: []string{"billing.Charge (charge.go:42)"}}
+		err := errx.WithForeignFrames(errx.Wrap(errors.New("card declined")), RemoteFrames("billing", info)...)
+
+		frames := errx.Frames(err)
+		require.Greater(t, len(frames), 1)
+		assert.Equal(t, "billing", frames[len(frames)-1].Service)
+		assert.Empty(t, frames[0].Service)
+		assert.Contains(t, err.Error(), ": [billing] billing.Charge (charge.go:42): card declined")
+	})
+}
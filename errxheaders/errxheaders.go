@@ -0,0 +1,186 @@
+// Package errxheaders carries errx errors in message headers, such as
+// Kafka record headers or NATS message headers, so messages sent to a
+// dead-letter queue say why and where their processing failed. Values
+// are kept printable ASCII and the headers of an error within a size
+// budget.
+package errxheaders
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alesr/errx"
+)
+
+// Keys of the headers written by Encode.
+const (
+	// KeyMessage holds the message of the root cause.
+	KeyMessage = "errx-message"
+	// KeyID holds the support ID.
+	KeyID = "errx-id"
+	// KeyCode and KeyKind hold the code and kind of the error, if set.
+	KeyCode = "errx-code"
+	KeyKind = "errx-kind"
+	// KeyOrigin holds the innermost wrap site, e.g.
+	// "db.Query (query.go:42)".
+	KeyOrigin = "errx-origin"
+	// KeyRecord holds the whole record, as encoded by errx.EncodeCompact.
+	KeyRecord = "errx-record"
+)
+
+// base64Prefix marks values holding bytes headers can't, base64 encoded.
+const base64Prefix = "base64:"
+
+// maxMessageSize bounds the size of the message header.
+const maxMessageSize = 256
+
+// Option configures Encode.
+type Option func(*options)
+
+type options struct {
+	maxSize int
+}
+
+// WithMaxSize sets the size budget of the headers of an error, keys
+// included. The record is shortened to its wrap sites, then left out,
+// when it doesn't fit. Defaults to 4096 bytes.
+func WithMaxSize(n int) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// Encode returns the headers describing err. The message, support ID,
+// code, kind and origin come as readable headers, and the whole record as
+// KeyRecord when it fits the size budget, for Decode to rebuild the error.
+// Returns nil if err is nil.
+func Encode(err error, opts ...Option) (map[string]string, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	o := options{maxSize: 4096}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := errx.NewRecord(err)
+	headers := make(map[string]string)
+	size := 0
+	set := func(key, value string) {
+		if value != "" && size+len(key)+len(value) <= o.maxSize {
+			headers[key] = value
+			size += len(key) + len(value)
+		}
+	}
+
+	set(KeyMessage, headerValue(truncate(r.Message, maxMessageSize)))
+	set(KeyID, headerValue(r.ID))
+	set(KeyCode, headerValue(string(r.Code)))
+	set(KeyKind, headerValue(string(r.Kind)))
+	for i := len(r.Frames) - 1; i >= 0; i-- {
+		if r.Frames[i].WrapSite {
+			set(KeyOrigin, headerValue(r.Frames[i].String()))
+			break
+		}
+	}
+
+	record, encErr := errx.EncodeCompact(r.Err())
+	if encErr == nil && size+len(KeyRecord)+len(record) > o.maxSize {
+		wrapSitesOnly(&r)
+		record, encErr = errx.EncodeCompact(r.Err())
+	}
+	if encErr != nil {
+		return nil, fmt.Errorf("could not encode error headers: %w", encErr)
+	}
+	set(KeyRecord, record)
+	return headers, nil
+}
+
+// Decode rebuilds an error from headers written by Encode, from the whole
+// record when there, and from the readable headers otherwise. See
+// errx.Record.Err for how the result relates to the original.
+// Returns nil if headers describe no error.
+func Decode(headers map[string]string) (error, error) {
+	if record, ok := headers[KeyRecord]; ok {
+		return errx.DecodeCompact(record)
+	}
+
+	var (
+		r          errx.Record
+		code, kind string
+	)
+	for _, field := range []struct {
+		key string
+		dst *string
+	}{
+		{KeyMessage, &r.Message},
+		{KeyID, &r.ID},
+		{KeyCode, &code},
+		{KeyKind, &kind},
+	} {
+		value, err := readValue(headers[field.key])
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s header: %w", field.key, err)
+		}
+		*field.dst = value
+	}
+	r.Code, r.Kind = errx.Code(code), errx.Kind(kind)
+	return r.Err(), nil
+}
+
+// wrapSitesOnly drops the frames found by scanning the stack, and the
+// attributes, from r.
+func wrapSitesOnly(r *errx.Record) {
+	frames := r.Frames[:0]
+	for _, frame := range r.Frames {
+		if frame.WrapSite {
+			frames = append(frames, frame)
+		}
+	}
+	r.Frames = frames
+	r.Attrs = nil
+}
+
+// headerValue returns s as is if it is printable ASCII, and base64
+// encoded behind base64Prefix otherwise.
+func headerValue(s string) string {
+	safe := !strings.HasPrefix(s, base64Prefix)
+	for i := 0; i < len(s) && safe; i++ {
+		safe = s[i] >= 0x20 && s[i] < 0x7f
+	}
+	if safe {
+		return s
+	}
+	return base64Prefix + base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// readValue reverses headerValue.
+func readValue(s string) (string, error) {
+	encoded, ok := strings.CutPrefix(s, base64Prefix)
+	if !ok {
+		return s, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// truncate shortens s to at most n bytes, without splitting a rune, and
+// marks the cut with "...".
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// ASCII, so the message needs no base64 for it
+	const ellipsis = "..."
+	cut := n - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
@@ -0,0 +1,105 @@
+package errxheaders
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/errx"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		headers, err := Encode(nil)
+		require.NoError(t, err)
+		assert.Nil(t, headers)
+	})
+
+	t.Run("readable headers and record", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.WithCode(errx.Wrapf(errors.New("connection refused"), "charging card"), "billing.unavailable")
+
+		headers, encErr := Encode(err)
+		require.NoError(t, encErr)
+		assert.Equal(t, "connection refused", headers[KeyMessage])
+		assert.Equal(t, errx.ID(err), headers[KeyID])
+		assert.Equal(t, "billing.unavailable", headers[KeyCode])
+		assert.Regexp(t, `^errxheaders\.TestEncode\.func2 \(errxheaders_test\.go:\d+\)$`, headers[KeyOrigin])
+		assert.NotEmpty(t, headers[KeyRecord])
+
+		decoded, decErr := Decode(headers)
+		require.NoError(t, decErr)
+		assert.Equal(t, err.Error(), decoded.Error())
+		assert.Equal(t, errx.ID(err), errx.ID(decoded))
+		require.Len(t, errx.Frames(decoded), len(errx.Frames(err)))
+		assert.Equal(t, errx.Frames(err)[0].Function, errx.Frames(decoded)[0].Function)
+	})
+
+	t.Run("unsafe values are base64 encoded", func(t *testing.T) {
+		t.Parallel()
+
+		for _, msg := range []string{"line one\nline two", "café fermé", "base64:not really"} {
+			headers, err := Encode(errx.Wrap(errors.New(msg)))
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(headers[KeyMessage], "base64:"), msg)
+
+			delete(headers, KeyRecord)
+			decoded, err := Decode(headers)
+			require.NoError(t, err)
+			assert.Equal(t, msg, decoded.Error())
+		}
+	})
+
+	t.Run("stays within the size budget", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.Wrap(errors.New(strings.Repeat("x", 1000)))
+		for range 20 {
+			err = errx.With(err, "key", strings.Repeat("v", 100))
+		}
+
+		for _, maxSize := range []int{200, 600, 4096} {
+			headers, encErr := Encode(err, WithMaxSize(maxSize))
+			require.NoError(t, encErr)
+
+			size := 0
+			for key, value := range headers {
+				size += len(key) + len(value)
+			}
+			assert.LessOrEqual(t, size, maxSize)
+			if msg, ok := headers[KeyMessage]; ok {
+				assert.True(t, strings.HasSuffix(msg, "..."), "the message is truncated")
+			}
+		}
+
+		// without room for the record, the readable headers remain
+		headers, encErr := Encode(err, WithMaxSize(400))
+		require.NoError(t, encErr)
+		assert.NotContains(t, headers, KeyRecord)
+		decoded, decErr := Decode(headers)
+		require.NoError(t, decErr)
+		assert.Equal(t, errx.ID(err), errx.ID(decoded))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	decoded, err := Decode(nil)
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	_, err = Decode(map[string]string{KeyMessage: "base64:***"})
+	assert.ErrorContains(t, err, "could not decode errx-message header")
+
+	_, err = Decode(map[string]string{KeyRecord: "***"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,246 @@
+// Package errxhttp carries errx errors across HTTP calls between services,
+// so the frames captured by a server show up in the errors of its clients.
+package errxhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alesr/errx"
+)
+
+// ContentType is the media type of response bodies written by WriteError.
+const ContentType = "application/vnd.errx+json"
+
+// maxBodySize bounds how much of a response ReadError is willing to decode.
+const maxBodySize = 1 << 20
+
+// maxExcerptSize bounds the body excerpt kept by FromResponse.
+const maxExcerptSize = 512
+
+// Option configures how WriteError serializes an error, and how
+// RecoverMiddleware handles panics.
+type Option func(*options)
+
+type options struct {
+	status   int
+	sanitize []func(*errx.Record)
+	onError  func(*http.Request, error)
+}
+
+// WithStatus sets the response status code. Defaults to 500.
+func WithStatus(code int) Option {
+	return func(o *options) {
+		o.status = code
+	}
+}
+
+// WithoutFrames leaves the captured frames out of the response,
+// keeping only the message, ID and attributes.
+func WithoutFrames() Option {
+	return WithSanitizer(func(r *errx.Record) {
+		r.Frames = nil
+		r.Error = r.Message
+	})
+}
+
+// WithoutPaths reduces file paths in frames to their base name,
+// hiding the layout of the machine that built the server.
+func WithoutPaths() Option {
+	return WithSanitizer(func(r *errx.Record) {
+		for i := range r.Frames {
+			r.Frames[i].File = filepath.Base(r.Frames[i].File)
+		}
+	})
+}
+
+// WithoutAttrs leaves attributes out of the response.
+func WithoutAttrs() Option {
+	return WithSanitizer(func(r *errx.Record) {
+		r.Attrs = nil
+	})
+}
+
+// WithSanitizer registers fn to edit the record before it is written,
+// e.g. to redact attribute values or replace the message.
+func WithSanitizer(fn func(*errx.Record)) Option {
+	return func(o *options) {
+		o.sanitize = append(o.sanitize, fn)
+	}
+}
+
+// WriteError writes err's chain to w as a ContentType response body.
+// A nil err writes nothing.
+func WriteError(w http.ResponseWriter, err error, opts ...Option) error {
+	if err == nil {
+		return nil
+	}
+
+	r, o := newRecord(err, opts)
+	body, encErr := json.Marshal(r)
+	if encErr != nil {
+		return fmt.Errorf("could not encode error: %w", encErr)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(o.status)
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("could not write error: %w", err)
+	}
+	return nil
+}
+
+// newRecord applies opts and builds the sanitized record of err.
+func newRecord(err error, opts []Option) (errx.Record, options) {
+	o := options{status: http.StatusInternalServerError}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := errx.NewRecord(err)
+	for _, fn := range o.sanitize {
+		fn(&r)
+	}
+	return r, o
+}
+
+// ReadError decodes the error written by WriteError into resp's body.
+// The result carries the server's frames and ID; wrap it with errx.Wrap
+// to add the client's side of the chain. It returns nil if resp doesn't
+// carry an errx error, and a decoding error if its body is malformed or
+// missing. The body is consumed but not closed.
+func ReadError(resp *http.Response) error {
+	if resp == nil || !IsError(resp) {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		if body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize)); err != nil {
+			return fmt.Errorf("could not read error body: %w", err)
+		}
+	}
+
+	decoded, err := errx.Decode(body)
+	if err != nil {
+		return err
+	}
+	return decoded
+}
+
+// IsError reports whether resp carries an errx error body.
+func IsError(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == ContentType
+}
+
+// ResponseError describes a response with a non-2xx status, as returned
+// by FromResponse.
+type ResponseError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Method and URL identify the request, when the response has one.
+	Method string
+	URL    string
+	// Body is the start of the response body, for responses that don't
+	// carry an errx error.
+	Body string
+	// Err is the error the server wrote with WriteError, if any.
+	Err error
+}
+
+// Error renders e as "GET https://api/x: 503 Service Unavailable",
+// followed by the server's error or the body excerpt.
+func (e *ResponseError) Error() string {
+	var b strings.Builder
+	if e.Method != "" || e.URL != "" {
+		b.WriteString(strings.TrimSpace(e.Method+" "+e.URL) + ": ")
+	}
+	b.WriteString(strconv.Itoa(e.StatusCode))
+	if text := http.StatusText(e.StatusCode); text != "" {
+		b.WriteString(" " + text)
+	}
+
+	switch {
+	case e.Err != nil:
+		b.WriteString(": " + e.Err.Error())
+	case e.Body != "":
+		b.WriteString(": " + e.Body)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error the server wrote, so its frames, ID and
+// attributes are found along the chain.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// FromResponse returns a *ResponseError describing resp if its status is
+// not 2xx, and nil otherwise. When the server wrote an errx error with
+// WriteError, it is decoded and becomes the cause; otherwise, or when it
+// can't be decoded, the first 512 bytes of the body are kept. Like ReadError's result, wrap it with
+// errx.Wrap to add the client's side of the chain:
+//
+//	if err := errxhttp.FromResponse(resp); err != nil {
+//		return errx.Wrap(err)
+//	}
+//
+// Only the start of the body is read: up to 1 MiB for an errx error and
+// 513 bytes, enough to tell whether the excerpt was cut, otherwise. The
+// rest is left unread and the body open; the caller still owns it and
+// must close it, draining it first to reuse the connection.
+func FromResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	e := &ResponseError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.URL = resp.Request.URL.Redacted()
+		}
+	}
+
+	if resp.Body == nil {
+		return e
+	}
+
+	isError := IsError(resp)
+	limit := int64(maxExcerptSize + 1)
+	if isError {
+		limit = maxBodySize
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if isError {
+		if decoded, err := errx.Decode(body); err == nil {
+			e.Err = decoded
+			return e
+		}
+	}
+	e.Body = excerpt(body)
+	return e
+}
+
+// excerpt renders body on one line, cut to maxExcerptSize bytes.
+func excerpt(body []byte) string {
+	cut := len(body) > maxExcerptSize
+	if cut {
+		body = body[:maxExcerptSize]
+	}
+
+	s := strings.Join(strings.Fields(strings.ToValidUTF8(string(body), "")), " ")
+	if cut {
+		s += "..."
+	}
+	return s
+}
@@ -0,0 +1,287 @@
+package errxhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	serverErr := errx.With(errors.New("connection refused"), "host", "db-1")
+
+	testCases := []struct {
+		name  string
+		opts  []Option
+		check func(t *testing.T, err error)
+	}{
+		{
+			name: "full chain",
+			check: func(t *testing.T, err error) {
+				assert.Equal(t, serverErr.Error(), err.Error())
+				assert.Equal(t, errx.ID(serverErr), errx.ID(err))
+				assert.Equal(t, errx.Attrs(serverErr), errx.Attrs(err))
+			},
+		},
+		{
+			name: "without frames",
+			opts: []Option{WithoutFrames()},
+			check: func(t *testing.T, err error) {
+				assert.Equal(t, "connection refused", err.Error())
+				assert.Empty(t, errx.Frames(err))
+				assert.Equal(t, errx.ID(serverErr), errx.ID(err))
+			},
+		},
+		{
+			name: "without paths",
+			opts: []Option{WithoutPaths()},
+			check: func(t *testing.T, err error) {
+				frames := errx.Frames(err)
+				require.NotEmpty(t, frames)
+				assert.Equal(t, "errxhttp_test.go", frames[0].File)
+			},
+		},
+		{
+			name: "without attrs",
+			opts: []Option{WithoutAttrs()},
+			check: func(t *testing.T, err error) {
+				assert.Empty(t, errx.Attrs(err))
+			},
+		},
+		{
+			name: "custom sanitizer",
+			opts: []Option{WithSanitizer(func(r *errx.Record) {
+				r.Message = "redacted"
+			})},
+			check: func(t *testing.T, err error) {
+				assert.True(t, strings.HasSuffix(err.Error(), ": redacted"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, WriteError(w, serverErr, tc.opts...))
+			}))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			assert.True(t, IsError(resp))
+
+			clientErr := ReadError(resp)
+			require.NotNil(t, clientErr)
+			tc.check(t, clientErr)
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, WriteError(rec, nil))
+
+		assert.Empty(t, rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, WriteError(rec, errors.New("bad input"), WithStatus(http.StatusBadRequest)))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestReadError(t *testing.T) {
+	t.Parallel()
+
+	newResponse := func(contentType string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       http.NoBody,
+		}
+	}
+
+	t.Run("nil response", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, ReadError(nil))
+	})
+
+	t.Run("other content type", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, ReadError(newResponse("application/json")))
+	})
+
+	t.Run("content type parameters", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, WriteError(rec, errors.New("boom")))
+		resp := rec.Result()
+		resp.Header.Set("Content-Type", ContentType+"; charset=utf-8")
+
+		assert.EqualError(t, ReadError(resp), "boom")
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", ContentType)
+		fmt.Fprint(rec, "{")
+
+		err := ReadError(rec.Result())
+		assert.ErrorContains(t, err, "could not decode")
+	})
+
+	t.Run("nil body", func(t *testing.T) {
+		t.Parallel()
+
+		resp := newResponse(ContentType)
+		resp.Body = nil
+		assert.ErrorContains(t, ReadError(resp), "could not decode")
+	})
+
+	t.Run("client wraps the server chain", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, WriteError(rec, errx.Wrap(errors.New("boom"))))
+
+		err := errx.Wrap(ReadError(rec.Result()))
+
+		assert.Equal(t, 2, strings.Count(err.Error(), "(errxhttp_test.go:"))
+	})
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+
+	get := func(t *testing.T, handler http.HandlerFunc) *http.Response {
+		t.Helper()
+
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		resp, err := http.Get(srv.URL + "/invoices/42")
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, FromResponse(nil))
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		assert.NoError(t, FromResponse(resp))
+	})
+
+	t.Run("plain body", func(t *testing.T) {
+		t.Parallel()
+
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "<html>\n  upstream\n  timed out\n</html>")
+		})
+
+		err := FromResponse(resp)
+		var respErr *ResponseError
+		require.ErrorAs(t, err, &respErr)
+		assert.Equal(t, http.StatusBadGateway, respErr.StatusCode)
+		assert.Equal(t, http.MethodGet, respErr.Method)
+		assert.Equal(t, "<html> upstream timed out </html>", respErr.Body)
+		assert.Nil(t, errors.Unwrap(err))
+		assert.Equal(t, "GET "+respErr.URL+": 502 Bad Gateway: <html> upstream timed out </html>", err.Error())
+	})
+
+	t.Run("long body is cut", func(t *testing.T) {
+		t.Parallel()
+
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, strings.Repeat("é", 1000))
+		})
+
+		var respErr *ResponseError
+		require.ErrorAs(t, FromResponse(resp), &respErr)
+		assert.Equal(t, strings.Repeat("é", maxExcerptSize/2)+"...", respErr.Body)
+	})
+
+	t.Run("malformed errx error", func(t *testing.T) {
+		t.Parallel()
+
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", ContentType)
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "{truncated")
+		})
+
+		err := FromResponse(resp)
+		var respErr *ResponseError
+		require.ErrorAs(t, err, &respErr)
+		assert.Equal(t, "{truncated", respErr.Body)
+		assert.Nil(t, errors.Unwrap(err))
+	})
+
+	t.Run("nil body", func(t *testing.T) {
+		t.Parallel()
+
+		for _, contentType := range []string{ContentType, "text/plain"} {
+			resp := &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Header:     http.Header{"Content-Type": []string{contentType}},
+			}
+			var respErr *ResponseError
+			require.ErrorAs(t, FromResponse(resp), &respErr, contentType)
+			assert.Empty(t, respErr.Body)
+		}
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		serverErr := errx.With(errors.New("invoice not found"), "invoice", "42")
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			_ = WriteError(w, serverErr, WithStatus(http.StatusNotFound))
+		})
+
+		err := errx.Wrap(FromResponse(resp))
+
+		var respErr *ResponseError
+		require.ErrorAs(t, err, &respErr)
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+		assert.Empty(t, respErr.Body)
+		assert.Equal(t, errx.ID(serverErr), errx.ID(err))
+		assert.Equal(t, errx.Attrs(serverErr), errx.Attrs(err))
+		assert.Contains(t, err.Error(), ": 404 Not Found: ")
+		assert.True(t, strings.HasSuffix(err.Error(), ": invoice not found"))
+	})
+}
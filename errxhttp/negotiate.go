@@ -0,0 +1,306 @@
+package errxhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alesr/errx"
+)
+
+// Media types with a built-in Encoder, besides ContentType.
+const (
+	ProblemContentType = "application/problem+json"
+	JSONContentType    = "application/json"
+	TextContentType    = "text/plain"
+	XMLContentType     = "application/xml"
+)
+
+// Encoder writes the record of an error in one media type. status is the
+// status code the response is sent with.
+type Encoder func(w io.Writer, r errx.Record, status int) error
+
+type encoderEntry struct {
+	mediaType string
+	encode    Encoder
+}
+
+var (
+	encodersMu sync.RWMutex
+	// encoders is ordered by preference, for Accept headers that leave
+	// the choice to the server
+	encoders = []encoderEntry{
+		{ProblemContentType, encodeProblem},
+		{ContentType, encodeRecord},
+		{JSONContentType, encodeRecord},
+		{TextContentType, encodeText},
+		{XMLContentType, encodeXML},
+	}
+)
+
+// RegisterEncoder makes Respond able to render errors as mediaType,
+// replacing the encoder registered for it, if any. New media types are
+// the least preferred when the client accepts several equally.
+// It is meant to be called from init functions.
+func RegisterEncoder(mediaType string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i, entry := range encoders {
+		if entry.mediaType == mediaType {
+			encoders[i].encode = enc
+			return
+		}
+	}
+	encoders = append(encoders, encoderEntry{mediaType, enc})
+}
+
+// Respond writes err's chain to w in the media type req's Accept header
+// prefers among the registered ones: application/problem+json (RFC 9457),
+// ContentType, application/json, text/plain or application/xml out of
+// the box. Clients that don't say, or accept none of them, get problem
+// details. opts apply as in WriteError, whatever the media type.
+// A nil err writes nothing.
+func Respond(w http.ResponseWriter, req *http.Request, err error, opts ...Option) error {
+	if err == nil {
+		return nil
+	}
+
+	r, o := newRecord(err, opts)
+	mediaType, encode := negotiate(req.Header.Get("Accept"))
+
+	var body bytes.Buffer
+	if encErr := encode(&body, r, o.status); encErr != nil {
+		return fmt.Errorf("could not encode error: %w", encErr)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(o.status)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return fmt.Errorf("could not write error: %w", err)
+	}
+	return nil
+}
+
+// acceptRange is a media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate picks the registered encoder accept prefers. Each media type
+// gets the quality of the most specific range matching it, so
+// "application/json;q=0, */*" refuses JSON while accepting the rest.
+// Among equal qualities, the range listed first wins, then the encoder
+// registered first.
+func negotiate(accept string) (string, Encoder) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	ranges := parseAccept(accept)
+	best, bestQ, bestOrder := -1, 0.0, 0
+	for i, entry := range encoders {
+		q, order := quality(ranges, entry.mediaType)
+		if q > bestQ || q > 0 && q == bestQ && order < bestOrder {
+			best, bestQ, bestOrder = i, q, order
+		}
+	}
+	if best >= 0 {
+		return encoders[best].mediaType, encoders[best].encode
+	}
+
+	// fall back to problem details, even if replaced or not accepted
+	for _, entry := range encoders {
+		if entry.mediaType == ProblemContentType {
+			return entry.mediaType, entry.encode
+		}
+	}
+	return ProblemContentType, encodeProblem
+}
+
+// parseAccept returns the media ranges of accept in the order they are
+// listed, refused ones with a zero quality included. Malformed ranges are
+// skipped.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || !strings.Contains(mediaType, "/") {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	return ranges
+}
+
+// quality returns the quality the most specific of ranges matching
+// mediaType gives it, along with the position of that range. It returns
+// zero and -1 when no range matches.
+func quality(ranges []acceptRange, mediaType string) (q float64, order int) {
+	order, specificity := -1, -1
+	for i, r := range ranges {
+		if !matchRange(r.mediaType, mediaType) {
+			continue
+		}
+		if s := rangeSpecificity(r.mediaType); s > specificity {
+			q, order, specificity = r.q, i, s
+		}
+	}
+	return q, order
+}
+
+// rangeSpecificity ranks a media range: 2 for a full media type, 1 for
+// "type/*" and 0 for "*/*".
+func rangeSpecificity(r string) int {
+	switch {
+	case r == "*/*":
+		return 0
+	case strings.HasSuffix(r, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matchRange reports whether mediaType falls in the media range r,
+// e.g. "text/*" or "*/*".
+func matchRange(r, mediaType string) bool {
+	if r == "*/*" || r == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(r, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// problem is the body of an application/problem+json response, carrying
+// the error's ID, code, kind and hint as extension members, and its field
+// errors as invalid-params like the example of RFC 9457.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	ID            string         `json:"id,omitempty"`
+	Code          errx.Code      `json:"code,omitempty"`
+	Kind          errx.Kind      `json:"kind,omitempty"`
+	Hint          string         `json:"hint,omitempty"`
+	InvalidParams []invalidParam `json:"invalid-params,omitempty"`
+}
+
+type invalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// encodeProblem writes r as problem details. The runbook, when there is
+// one, is the problem type.
+func encodeProblem(w io.Writer, r errx.Record, status int) error {
+	p := problem{
+		Type:   r.Runbook,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: r.Error,
+		ID:     r.ID,
+		Code:   r.Code,
+		Kind:   r.Kind,
+		Hint:   r.Hint,
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	for _, field := range slices.Sorted(maps.Keys(r.FieldErrors)) {
+		p.InvalidParams = append(p.InvalidParams, invalidParam{Name: field, Reason: r.FieldErrors[field]})
+	}
+	return json.NewEncoder(w).Encode(p)
+}
+
+// encodeRecord writes r as JSON, as WriteError does.
+func encodeRecord(w io.Writer, r errx.Record, _ int) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// encodeText writes r's message followed by its field errors, hint and ID.
+func encodeText(w io.Writer, r errx.Record, _ int) error {
+	var b strings.Builder
+	b.WriteString(r.Error + "\n")
+	for _, field := range slices.Sorted(maps.Keys(r.FieldErrors)) {
+		b.WriteString(field + ": " + r.FieldErrors[field] + "\n")
+	}
+	if r.Hint != "" {
+		b.WriteString("hint: " + r.Hint + "\n")
+	}
+	if r.ID != "" {
+		b.WriteString("id: " + r.ID + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// xmlError is the body of an application/xml response.
+type xmlError struct {
+	XMLName xml.Name   `xml:"error"`
+	Message string     `xml:"message"`
+	ID      string     `xml:"id,omitempty"`
+	Code    errx.Code  `xml:"code,omitempty"`
+	Kind    errx.Kind  `xml:"kind,omitempty"`
+	Hint    string     `xml:"hint,omitempty"`
+	Frames  []xmlFrame `xml:"frames>frame,omitempty"`
+	Attrs   []xmlAttr  `xml:"attrs>attr,omitempty"`
+}
+
+type xmlFrame struct {
+	Function string `xml:"function,attr"`
+	File     string `xml:"file,attr"`
+	Line     int    `xml:"line,attr"`
+	Message  string `xml:",chardata"`
+}
+
+type xmlAttr struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// encodeXML writes r as XML, with attribute values rendered with fmt.
+func encodeXML(w io.Writer, r errx.Record, _ int) error {
+	e := xmlError{
+		Message: r.Error,
+		ID:      r.ID,
+		Code:    r.Code,
+		Kind:    r.Kind,
+		Hint:    r.Hint,
+	}
+	for _, frame := range r.Frames {
+		e.Frames = append(e.Frames, xmlFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			Message:  frame.Message,
+		})
+	}
+	for _, attr := range r.Attrs {
+		e.Attrs = append(e.Attrs, xmlAttr{Key: attr.Key, Value: fmt.Sprint(attr.Value)})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(e)
+}
@@ -0,0 +1,182 @@
+package errxhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespond(t *testing.T) {
+	t.Parallel()
+
+	err := errx.WithCode(errors.New("invoice not found"), "billing.invoice.not_found")
+	err = errx.WithKind(err, errx.KindNotFound)
+	err = errx.WithHint(err, "check the invoice number")
+
+	respond := func(t *testing.T, accept string, opts ...Option) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/invoices/42", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, Respond(rec, req, err, opts...))
+		return rec
+	}
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil))
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("problem details by default", func(t *testing.T) {
+		t.Parallel()
+
+		for _, accept := range []string{"", "*/*", "image/png"} {
+			rec := respond(t, accept, WithStatus(http.StatusNotFound))
+			assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"), accept)
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+
+			var p map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			assert.Equal(t, "about:blank", p["type"])
+			assert.Equal(t, "Not Found", p["title"])
+			assert.InDelta(t, 404, p["status"], 0)
+			assert.Equal(t, err.Error(), p["detail"])
+			assert.Equal(t, errx.ID(err), p["id"])
+			assert.Equal(t, "billing.invoice.not_found", p["code"])
+			assert.Equal(t, "not_found", p["kind"])
+			assert.Equal(t, "check the invoice number", p["hint"])
+		}
+	})
+
+	t.Run("runbook as problem type", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, Respond(rec, req, errx.WithRunbook(err, "https://runbooks.example.com/invoices")))
+
+		var p problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, "https://runbooks.example.com/invoices", p.Type)
+	})
+
+	t.Run("field errors as invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := errx.WithFieldError(nil, "email", "must be a valid address")
+		invalid = errx.WithFieldError(invalid, "age", "must be positive")
+
+		req := httptest.NewRequest(http.MethodPost, "/signup", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, Respond(rec, req, invalid, WithStatus(http.StatusUnprocessableEntity)))
+
+		var p problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, errx.KindInvalid, p.Kind)
+		assert.Equal(t, []invalidParam{
+			{Name: "age", Reason: "must be positive"},
+			{Name: "email", Reason: "must be a valid address"},
+		}, p.InvalidParams)
+
+		req.Header.Set("Accept", "text/plain")
+		rec = httptest.NewRecorder()
+		require.NoError(t, Respond(rec, req, invalid, WithoutFrames()))
+		assert.Contains(t, rec.Body.String(), "\nage: must be positive\nemail: must be a valid address\n")
+	})
+
+	t.Run("errx records", func(t *testing.T) {
+		t.Parallel()
+
+		for _, accept := range []string{ContentType, JSONContentType, "text/html;q=0.9, application/json"} {
+			rec := respond(t, accept)
+
+			var r errx.Record
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+			assert.Equal(t, err.Error(), r.Error, accept)
+			assert.Len(t, r.Frames, len(errx.Frames(err)), accept)
+		}
+
+		// errx clients keep reading errors across the call
+		rec := respond(t, ContentType)
+		decoded := ReadError(rec.Result())
+		assert.Equal(t, errx.ID(err), errx.ID(decoded))
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		t.Parallel()
+
+		rec := respond(t, "text/*", WithoutFrames())
+		assert.Equal(t, TextContentType, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "invoice not found\nhint: check the invoice number\nid: "+errx.ID(err)+"\n", rec.Body.String())
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		t.Parallel()
+
+		rec := respond(t, "application/json;q=0.5, application/xml")
+		assert.Equal(t, XMLContentType, rec.Header().Get("Content-Type"))
+
+		body := rec.Body.String()
+		assert.Contains(t, body, "<error><message>"+err.Error()+"</message><id>"+errx.ID(err)+"</id>")
+		assert.Contains(t, body, "<code>billing.invoice.not_found</code>")
+		assert.Contains(t, body, `<frames><frame function="github.com/alesr/errx/errxhttp.TestRespond"`)
+	})
+
+	t.Run("refused types are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		rec := respond(t, "application/problem+json;q=0, text/plain;q=0.1")
+		assert.Equal(t, TextContentType, rec.Header().Get("Content-Type"))
+
+		// the most specific range decides, wildcards don't bring them back
+		rec = respond(t, "application/problem+json;q=0, application/vnd.errx+json;q=0, application/json;q=0, */*")
+		assert.Equal(t, TextContentType, rec.Header().Get("Content-Type"))
+		rec = respond(t, "text/*;q=0, text/plain;q=0.2, */*;q=0.1")
+		assert.Equal(t, TextContentType, rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	t.Parallel()
+
+	const mediaType = "application/vnd.test-register-encoder"
+	RegisterEncoder(mediaType, func(w io.Writer, r errx.Record, status int) error {
+		_, err := fmt.Fprintf(w, "%d %s", status, r.Message)
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", mediaType)
+	rec := httptest.NewRecorder()
+	require.NoError(t, Respond(rec, req, errors.New("boom"), WithStatus(http.StatusTeapot)))
+
+	assert.Equal(t, mediaType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "418 boom", rec.Body.String())
+}
+
+func TestParseAccept(t *testing.T) {
+	t.Parallel()
+
+	ranges := parseAccept("text/plain;q=0.5, application/json, bogus, */*;q=0.1, image/png;q=0")
+	assert.Equal(t, []acceptRange{
+		{"text/plain", 0.5},
+		{"application/json", 1},
+		{"*/*", 0.1},
+		{"image/png", 0},
+	}, ranges)
+}
@@ -0,0 +1,120 @@
+package errxhttp
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/alesr/errx"
+)
+
+// WithErrorHandler sets the function RecoverMiddleware passes the errors
+// made of panics to, instead of errx.Report.
+func WithErrorHandler(fn func(req *http.Request, err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// RecoverMiddleware returns a handler calling next that turns its panics
+// into errx errors with errx.FromPanic, instead of letting net/http print
+// the stack to stderr. The error is passed to errx.Report, or to the
+// WithErrorHandler function, and answered with a problem details response
+// whose detail is errx.PublicMessage, with the status set by WithStatus,
+// 500 by default. When next started writing its response before
+// panicking, the error is only reported, as writing another response
+// would corrupt it. Other opts apply to the response as in Respond.
+// Panics with http.ErrAbortHandler abort the request as usual.
+func RecoverMiddleware(next http.Handler, opts ...Option) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w := &trackingWriter{ResponseWriter: rw}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := errx.FromPanic(v)
+			r, o := newRecord(err, append([]Option{WithSanitizer(func(r *errx.Record) {
+				r.Error = errx.PublicMessage(err)
+			})}, opts...))
+			if o.onError != nil {
+				o.onError(req, err)
+			} else {
+				errx.Report(err)
+			}
+			if w.wrote {
+				return
+			}
+
+			// encoding into memory can't fail
+			var body bytes.Buffer
+			_ = encodeProblem(&body, r, o.status)
+			w.Header().Set("Content-Type", ProblemContentType)
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(o.status)
+			_, _ = w.Write(body.Bytes())
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// trackingWriter records whether a response was started through it.
+type trackingWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (w *trackingWriter) WriteHeader(status int) {
+	// informational responses leave the final one to be written
+	if status >= 200 {
+		w.wrote = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// ReadFrom implements io.ReaderFrom, keeping the sendfile path of the
+// underlying ResponseWriter when it has one.
+func (w *trackingWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wrote = true
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	// hide ReadFrom from io.Copy, which would call it again
+	return io.Copy(struct{ io.Writer }{w.ResponseWriter}, r)
+}
+
+// Flush implements http.Flusher, for handlers streaming their response.
+// Flushing starts the response only when the underlying ResponseWriter
+// supports it.
+func (w *trackingWriter) Flush() {
+	if http.NewResponseController(w.ResponseWriter).Flush() == nil {
+		w.wrote = true
+	}
+}
+
+// Hijack implements http.Hijacker, for handlers taking over the
+// connection, such as websocket upgrades. Once hijacked, the connection
+// is the handler's to answer on.
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wrote = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
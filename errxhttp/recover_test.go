@@ -0,0 +1,156 @@
+package errxhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBroken = errors.New("broken invariant")
+
+func panickingHandler(w http.ResponseWriter, _ *http.Request) {
+	panic(errBroken)
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	serve := func(t *testing.T, h http.Handler) (*httptest.ResponseRecorder, error) {
+		t.Helper()
+
+		var handled error
+		h = RecoverMiddleware(h, WithErrorHandler(func(_ *http.Request, err error) {
+			handled = err
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/invoices/42", nil))
+		return rec, handled
+	}
+
+	t.Run("turns panics into errors", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, http.HandlerFunc(panickingHandler))
+		require.Error(t, err)
+
+		assert.ErrorIs(t, err, errBroken)
+		frames := errx.Frames(err)
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "github.com/alesr/errx/errxhttp.panickingHandler", frames[0].Function, "the panic site comes first")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+
+		var p problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, errx.PublicMessage(err), p.Detail)
+		assert.Equal(t, errx.ID(err), p.ID)
+		assert.NotContains(t, rec.Body.String(), "broken invariant")
+	})
+
+	t.Run("runtime panics start at the faulting call", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := serve(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			var m map[string]int
+			m["x"]++
+		}))
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "panic: assignment to entry in nil map")
+		assert.Equal(t, "github.com/alesr/errx/errxhttp.TestRecoverMiddleware.func3.1", errx.Frames(err)[0].Function)
+	})
+
+	t.Run("passes through without panics", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("leaves started responses alone", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("partial"))
+			panic(errBroken)
+		}))
+		assert.ErrorIs(t, err, errBroken, "the error is still handled")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "partial", rec.Body.String())
+	})
+
+	t.Run("streamed bodies count as started", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, ok := w.(io.ReaderFrom)
+			require.True(t, ok)
+			_, _ = io.Copy(w, strings.NewReader("partial"))
+			panic(errBroken)
+		}))
+		assert.ErrorIs(t, err, errBroken)
+		assert.Equal(t, "partial", rec.Body.String())
+	})
+
+	t.Run("unsupported flushes don't start the response", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		h := RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.(http.Flusher).Flush()
+			panic(errBroken)
+		}), WithErrorHandler(func(*http.Request, error) {}))
+		// hides the recorder's Flush
+		h.ServeHTTP(struct{ http.ResponseWriter }{rec}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("hijacked connections are left alone", func(t *testing.T) {
+		t.Parallel()
+
+		handled := make(chan error, 1)
+		srv := httptest.NewServer(RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			conn, rw, err := http.NewResponseController(w).Hijack()
+			require.NoError(t, err)
+			defer conn.Close()
+			_, _ = rw.WriteString("HTTP/1.1 418 I'm a teapot\r\nContent-Length: 0\r\n\r\n")
+			_ = rw.Flush()
+			panic(errBroken)
+		}), WithErrorHandler(func(_ *http.Request, err error) {
+			handled <- err
+		})))
+		t.Cleanup(srv.Close)
+
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		assert.ErrorIs(t, <-handled, errBroken)
+	})
+
+	t.Run("lets aborts through", func(t *testing.T) {
+		t.Parallel()
+
+		h := RecoverMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
@@ -0,0 +1,189 @@
+// Package errxi18n loads translation tables mapping error codes to
+// localized, user-facing messages, so product teams can edit copy
+// without recompiling. A Catalog is an errx.Translator:
+//
+//	catalog, err := errxi18n.Load(os.DirFS("locales"), "*.yaml")
+//	...
+//	errx.SetConfig(errx.Config{Translator: catalog})
+//	msg := errx.LocalizedMessage(err, "pt-BR")
+package errxi18n
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/alesr/errx"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog holds the messages of every loaded language.
+// It is safe for concurrent use.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[errx.Code]message
+}
+
+// message is a translation, with plural forms picked by the count
+// parameter. Messages without plural forms only have other set.
+type message struct {
+	zero, one, other string
+}
+
+// UnmarshalYAML accepts either a plain string or a mapping of the
+// plural forms zero, one and other.
+func (m *message) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&m.other)
+	}
+
+	var forms struct {
+		Zero  string `yaml:"zero"`
+		One   string `yaml:"one"`
+		Other string `yaml:"other"`
+	}
+	if err := node.Decode(&forms); err != nil {
+		return err
+	}
+	if forms.Other == "" {
+		return fmt.Errorf("line %d: plural forms without other", node.Line)
+	}
+	*m = message{zero: forms.Zero, one: forms.One, other: forms.Other}
+	return nil
+}
+
+// New returns an empty Catalog.
+func New() *Catalog {
+	return &Catalog{messages: make(map[string]map[errx.Code]message)}
+}
+
+// Load reads the translation files of fsys matching pattern, one per
+// language, named after its tag: "en.yaml", "pt-BR.json". See Add for
+// their format.
+func Load(fsys fs.FS, pattern string) (*Catalog, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("could not list translation files: %w", err)
+	}
+
+	c := New()
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read translation file: %w", err)
+		}
+
+		lang := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		if err := c.Add(lang, data); err != nil {
+			return nil, fmt.Errorf("could not load %s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+// Add loads the messages of the language tagged lang from data, a JSON
+// or YAML object mapping codes to messages. Messages are either strings
+// or objects with the plural forms "zero", "one" and "other", picked by
+// the count parameter:
+//
+//	billing.invoice.not_found: "Invoice {invoice} was not found"
+//	billing.invoices.overdue:
+//	  one: "{count} invoice is overdue"
+//	  other: "{count} invoices are overdue"
+//
+// Placeholders such as {invoice} are filled in with the error's
+// attributes. Messages already loaded for lang are replaced code by code.
+func (c *Catalog) Add(lang string, data []byte) error {
+	var messages map[errx.Code]message
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("could not parse translations: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[errx.Code]message, len(messages))
+	}
+	for code, msg := range messages {
+		c.messages[lang][code] = msg
+	}
+	return nil
+}
+
+// Translate implements errx.Translator. Languages fall back to their base
+// tag, "pt" for "pt-BR".
+func (c *Catalog) Translate(code errx.Code, lang string, params map[string]any) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for {
+		if msg, ok := c.messages[lang][code]; ok {
+			return fill(msg.pick(params["count"]), params), true
+		}
+
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			return "", false
+		}
+		lang = lang[:i]
+	}
+}
+
+// pick returns the plural form of m for count. Counts that aren't
+// numbers get the other form.
+func (m message) pick(count any) string {
+	var n float64
+	switch v := count.(type) {
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return m.other
+	}
+
+	switch {
+	case n == 0 && m.zero != "":
+		return m.zero
+	case n == 1 && m.one != "":
+		return m.one
+	default:
+		return m.other
+	}
+}
+
+// fill replaces the {name} placeholders of s with the matching params.
+// Unknown placeholders are kept.
+func fill(s string, params map[string]any) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		if v, ok := params[s[start+1:end]]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
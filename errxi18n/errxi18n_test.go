@@ -0,0 +1,121 @@
+package errxi18n
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var locales = fstest.MapFS{
+	"locales/en.yaml": {Data: []byte(`
+billing.invoice.not_found: "Invoice {invoice} was not found"
+billing.invoices.overdue:
+  zero: "No invoices are overdue"
+  one: "{count} invoice is overdue"
+  other: "{count} invoices are overdue"
+`)},
+	"locales/pt.json": {Data: []byte(`{
+  "billing.invoice.not_found": "Fatura {invoice} não encontrada",
+  "billing.invoices.overdue": {"one": "{count} fatura vencida", "other": "{count} faturas vencidas"}
+}`)},
+	"locales/README.md": {Data: []byte("not a translation file")},
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json and yaml", func(t *testing.T) {
+		t.Parallel()
+
+		// .yaml and .json, leaving README.md out
+		c, err := Load(locales, "locales/*.*[ln]")
+		require.NoError(t, err)
+
+		msg, ok := c.Translate("billing.invoice.not_found", "en", map[string]any{"invoice": "INV-42"})
+		require.True(t, ok)
+		assert.Equal(t, "Invoice INV-42 was not found", msg)
+
+		msg, ok = c.Translate("billing.invoice.not_found", "pt", map[string]any{"invoice": "INV-42"})
+		require.True(t, ok)
+		assert.Equal(t, "Fatura INV-42 não encontrada", msg)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		t.Parallel()
+
+		fsys := fstest.MapFS{"en.yaml": {Data: []byte("billing.x: [")}}
+		_, err := Load(fsys, "*.yaml")
+		assert.ErrorContains(t, err, "could not load en.yaml")
+
+		fsys = fstest.MapFS{"en.yaml": {Data: []byte("billing.x:\n  one: only one\n")}}
+		_, err = Load(fsys, "*.yaml")
+		assert.ErrorContains(t, err, "plural forms without other")
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load(locales, "[")
+		assert.Error(t, err)
+	})
+}
+
+func TestCatalogTranslate(t *testing.T) {
+	t.Parallel()
+
+	c, err := Load(locales, "locales/*.yaml")
+	require.NoError(t, err)
+	require.NoError(t, c.Add("pt", []byte(`billing.invoice.not_found: "Fatura {invoice} não encontrada"`)))
+
+	tests := []struct {
+		name   string
+		code   errx.Code
+		lang   string
+		params map[string]any
+		want   string
+		ok     bool
+	}{
+		{name: "zero", code: "billing.invoices.overdue", lang: "en", params: map[string]any{"count": 0}, want: "No invoices are overdue", ok: true},
+		{name: "one", code: "billing.invoices.overdue", lang: "en", params: map[string]any{"count": 1}, want: "1 invoice is overdue", ok: true},
+		{name: "other", code: "billing.invoices.overdue", lang: "en", params: map[string]any{"count": 3}, want: "3 invoices are overdue", ok: true},
+		{name: "decoded count", code: "billing.invoices.overdue", lang: "en", params: map[string]any{"count": 1.0}, want: "1 invoice is overdue", ok: true},
+		{name: "no count", code: "billing.invoices.overdue", lang: "en", want: "{count} invoices are overdue", ok: true},
+		{name: "base language", code: "billing.invoice.not_found", lang: "pt-BR", params: map[string]any{"invoice": 42}, want: "Fatura 42 não encontrada", ok: true},
+		{name: "unknown code", code: "billing.other", lang: "en"},
+		{name: "unknown language", code: "billing.invoice.not_found", lang: "de-DE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := c.Translate(tt.code, tt.lang, tt.params)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	t.Parallel()
+
+	c, err := Load(locales, "locales/*.yaml")
+	require.NoError(t, err)
+	w := errx.NewWrapper(errx.TranslateWith(c))
+
+	invoiceErr := w.Wrap(errx.With(errx.WithCode(errors.New("no rows"), "billing.invoice.not_found"), "invoice", "INV-42"))
+	assert.Equal(t, "Invoice INV-42 was not found (ref: "+errx.ID(invoiceErr)+")", errx.LocalizedMessage(invoiceErr, "en-US"))
+	assert.Equal(t, errx.PublicMessage(invoiceErr), errx.LocalizedMessage(invoiceErr, "de"))
+}
+
+func TestFill(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]any{"a": 1, "b": "two"}
+	assert.Equal(t, "1 and two", fill("{a} and {b}", params))
+	assert.Equal(t, "{c} and {", fill("{c} and {", params))
+	assert.Equal(t, "plain", fill("plain", params))
+}
@@ -0,0 +1,20 @@
+module github.com/alesr/errx/errxi18n
+
+go 1.24.0
+
+require github.com/alesr/errx v0.0.0-20261016170111-61bccbc30cb2
+
+require (
+	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
+
+// build against the errx of this repository; modules depending on this one
+// ignore the replacement and get the version required above
+replace github.com/alesr/errx => ../
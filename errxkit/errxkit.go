@@ -0,0 +1,51 @@
+// Package errxkit turns errx errors into go-kit log key/value pairs, for
+// structured pipelines where fmt verbs aren't an option.
+package errxkit
+
+import (
+	"strconv"
+
+	"github.com/alesr/errx"
+)
+
+// Logger matches the go-kit log.Logger interface.
+type Logger interface {
+	Log(keyvals ...any) error
+}
+
+// Keyvals flattens err into go-kit key/value pairs: "err" holds the root
+// message, "err_id" the support ID, "frame_0" to "frame_N" the frames in
+// rendering order, followed by the attributes under their own keys.
+// Returns nil if err is nil.
+func Keyvals(err error) []any {
+	if err == nil {
+		return nil
+	}
+
+	r := errx.NewRecord(err)
+	keyvals := make([]any, 0, 2*(2+len(r.Frames)+len(r.Attrs)))
+
+	keyvals = append(keyvals, "err", r.Message)
+	if r.ID != "" {
+		keyvals = append(keyvals, "err_id", r.ID)
+	}
+
+	for i, frame := range r.Frames {
+		value := frame.String()
+		if frame.Message != "" {
+			value += ": " + frame.Message
+		}
+		keyvals = append(keyvals, "frame_"+strconv.Itoa(i), value)
+	}
+
+	for _, attr := range r.Attrs {
+		keyvals = append(keyvals, attr.Key, attr.Value)
+	}
+	return keyvals
+}
+
+// Log writes keyvals followed by err's pairs to logger.
+// It logs keyvals alone if err is nil.
+func Log(logger Logger, err error, keyvals ...any) error {
+	return logger.Log(append(keyvals[:len(keyvals):len(keyvals)], Keyvals(err)...)...)
+}
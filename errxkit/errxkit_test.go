@@ -0,0 +1,74 @@
+package errxkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logFunc func(keyvals ...any) error
+
+func (f logFunc) Log(keyvals ...any) error {
+	return f(keyvals...)
+}
+
+func TestKeyvals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Keyvals(nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []any{"err", "boom"}, Keyvals(errors.New("boom")))
+	})
+
+	t.Run("errx error", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.With(errors.New("boom"), "user_id", 42)
+		err = errx.Wrapf(err, "loading user")
+
+		keyvals := Keyvals(err)
+		require.Zero(t, len(keyvals)%2)
+
+		pairs := make(map[any]any)
+		for i := 0; i < len(keyvals); i += 2 {
+			pairs[keyvals[i]] = keyvals[i+1]
+		}
+
+		assert.Equal(t, "boom", pairs["err"])
+		assert.Equal(t, errx.ID(err), pairs["err_id"])
+		assert.Equal(t, 42, pairs["user_id"])
+
+		frame0, ok := pairs["frame_0"].(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(frame0, "errxkit.TestKeyvals.func3 (errxkit_test.go:"))
+		assert.True(t, strings.HasSuffix(frame0, "): loading user"))
+		assert.Contains(t, pairs, "frame_1")
+	})
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	var logged []any
+	logger := logFunc(func(keyvals ...any) error {
+		logged = keyvals
+		return nil
+	})
+
+	require.NoError(t, Log(logger, errors.New("boom"), "level", "error"))
+	assert.Equal(t, []any{"level", "error", "err", "boom"}, logged)
+
+	require.NoError(t, Log(logger, nil, "level", "info"))
+	assert.Equal(t, []any{"level", "info"}, logged)
+}
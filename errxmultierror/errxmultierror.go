@@ -0,0 +1,51 @@
+// Package errxmultierror converts errors built with
+// github.com/hashicorp/go-multierror into joined errors and back, so the
+// errors they gather are walked, matched and rendered by errx like the
+// ones joined with errors.Join.
+package errxmultierror
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// From returns the errors gathered by err joined with errors.Join, so
+// errx lists each of them, when err is a *multierror.Error, and err as it
+// is otherwise. Convert multierrors before wrapping them.
+// Returns nil if err is nil or an empty multierror.
+func From(err error) error {
+	m, ok := err.(*multierror.Error)
+	if !ok {
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+	return errors.Join(m.Errors...)
+}
+
+// To returns the errors joined in err, with errors.Join or any error
+// implementing Unwrap() []error, gathered in a *multierror.Error, nested
+// joins flattened. Other errors are gathered alone.
+// Returns nil if err is nil.
+func To(err error) *multierror.Error {
+	if err == nil {
+		return nil
+	}
+	return &multierror.Error{Errors: flatten(nil, err)}
+}
+
+// flatten appends the errors joined in err to dst.
+func flatten(dst []error, err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return append(dst, err)
+	}
+	for _, e := range joined.Unwrap() {
+		if e != nil {
+			dst = flatten(dst, e)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,49 @@
+package errxmultierror
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("joins the gathered errors", func(t *testing.T) {
+		t.Parallel()
+
+		diskFull := errors.New("disk full")
+		m := multierror.Append(io.EOF, diskFull)
+
+		got := From(m)
+		assert.EqualError(t, got, "EOF\ndisk full")
+		assert.ErrorIs(t, got, io.EOF)
+		assert.ErrorIs(t, got, diskFull)
+	})
+
+	t.Run("returns other errors as they are", func(t *testing.T) {
+		t.Parallel()
+
+		plain := errors.New("boom")
+		assert.Same(t, plain, From(plain))
+		assert.NoError(t, From(nil))
+		assert.NoError(t, From((*multierror.Error)(nil)))
+		assert.NoError(t, From(&multierror.Error{}))
+	})
+}
+
+func TestTo(t *testing.T) {
+	t.Parallel()
+
+	first, second, third := errors.New("first"), errors.New("second"), errors.New("third")
+	m := To(errors.Join(first, errors.Join(second, nil, third)))
+	require.NotNil(t, m)
+	assert.Equal(t, []error{first, second, third}, m.Errors)
+
+	assert.Equal(t, []error{first}, To(first).Errors)
+	assert.Nil(t, To(nil))
+}
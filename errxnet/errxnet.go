@@ -0,0 +1,111 @@
+// Package errxnet describes network errors wrapped by errx: the failed
+// operation, address and flags of *net.OpError and *net.DNSError, and the
+// TLS failure, as attributes and a kind, so network failures can be
+// handled by one policy wherever they come from.
+package errxnet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+
+	"github.com/alesr/errx"
+)
+
+// Keys of the attributes attached by Enrich.
+const (
+	// AttrOp holds the failed operation, e.g. "dial" or "read".
+	AttrOp = "net.op"
+	// AttrNetwork holds the network, e.g. "tcp".
+	AttrNetwork = "net.network"
+	// AttrAddr holds the remote address.
+	AttrAddr = "net.addr"
+	// AttrHost holds the name looked up by a failed DNS query.
+	AttrHost = "net.dns_name"
+	// AttrServer holds the DNS server that answered, if known.
+	AttrServer = "net.dns_server"
+	// AttrTimeout and AttrTemporary hold the flags reported by the
+	// errors found, as bools.
+	AttrTimeout   = "net.timeout"
+	AttrTemporary = "net.temporary"
+	// AttrTLS describes a failed TLS handshake or certificate check.
+	AttrTLS = "net.tls"
+)
+
+// Register makes errx enrich network errors with Enrich when they are
+// first wrapped. Call it once at startup.
+func Register() {
+	errx.RegisterEnricher(Enrich)
+}
+
+// Enrich is an errx.Enricher for network errors. Failures that timed out
+// are classified as errx.KindTimeout, and the other network, DNS and TLS
+// failures as errx.KindUnavailable.
+func Enrich(cause error) ([]errx.Attr, errx.Kind) {
+	var (
+		attrs              []errx.Attr
+		found              bool
+		timeout, temporary bool
+	)
+	errx.Walk(cause, func(err error) bool {
+		switch err := err.(type) {
+		case *net.OpError:
+			found = true
+			timeout = timeout || err.Timeout()
+			temporary = temporary || err.Temporary()
+			attrs = append(attrs,
+				errx.Attr{Key: AttrOp, Value: err.Op},
+				errx.Attr{Key: AttrNetwork, Value: err.Net},
+			)
+			if err.Addr != nil {
+				attrs = append(attrs, errx.Attr{Key: AttrAddr, Value: err.Addr.String()})
+			}
+		case *net.DNSError:
+			found = true
+			timeout = timeout || err.IsTimeout
+			temporary = temporary || err.IsTemporary
+			attrs = append(attrs, errx.Attr{Key: AttrHost, Value: err.Name})
+			if err.Server != "" {
+				attrs = append(attrs, errx.Attr{Key: AttrServer, Value: err.Server})
+			}
+		default:
+			if desc := describeTLS(err); desc != "" {
+				found = true
+				attrs = append(attrs, errx.Attr{Key: AttrTLS, Value: desc})
+			}
+		}
+		return true
+	})
+	if !found {
+		return nil, ""
+	}
+
+	attrs = append(attrs,
+		errx.Attr{Key: AttrTimeout, Value: timeout},
+		errx.Attr{Key: AttrTemporary, Value: temporary},
+	)
+	if timeout {
+		return attrs, errx.KindTimeout
+	}
+	return attrs, errx.KindUnavailable
+}
+
+// describeTLS returns a short description of err if it is a TLS or
+// certificate error, and an empty string otherwise.
+func describeTLS(err error) string {
+	switch err := err.(type) {
+	case *tls.CertificateVerificationError:
+		return "certificate verification failed"
+	case tls.RecordHeaderError:
+		return "not a TLS handshake"
+	case tls.AlertError:
+		return "alert: " + err.Error()
+	case x509.UnknownAuthorityError:
+		return "certificate signed by unknown authority"
+	case x509.HostnameError:
+		return "certificate not valid for " + err.Host
+	case x509.CertificateInvalidError:
+		return "invalid certificate"
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package errxnet
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alesr/errx"
+)
+
+func TestEnrich(t *testing.T) {
+	t.Parallel()
+
+	t.Run("refused connections", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		require.NoError(t, ln.Close())
+
+		_, cause := net.Dial("tcp", addr)
+		require.Error(t, cause)
+
+		attrs, kind := Enrich(fmt.Errorf("calling billing: %w", cause))
+		assert.Equal(t, []errx.Attr{
+			{Key: AttrOp, Value: "dial"},
+			{Key: AttrNetwork, Value: "tcp"},
+			{Key: AttrAddr, Value: addr},
+			{Key: AttrTimeout, Value: false},
+			{Key: AttrTemporary, Value: false},
+		}, attrs)
+		assert.Equal(t, errx.KindUnavailable, kind)
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		t.Parallel()
+
+		cause := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+		attrs, kind := Enrich(cause)
+		assert.Contains(t, attrs, errx.Attr{Key: AttrTimeout, Value: true})
+		assert.Equal(t, errx.KindTimeout, kind)
+	})
+
+	t.Run("DNS errors", func(t *testing.T) {
+		t.Parallel()
+
+		cause := &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{
+			Err:         "no such host",
+			Name:        "billing.internal",
+			Server:      "10.0.0.2:53",
+			IsNotFound:  true,
+			IsTemporary: true,
+		}}
+
+		attrs, kind := Enrich(cause)
+		assert.Equal(t, []errx.Attr{
+			{Key: AttrOp, Value: "dial"},
+			{Key: AttrNetwork, Value: "tcp"},
+			{Key: AttrHost, Value: "billing.internal"},
+			{Key: AttrServer, Value: "10.0.0.2:53"},
+			{Key: AttrTimeout, Value: false},
+			{Key: AttrTemporary, Value: true},
+		}, attrs)
+		assert.Equal(t, errx.KindUnavailable, kind)
+	})
+
+	t.Run("TLS errors", func(t *testing.T) {
+		t.Parallel()
+
+		attrs, kind := Enrich(fmt.Errorf("handshake: %w", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}))
+		assert.Contains(t, attrs, errx.Attr{Key: AttrTLS, Value: "not a TLS handshake"})
+		assert.Equal(t, errx.KindUnavailable, kind)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		t.Parallel()
+
+		attrs, kind := Enrich(errors.New("boom"))
+		assert.Nil(t, attrs)
+		assert.Empty(t, kind)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	Register()
+
+	cause := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	err := errx.Wrap(cause)
+
+	op, _ := errx.Value[string](err, AttrOp)
+	assert.Equal(t, "read", op)
+	assert.Equal(t, errx.KindTimeout, errx.KindOf(err))
+}
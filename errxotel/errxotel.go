@@ -0,0 +1,129 @@
+// Package errxotel converts errx errors into OpenTelemetry log records,
+// for services exporting their logs over OTLP rather than having them
+// scraped from files.
+package errxotel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alesr/errx"
+	"go.opentelemetry.io/otel/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Attribute keys set on records besides the exception.* ones defined by
+// the semantic conventions.
+const (
+	KeyID   = "error.id"
+	KeyCode = "error.code"
+	KeyKind = "error.kind"
+)
+
+// New builds a log record for err:
+//
+//   - the body is the compact rendering of the chain
+//   - the severity follows errx.SeverityOf
+//   - the timestamp is errx.FirstSeen, when the chain has one
+//   - exception.type, exception.message and exception.stacktrace hold the
+//     root cause's Go type and message and the captured frames, in the
+//     format of Go stack traces
+//   - the support ID, code and kind are set when present, followed by the
+//     error's attributes
+//
+// Emit it with a log.Logger from the OTel SDK.
+// Returns the zero Record if err is nil.
+func New(err error) log.Record {
+	var r log.Record
+	if err == nil {
+		return r
+	}
+
+	severity := errx.SeverityOf(err)
+	r.SetSeverity(Severity(severity))
+	r.SetSeverityText(severity.String())
+	r.SetBody(log.StringValue(err.Error()))
+	if t := errx.FirstSeen(err); !t.IsZero() {
+		r.SetTimestamp(t)
+	}
+
+	root := errx.Root(err)
+	r.AddAttributes(
+		log.String(string(semconv.ExceptionTypeKey), errx.CauseType(err)),
+		log.String(string(semconv.ExceptionMessageKey), root.Error()),
+	)
+	if frames := errx.Frames(err); len(frames) > 0 {
+		r.AddAttributes(log.String(string(semconv.ExceptionStacktraceKey), stack(frames)))
+	}
+
+	if id := errx.ID(err); id != "" {
+		r.AddAttributes(log.String(KeyID, id))
+	}
+	if code := errx.CodeOf(err); code != "" {
+		r.AddAttributes(log.String(KeyCode, string(code)))
+	}
+	if kind := errx.KindOf(err); kind != "" {
+		r.AddAttributes(log.String(KeyKind, string(kind)))
+	}
+	for _, attr := range errx.Attrs(err) {
+		r.AddAttributes(log.KeyValue{Key: attr.Key, Value: value(attr.Value)})
+	}
+	return r
+}
+
+// Severity maps an errx severity to the OTel one of the same name.
+// Unset severities map to log.SeverityUndefined.
+func Severity(s errx.Severity) log.Severity {
+	switch s {
+	case errx.SeverityDebug:
+		return log.SeverityDebug
+	case errx.SeverityInfo:
+		return log.SeverityInfo
+	case errx.SeverityWarning:
+		return log.SeverityWarn
+	case errx.SeverityError:
+		return log.SeverityError
+	case errx.SeverityCritical:
+		return log.SeverityFatal
+	default:
+		return log.SeverityUndefined
+	}
+}
+
+// stack renders frames like the body of a runtime.Stack trace. The
+// goroutine header is left out: the frames don't tell which goroutine
+// captured them.
+func stack(frames []errx.Frame) string {
+	var b strings.Builder
+	for _, frame := range frames {
+		b.WriteString(frame.Function + "(...)\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + "\n")
+	}
+	return b.String()
+}
+
+// value converts an attribute value into a log value, keeping the types
+// OTel knows and rendering the others with fmt.
+func value(v any) log.Value {
+	switch v := v.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int64:
+		return log.Int64Value(v)
+	case float64:
+		return log.Float64Value(v)
+	case []byte:
+		return log.BytesValue(v)
+	case time.Time:
+		return log.StringValue(v.Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,102 @@
+package errxotel
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		r := New(nil)
+		assert.Equal(t, log.SeverityUndefined, r.Severity())
+		assert.Zero(t, r.AttributesLen())
+	})
+
+	t.Run("fills body, severity and exception", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.With(errors.New("connection refused"), "attempt", 3)
+		err = errx.WithCode(err, "users.db_unavailable")
+		err = errx.WithKind(err, errx.KindUnavailable)
+		err = errx.WithSeverity(err, errx.SeverityWarning)
+		err = errx.Wrapf(err, "loading user")
+
+		r := New(err)
+		assert.Equal(t, err.Error(), r.Body().AsString())
+		assert.Equal(t, log.SeverityWarn, r.Severity())
+		assert.Equal(t, "warning", r.SeverityText())
+		assert.Equal(t, errx.FirstSeen(err), r.Timestamp())
+
+		attrs := attributes(r)
+		assert.Equal(t, "*errors.errorString", attrs["exception.type"].AsString())
+		assert.Equal(t, "connection refused", attrs["exception.message"].AsString())
+		assert.Contains(t, attrs["exception.stacktrace"].AsString(), "github.com/alesr/errx/errxotel.TestNew.func2(...)\n\t")
+		assert.NotContains(t, attrs["exception.stacktrace"].AsString(), "goroutine")
+		assert.Equal(t, errx.ID(err), attrs[KeyID].AsString())
+		assert.Equal(t, "users.db_unavailable", attrs[KeyCode].AsString())
+		assert.Equal(t, "unavailable", attrs[KeyKind].AsString())
+		assert.Equal(t, int64(3), attrs["attempt"].AsInt64())
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		r := New(fmt.Errorf("context: %w", errors.New("boom")))
+		assert.Equal(t, log.SeverityError, r.Severity())
+		assert.True(t, r.Timestamp().IsZero())
+
+		attrs := attributes(r)
+		assert.Equal(t, "boom", attrs["exception.message"].AsString())
+		assert.NotContains(t, attrs, "exception.stacktrace")
+		assert.NotContains(t, attrs, KeyID)
+	})
+}
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   errx.Severity
+		want log.Severity
+	}{
+		{in: 0, want: log.SeverityUndefined},
+		{in: errx.SeverityDebug, want: log.SeverityDebug},
+		{in: errx.SeverityInfo, want: log.SeverityInfo},
+		{in: errx.SeverityWarning, want: log.SeverityWarn},
+		{in: errx.SeverityError, want: log.SeverityError},
+		{in: errx.SeverityCritical, want: log.SeverityFatal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Severity(tt.in), "severity %d", tt.in)
+	}
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, log.KindString, value(errx.KindInvalid).Kind())
+	assert.Equal(t, "invalid", value(errx.KindInvalid).AsString())
+	assert.True(t, value(true).AsBool())
+	assert.InDelta(t, 1.5, value(1.5).AsFloat64(), 0)
+	assert.Equal(t, "[1 2]", value([]int{1, 2}).AsString())
+}
+
+// attributes collects r's attributes by key.
+func attributes(r log.Record) map[string]log.Value {
+	attrs := make(map[string]log.Value)
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
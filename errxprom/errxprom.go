@@ -0,0 +1,134 @@
+// Package errxprom exposes Prometheus metrics about errx errors, so error
+// rate dashboards and alerts don't depend on parsing logs.
+package errxprom
+
+import (
+	"sync"
+
+	"github.com/alesr/errx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ComponentAttr is the attribute key read for the component label, as
+// typically set per Wrapper or per package with errx.DefaultAttrs.
+const ComponentAttr = "component"
+
+// MaxFingerprints is the number of distinct fingerprints a Collector
+// remembers. Past it, new fingerprints are no longer counted, so a flood
+// of unique errors can't grow the memory of the service without bound.
+const MaxFingerprints = 10000
+
+// Collector counts errors by kind, code and component, and tracks how
+// many distinct fingerprints it has seen. It implements both
+// prometheus.Collector and errx.Reporter.
+// It is safe for concurrent use.
+type Collector struct {
+	errors       *prometheus.CounterVec
+	fingerprints *prometheus.Desc
+	sentinels    *prometheus.Desc
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// New returns a Collector exporting:
+//
+//   - errx_errors_total, a counter labeled with kind, code and component
+//   - errx_fingerprints, a gauge of the distinct fingerprints observed,
+//     up to MaxFingerprints
+//   - errx_sentinel_chains_total, a counter of the chains holding each
+//     sentinel declared with errx.Counted, labeled with its message;
+//     sentinels sharing a message are summed into one series
+//
+// Register it with a prometheus.Registerer, then feed it with Attach or
+// Observe.
+func New() *Collector {
+	return &Collector{
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "errx_errors_total",
+			Help: "Errors observed, by kind, code and component.",
+		}, []string{"kind", "code", "component"}),
+		fingerprints: prometheus.NewDesc(
+			"errx_fingerprints",
+			"Distinct error fingerprints observed.",
+			nil, nil,
+		),
+		sentinels: prometheus.NewDesc(
+			"errx_sentinel_chains_total",
+			"Error chains holding each counted sentinel.",
+			[]string{"sentinel"}, nil,
+		),
+		seen: make(map[string]struct{}),
+	}
+}
+
+// Attach makes c count every error wrapped from now on, through
+// errx.OnWrap. Each error is counted once, at its first wrap, so only
+// kinds, codes and components known by then make it into the labels,
+// such as the ones set with errx.DefaultAttrs or errx.DefaultKind.
+// Errors without captured frames are not counted.
+// The returned function stops counting.
+func (c *Collector) Attach() (detach func()) {
+	return errx.OnWrap(func(err error) {
+		if errx.IsOrigin(err) {
+			c.Observe(err)
+		}
+	})
+}
+
+// Observe counts err. Use it, or Report, where errors leave the service,
+// once their kind and code are settled.
+// It does nothing if err is nil.
+func (c *Collector) Observe(err error) {
+	if err == nil {
+		return
+	}
+
+	component, _ := errx.Value[string](err, ComponentAttr)
+	c.errors.WithLabelValues(string(errx.KindOf(err)), string(errx.CodeOf(err)), component).Inc()
+
+	fingerprint := errx.Fingerprint(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.seen) < MaxFingerprints {
+		c.seen[fingerprint] = struct{}{}
+	}
+}
+
+// Report counts err, like Observe.
+func (c *Collector) Report(err error) {
+	c.Observe(err)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.errors.Describe(ch)
+	ch <- c.fingerprints
+	ch <- c.sentinels
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.errors.Collect(ch)
+
+	c.mu.Lock()
+	n := len(c.seen)
+	c.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(c.fingerprints, prometheus.GaugeValue, float64(n))
+
+	// counted by errx as they are wrapped, whether c is attached or not;
+	// a series per message, as duplicates fail the whole gather
+	var labels []string
+	counts := make(map[string]int64)
+	for _, sc := range errx.Counts() {
+		label := sc.Err.Error()
+		if _, ok := counts[label]; !ok {
+			labels = append(labels, label)
+		}
+		counts[label] += sc.Count
+	}
+	for _, label := range labels {
+		ch <- prometheus.MustNewConstMetric(c.sentinels, prometheus.CounterValue, float64(counts[label]), label)
+	}
+}
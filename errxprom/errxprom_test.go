@@ -0,0 +1,106 @@
+package errxprom
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts by kind, code and component", func(t *testing.T) {
+		t.Parallel()
+
+		c := New()
+		w := errx.NewWrapper(errx.DefaultAttrs(errx.Attr{Key: ComponentAttr, Value: "billing"}))
+
+		err := errx.WithCode(w.Wrap(errors.New("boom")), "billing.invoice.not_found")
+		err = errx.WithKind(err, errx.KindNotFound)
+		c.Observe(err)
+		c.Report(err)
+		c.Observe(errx.Wrap(errors.New("other")))
+		c.Observe(nil)
+
+		assert.InDelta(t, 2, testutil.ToFloat64(c.errors.WithLabelValues("not_found", "billing.invoice.not_found", "billing")), 0)
+		assert.InDelta(t, 1, testutil.ToFloat64(c.errors.WithLabelValues("", "", "")), 0)
+	})
+
+	t.Run("tracks distinct fingerprints", func(t *testing.T) {
+		t.Parallel()
+
+		c := New()
+		c.Observe(errx.WithGroupingKey(errors.New("a"), "a"))
+		c.Observe(errx.WithGroupingKey(errors.New("a"), "a"))
+		c.Observe(errx.WithGroupingKey(errors.New("b"), "b"))
+
+		reg := prometheus.NewPedanticRegistry()
+		require.NoError(t, reg.Register(c))
+
+		expected := `
+# HELP errx_fingerprints Distinct error fingerprints observed.
+# TYPE errx_fingerprints gauge
+errx_fingerprints 2
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "errx_fingerprints"))
+	})
+
+	t.Run("bounds the fingerprints remembered", func(t *testing.T) {
+		t.Parallel()
+
+		c := New()
+		for i := range MaxFingerprints + 10 {
+			c.Observe(errx.WithGroupingKey(errors.New("unique"), strconv.Itoa(i)))
+		}
+		assert.Len(t, c.seen, MaxFingerprints)
+	})
+
+	t.Run("exports counted sentinels", func(t *testing.T) {
+		t.Parallel()
+
+		errRateLimited := errx.Counted(errors.New("rate limited"))
+		errThrottled := errx.Counted(errors.New("rate limited"))
+		t.Cleanup(func() {
+			errx.Uncount(errRateLimited)
+			errx.Uncount(errThrottled)
+		})
+		_ = errx.Wrap(errRateLimited)
+		_ = errx.Wrap(errRateLimited)
+		_ = errx.Wrap(errThrottled)
+
+		reg := prometheus.NewPedanticRegistry()
+		require.NoError(t, reg.Register(New()))
+
+		expected := `
+# HELP errx_sentinel_chains_total Error chains holding each counted sentinel.
+# TYPE errx_sentinel_chains_total counter
+errx_sentinel_chains_total{sentinel="rate limited"} 3
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "errx_sentinel_chains_total"))
+	})
+}
+
+func TestAttach(t *testing.T) {
+	// not parallel: the hook sees every wrap in the process
+
+	c := New()
+	detach := c.Attach()
+
+	w := errx.NewWrapper(errx.DefaultKind(errx.KindUnavailable))
+	err := w.Wrap(errors.New("boom"))
+	err = errx.Wrap(err)
+	_ = errx.Wrapf(err, "retrying")
+
+	detach()
+	_ = errx.Wrap(errors.New("after detach"))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(c.errors.WithLabelValues("unavailable", "", "")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(c.errors))
+}
@@ -0,0 +1,130 @@
+// Package errxring keeps the last errors wrapped by a process in memory,
+// so they can be inspected while it misbehaves without grepping logs.
+package errxring
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/alesr/errx"
+)
+
+// Buffer is a fixed-size ring of recently wrapped errors.
+// Re-wrapping an error already in the buffer, recognized by its support
+// ID, updates its entry instead of taking a new slot, so each entry shows
+// the most complete chain seen so far.
+// It is safe for concurrent use.
+type Buffer struct {
+	mu      sync.Mutex
+	entries []error
+	// next is the slot the next new error is written to
+	next int
+	// slots maps support IDs to the slot holding their error
+	slots map[string]int
+}
+
+// New returns an empty buffer holding up to size errors.
+// It panics if size is not positive.
+func New(size int) *Buffer {
+	if size <= 0 {
+		panic("errxring: size must be positive")
+	}
+	return &Buffer{
+		entries: make([]error, 0, size),
+		slots:   make(map[string]int, size),
+	}
+}
+
+// Attach makes b record every error wrapped from now on, through
+// errx.OnWrap. The returned function stops recording.
+func (b *Buffer) Attach() (detach func()) {
+	return errx.OnWrap(b.Add)
+}
+
+// Add records err, evicting the oldest entry when b is full.
+// It does nothing if err is nil.
+func (b *Buffer) Add(err error) {
+	if err == nil {
+		return
+	}
+	id := errx.ID(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if slot, ok := b.slots[id]; ok && id != "" {
+		b.entries[slot] = err
+		return
+	}
+
+	slot := b.next
+	if len(b.entries) < cap(b.entries) {
+		b.entries = append(b.entries, err)
+	} else {
+		evicted := errx.ID(b.entries[slot])
+		if s, ok := b.slots[evicted]; ok && s == slot {
+			delete(b.slots, evicted)
+		}
+		b.entries[slot] = err
+	}
+	if id != "" {
+		b.slots[id] = slot
+	}
+	b.next = (slot + 1) % cap(b.entries)
+}
+
+// Errors returns the recorded errors, newest first.
+func (b *Buffer) Errors() []error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	errs := make([]error, 0, len(b.entries))
+	for i := 1; i <= len(b.entries); i++ {
+		slot := (b.next - i + cap(b.entries)) % cap(b.entries)
+		errs = append(errs, b.entries[slot])
+	}
+	return errs
+}
+
+// Records returns the recorded errors as records, newest first.
+func (b *Buffer) Records() []errx.Record {
+	errs := b.Errors()
+	records := make([]errx.Record, 0, len(errs))
+	for _, err := range errs {
+		records = append(records, errx.NewRecord(err))
+	}
+	return records
+}
+
+// Reset empties b.
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = b.entries[:0]
+	b.next = 0
+	clear(b.slots)
+}
+
+// ServeHTTP dumps the recorded errors, newest first, as a JSON array of
+// records. With the query parameter format=text, it writes their verbose
+// %+v rendering instead, separated by blank lines.
+func (b *Buffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for i, err := range b.Errors() {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%+v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(b.Records()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
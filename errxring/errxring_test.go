@@ -0,0 +1,147 @@
+package errxring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { New(0) })
+	assert.Empty(t, New(3).Errors())
+}
+
+func TestBufferAdd(t *testing.T) {
+	t.Parallel()
+
+	t.Run("newest first", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(3)
+		first := errx.Wrap(errors.New("first"))
+		second := errx.Wrap(errors.New("second"))
+		b.Add(first)
+		b.Add(nil)
+		b.Add(second)
+
+		assert.Equal(t, []error{second, first}, b.Errors())
+	})
+
+	t.Run("evicts the oldest", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(2)
+		var errs []error
+		for i := range 5 {
+			err := errx.Wrap(fmt.Errorf("error %d", i))
+			errs = append(errs, err)
+			b.Add(err)
+		}
+
+		assert.Equal(t, []error{errs[4], errs[3]}, b.Errors())
+
+		// evicted IDs no longer update entries
+		b.Add(errx.Wrap(errs[0]))
+		got := b.Errors()
+		require.Len(t, got, 2)
+		assert.Equal(t, errx.ID(errs[0]), errx.ID(got[0]))
+		assert.Equal(t, errs[4], got[1])
+	})
+
+	t.Run("re-wraps update their entry", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(3)
+		first := errx.Wrap(errors.New("first"))
+		other := errx.Wrap(errors.New("other"))
+		b.Add(first)
+		b.Add(other)
+
+		rewrapped := errx.Wrapf(first, "retrying")
+		b.Add(rewrapped)
+
+		assert.Equal(t, []error{other, rewrapped}, b.Errors())
+	})
+
+	t.Run("plain errors", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(3)
+		plain := errors.New("plain")
+		b.Add(plain)
+		b.Add(plain)
+
+		assert.Equal(t, []error{plain, plain}, b.Errors())
+	})
+}
+
+func TestBufferReset(t *testing.T) {
+	t.Parallel()
+
+	b := New(2)
+	err := errx.Wrap(errors.New("boom"))
+	b.Add(err)
+	b.Reset()
+	assert.Empty(t, b.Errors())
+
+	b.Add(err)
+	assert.Equal(t, []error{err}, b.Errors())
+}
+
+func TestBufferAttach(t *testing.T) {
+	b := New(10)
+	detach := b.Attach()
+
+	err := errx.Wrap(errors.New("boom"))
+	err = errx.Wrap(err)
+	detach()
+	_ = errx.Wrap(errors.New("not recorded"))
+
+	assert.Equal(t, []error{err}, b.Errors())
+}
+
+func TestBufferServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	b := New(2)
+	first := errx.Wrap(errors.New("first"))
+	second := errx.With(errors.New("second"), "user_id", 42)
+	b.Add(first)
+	b.Add(second)
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var records []errx.Record
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		require.Len(t, records, 2)
+		assert.Equal(t, "second", records[0].Message)
+		assert.Equal(t, errx.ID(second), records[0].ID)
+		assert.Equal(t, "first", records[1].Message)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors?format=text", nil))
+
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, fmt.Sprintf("%+v\n%+v", second, first), rec.Body.String())
+		assert.Contains(t, rec.Body.String(), "user_id: 42")
+	})
+}
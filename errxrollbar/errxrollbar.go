@@ -0,0 +1,116 @@
+// Package errxrollbar builds Rollbar trace payloads from errx errors.
+// The result plugs into the Rollbar Go SDK's transform hook, replacing
+// the stack of the reporting goroutine with the one captured by errx.
+package errxrollbar
+
+import (
+	"slices"
+
+	"github.com/alesr/errx"
+)
+
+// Frame is a Rollbar stack frame.
+type Frame struct {
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	Method   string `json:"method"`
+}
+
+// Exception describes the error a trace belongs to.
+type Exception struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// Trace is the "trace" body of a Rollbar item.
+type Trace struct {
+	Frames    []Frame   `json:"frames"`
+	Exception Exception `json:"exception"`
+}
+
+// NewTrace builds the trace for err. Frames are listed the way Rollbar
+// expects them, most recent call last, and the exception class is the Go
+// type of the root cause.
+// Returns the zero Trace if err is nil.
+func NewTrace(err error) Trace {
+	if err == nil {
+		return Trace{}
+	}
+
+	frames := errx.Frames(err)
+	trace := Trace{
+		Frames: make([]Frame, 0, len(frames)),
+		Exception: Exception{
+			Class:   errx.CauseType(err),
+			Message: err.Error(),
+		},
+	}
+	for _, frame := range slices.Backward(frames) {
+		trace.Frames = append(trace.Frames, Frame{
+			Filename: frame.File,
+			Lineno:   frame.Line,
+			Method:   frame.Function,
+		})
+	}
+	return trace
+}
+
+// Custom returns err's attributes as Rollbar custom data, along with its
+// support ID under "errx_id".
+// Returns nil if err is nil.
+func Custom(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	custom := make(map[string]any)
+	for _, attr := range errx.Attrs(err) {
+		custom[attr.Key] = attr.Value
+	}
+	if id := errx.ID(err); id != "" {
+		custom["errx_id"] = id
+	}
+	return custom
+}
+
+// Apply rewrites the item data built by the Rollbar SDK with err's trace
+// and merges its custom data, keeping keys already set. It is meant to be
+// called from the SDK's transform hook, with the error passed along in
+// the item's extras:
+//
+//	rollbar.SetTransform(func(data map[string]any) {
+//		custom, _ := data["custom"].(map[string]any)
+//		if err, ok := custom["errx"].(error); ok {
+//			delete(custom, "errx")
+//			errxrollbar.Apply(data, err)
+//		}
+//	})
+//
+//	rollbar.ErrorWithExtras(rollbar.ERR, err, map[string]any{"errx": err})
+//
+// Apply does nothing if err is nil.
+func Apply(data map[string]any, err error) {
+	if err == nil {
+		return
+	}
+
+	body, ok := data["body"].(map[string]any)
+	if !ok {
+		body = make(map[string]any)
+		data["body"] = body
+	}
+	delete(body, "trace_chain")
+	delete(body, "message")
+	body["trace"] = NewTrace(err)
+
+	custom, ok := data["custom"].(map[string]any)
+	if !ok {
+		custom = make(map[string]any)
+		data["custom"] = custom
+	}
+	for key, value := range Custom(err) {
+		if _, exists := custom[key]; !exists {
+			custom[key] = value
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package errxrollbar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Trace{}, NewTrace(nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		trace := NewTrace(errors.New("boom"))
+
+		assert.Empty(t, trace.Frames)
+		assert.Equal(t, Exception{Class: "*errors.errorString", Message: "boom"}, trace.Exception)
+	})
+
+	t.Run("most recent call last", func(t *testing.T) {
+		t.Parallel()
+
+		err := errx.Wrap(errors.New("boom"))
+		frames := errx.Frames(err)
+
+		trace := NewTrace(err)
+
+		require.Len(t, trace.Frames, len(frames))
+		last := trace.Frames[len(trace.Frames)-1]
+		assert.Equal(t, frames[0].Function, last.Method)
+		assert.Equal(t, frames[0].File, last.Filename)
+		assert.Equal(t, frames[0].Line, last.Lineno)
+		assert.Equal(t, "github.com/alesr/errx/errxrollbar.TestNewTrace.func3", last.Method)
+	})
+}
+
+func TestCustom(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, Custom(nil))
+	assert.Empty(t, Custom(errors.New("boom")))
+
+	err := errx.With(errors.New("boom"), "user_id", 42)
+	assert.Equal(t, map[string]any{
+		"user_id": 42,
+		"errx_id": errx.ID(err),
+	}, Custom(err))
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	err := errx.With(errors.New("boom"), "user_id", 42)
+
+	data := map[string]any{
+		"body": map[string]any{
+			"trace_chain": []any{"sdk stack"},
+		},
+		"custom": map[string]any{
+			"user_id": 7,
+		},
+	}
+	Apply(data, err)
+
+	body := data["body"].(map[string]any)
+	assert.NotContains(t, body, "trace_chain")
+	assert.Equal(t, NewTrace(err), body["trace"])
+
+	custom := data["custom"].(map[string]any)
+	assert.Equal(t, 7, custom["user_id"])
+	assert.Equal(t, errx.ID(err), custom["errx_id"])
+
+	t.Run("empty data", func(t *testing.T) {
+		t.Parallel()
+
+		data := map[string]any{}
+		Apply(data, err)
+
+		assert.Contains(t, data["body"], "trace")
+		assert.Contains(t, data["custom"], "errx_id")
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		data := map[string]any{}
+		Apply(data, nil)
+		assert.Empty(t, data)
+	})
+}
@@ -0,0 +1,339 @@
+// Package errxship ships errx errors to a collector endpoint, for teams
+// without an error tracker. Errors are serialized as errx records,
+// sampled and redacted on the way in, and POSTed as JSON arrays in
+// batches by a background goroutine, retrying failed deliveries.
+package errxship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alesr/errx"
+)
+
+// Option configures a Shipper.
+type Option func(*options)
+
+type options struct {
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	sampleRate    float64
+	retries       int
+	backoff       time.Duration
+	redact        []func(*errx.Record)
+}
+
+// WithClient sets the HTTP client used to reach the collector.
+// Defaults to a client timing out after 10 seconds.
+func WithClient(c *http.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// WithBatchSize sets the number of records sent per request.
+// Defaults to 100.
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithFlushInterval sets how long records wait for a batch to fill
+// before being sent anyway. Defaults to 5 seconds.
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.flushInterval = d
+	}
+}
+
+// WithQueueSize sets the number of records waiting to be sent past which
+// new ones are dropped, so a slow or unreachable collector never blocks
+// the code reporting errors. Defaults to 1000.
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// WithSampleRate sets the fraction of errors shipped, between 0 and 1.
+// Defaults to 1, shipping every error.
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithRetries sets how many times a failed delivery is retried, waiting
+// backoff before the first retry and twice as long before each next one.
+// Network errors, 429 and 5xx responses are retried; other responses
+// drop the batch. Defaults to 3 retries and a 500ms backoff.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// WithRedactor registers fn to edit records before they are queued,
+// e.g. to remove attributes holding personal data or to shorten paths.
+func WithRedactor(fn func(*errx.Record)) Option {
+	return func(o *options) {
+		o.redact = append(o.redact, fn)
+	}
+}
+
+// Shipper batches errors and sends them to a collector endpoint.
+// It implements errx.Reporter, so it can be set as Config.Reporter.
+// It is safe for concurrent use.
+type Shipper struct {
+	endpoint string
+	opts     options
+
+	queue   chan errx.Record
+	flushes chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	close   sync.Once
+
+	// ctx is canceled when Close gives up waiting for deliveries
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	dropped atomic.Int64
+}
+
+// New returns a Shipper posting to endpoint, configured by opts, and
+// starts its background goroutine. Call Close to stop it.
+func New(endpoint string, opts ...Option) *Shipper {
+	o := options{
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     100,
+		flushInterval: 5 * time.Second,
+		queueSize:     1000,
+		sampleRate:    1,
+		retries:       3,
+		backoff:       500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.batchSize = max(o.batchSize, 1)
+	if o.flushInterval <= 0 {
+		o.flushInterval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Shipper{
+		endpoint: endpoint,
+		opts:     o,
+		queue:    make(chan errx.Record, max(o.queueSize, 1)),
+		flushes:  make(chan chan struct{}),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go s.run()
+	return s
+}
+
+// Attach makes s ship every error wrapped from now on, through
+// errx.OnWrap. Each error is shipped once, at its first wrap, so the
+// record holds what was known by then; call Report where errors leave
+// the service to ship them with the codes and kinds set on the way up.
+// The returned function stops shipping.
+func (s *Shipper) Attach() (detach func()) {
+	return errx.OnWrap(func(err error) {
+		if errx.IsOrigin(err) {
+			s.Report(err)
+		}
+	})
+}
+
+// Report queues err to be shipped, unless it is sampled out or the queue
+// is full, in which case it is dropped and counted by Dropped. It never
+// blocks, and does nothing if err is nil or s is closed.
+func (s *Shipper) Report(err error) {
+	if err == nil || s.opts.sampleRate < 1 && rand.Float64() >= s.opts.sampleRate {
+		return
+	}
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	r := errx.NewRecord(err)
+	for _, fn := range s.opts.redact {
+		fn(&r)
+	}
+
+	select {
+	case s.queue <- r:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of records dropped so far, because the
+// queue was full or their delivery failed for good.
+func (s *Shipper) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Flush sends the queued records right away and waits until they are
+// delivered or dropped, or until ctx is done.
+func (s *Shipper) Flush(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case s.flushes <- reply:
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not flush errors: %w", ctx.Err())
+	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not flush errors: %w", ctx.Err())
+	}
+}
+
+// Close stops s after sending the queued records. When ctx is done
+// first, deliveries in flight are abandoned and the rest is dropped.
+// Errors reported after Close are ignored.
+func (s *Shipper) Close(ctx context.Context) error {
+	s.close.Do(func() {
+		close(s.done)
+	})
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.stopped
+		return fmt.Errorf("could not ship pending errors: %w", ctx.Err())
+	}
+}
+
+// run batches queued records and sends them until s is closed.
+func (s *Shipper) run() {
+	defer close(s.stopped)
+	defer s.cancel()
+
+	ticker := time.NewTicker(s.opts.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]errx.Record, 0, s.opts.batchSize)
+	sendBatch := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+	// drain sends everything queued so far
+	drain := func() {
+		for {
+			select {
+			case r := <-s.queue:
+				batch = append(batch, r)
+				if len(batch) == s.opts.batchSize {
+					sendBatch()
+				}
+			default:
+				sendBatch()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case r := <-s.queue:
+			batch = append(batch, r)
+			if len(batch) == s.opts.batchSize {
+				sendBatch()
+			}
+		case <-ticker.C:
+			sendBatch()
+		case reply := <-s.flushes:
+			drain()
+			close(reply)
+		case <-s.done:
+			drain()
+			return
+		}
+	}
+}
+
+// send delivers batch, retrying as configured, and counts it as dropped
+// when it can't.
+func (s *Shipper) send(batch []errx.Record) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		s.dropped.Add(int64(len(batch)))
+		return
+	}
+
+	wait := s.opts.backoff
+	for attempt := 0; ; attempt++ {
+		retry, ok := s.post(body)
+		if ok {
+			return
+		}
+		if !retry || attempt >= s.opts.retries {
+			s.dropped.Add(int64(len(batch)))
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			s.dropped.Add(int64(len(batch)))
+			return
+		}
+		wait *= 2
+	}
+}
+
+// post sends body once. It reports whether the collector accepted it,
+// and otherwise whether the failure is worth retrying.
+func (s *Shipper) post(body []byte) (retry, ok bool) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.opts.client.Do(req)
+	if err != nil {
+		return s.ctx.Err() == nil, false
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, true
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, false
+	default:
+		return false, false
+	}
+}
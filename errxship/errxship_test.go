@@ -0,0 +1,198 @@
+package errxship
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector records the batches posted to it.
+type collector struct {
+	mu      sync.Mutex
+	batches [][]errx.Record
+	// fail is the number of requests still to answer with 503
+	fail atomic.Int32
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.fail.Add(-1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var batch []errx.Record
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, batch)
+	c.mu.Unlock()
+}
+
+func (c *collector) sizes() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sizes []int
+	for _, batch := range c.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func newCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return c, srv.URL
+}
+
+func TestShipper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ships in batches", func(t *testing.T) {
+		t.Parallel()
+
+		c, url := newCollector(t)
+		s := New(url, WithBatchSize(2), WithFlushInterval(time.Hour))
+		for range 5 {
+			s.Report(errx.Wrap(errors.New("boom")))
+		}
+		s.Report(nil)
+		require.NoError(t, s.Close(context.Background()))
+
+		assert.Equal(t, []int{2, 2, 1}, c.sizes())
+		assert.Equal(t, "boom", c.batches[0][0].Message)
+		assert.NotEmpty(t, c.batches[0][0].ID)
+		assert.Zero(t, s.Dropped())
+
+		s.Report(errors.New("late"))
+		assert.Equal(t, []int{2, 2, 1}, c.sizes(), "errors reported after Close are ignored")
+	})
+
+	t.Run("flushes on demand and on interval", func(t *testing.T) {
+		t.Parallel()
+
+		c, url := newCollector(t)
+		s := New(url, WithFlushInterval(time.Hour))
+		t.Cleanup(func() { _ = s.Close(context.Background()) })
+
+		s.Report(errors.New("first"))
+		require.NoError(t, s.Flush(context.Background()))
+		assert.Equal(t, []int{1}, c.sizes())
+
+		ticking := New(url, WithFlushInterval(10*time.Millisecond))
+		t.Cleanup(func() { _ = ticking.Close(context.Background()) })
+		ticking.Report(errors.New("second"))
+		assert.Eventually(t, func() bool { return len(c.sizes()) == 2 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("redacts and samples", func(t *testing.T) {
+		t.Parallel()
+
+		c, url := newCollector(t)
+		s := New(url, WithRedactor(func(r *errx.Record) {
+			r.Attrs = nil
+		}))
+		s.Report(errx.With(errors.New("boom"), "email", "jane@example.com"))
+		require.NoError(t, s.Close(context.Background()))
+		require.Equal(t, []int{1}, c.sizes())
+		assert.Empty(t, c.batches[0][0].Attrs)
+
+		sampled := New(url, WithSampleRate(0))
+		sampled.Report(errors.New("boom"))
+		require.NoError(t, sampled.Close(context.Background()))
+		assert.Equal(t, []int{1}, c.sizes())
+	})
+
+	t.Run("retries failed deliveries", func(t *testing.T) {
+		t.Parallel()
+
+		c, url := newCollector(t)
+		c.fail.Store(2)
+		s := New(url, WithRetries(2, time.Millisecond))
+		s.Report(errors.New("boom"))
+		require.NoError(t, s.Close(context.Background()))
+
+		assert.Equal(t, []int{1}, c.sizes())
+		assert.Zero(t, s.Dropped())
+
+		c.fail.Store(2)
+		giveUp := New(url, WithRetries(1, time.Millisecond))
+		giveUp.Report(errors.New("boom"))
+		require.NoError(t, giveUp.Close(context.Background()))
+		assert.Equal(t, []int{1}, c.sizes())
+		assert.Equal(t, int64(1), giveUp.Dropped())
+	})
+
+	t.Run("drops past the queue size", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(srv.Close)
+
+		s := New(srv.URL, WithBatchSize(1), WithQueueSize(1))
+		// the first error is held by the blocked delivery, the second
+		// fills the queue
+		s.Report(errors.New("first"))
+		assert.Eventually(t, func() bool { return len(s.queue) == 0 }, time.Second, time.Millisecond)
+		s.Report(errors.New("second"))
+		s.Report(errors.New("third"))
+		assert.Equal(t, int64(1), s.Dropped())
+
+		close(release)
+		require.NoError(t, s.Close(context.Background()))
+	})
+
+	t.Run("close gives up when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the server only notices the client leaving once the body is read
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+		t.Cleanup(srv.Close)
+
+		s := New(srv.URL)
+		s.Report(errors.New("boom"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, s.Close(ctx), context.DeadlineExceeded)
+		assert.Equal(t, int64(1), s.Dropped())
+	})
+}
+
+func TestAttach(t *testing.T) {
+	// OnWrap hooks are global, so this test can't run in parallel
+
+	c, url := newCollector(t)
+	s := New(url)
+	detach := s.Attach()
+	err := errx.Wrap(errors.New("boom"))
+	_ = errx.Wrapf(err, "retrying") // not shipped again
+	detach()
+	_ = errx.Wrap(errors.New("ignored"))
+	require.NoError(t, s.Close(context.Background()))
+
+	require.Equal(t, []int{1}, c.sizes())
+	assert.Equal(t, "boom", c.batches[0][0].Message)
+}
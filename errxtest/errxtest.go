@@ -0,0 +1,229 @@
+// Package errxtest helps asserting on the shape of errx errors in tests.
+package errxtest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+)
+
+var update = flag.Bool("errxtest.update", false, "rewrite the golden files compared by errxtest.Snapshot")
+
+// lineNumber matches the line number at the end of a rendered frame.
+var lineNumber = regexp.MustCompile(`:\d+\)$`)
+
+// Render returns the rendering of err compared by Snapshot: the root
+// message, the frames recorded by wrap calls with their messages and
+// arguments, the attributes, then the code, kind, severity, runbook,
+// hint, field errors, scopes and public message when set, and the
+// support ID. Line numbers print as NN and the ID as 0000-0000, and
+// capture times and the frames found by scanning the stack are left out,
+// so the rendering only changes when the error does.
+// Returns an empty string if err is nil.
+func Render(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	r := errx.NewRecord(err)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "message: %s\n", r.Message)
+
+	var frames []errx.Frame
+	for _, frame := range r.Frames {
+		if frame.WrapSite {
+			frames = append(frames, frame)
+		}
+	}
+	if len(frames) > 0 {
+		b.WriteString("frames:\n")
+		for _, frame := range frames {
+			line := lineNumber.ReplaceAllString(frame.String(), ":NN)")
+			if frame.Tags != "" {
+				line += " #" + strings.ReplaceAll(frame.Tags, ",", " #")
+			}
+			if frame.Message != "" {
+				line += ": " + frame.Message
+			}
+			fmt.Fprintf(&b, "  %s\n", line)
+			if frame.Args != "" {
+				fmt.Fprintf(&b, "    args: %s\n", frame.Args)
+			}
+		}
+	}
+
+	if len(r.Attrs) > 0 {
+		b.WriteString("attrs:\n")
+		for _, attr := range r.Attrs {
+			fmt.Fprintf(&b, "  %s: %v\n", attr.Key, attr.Value)
+		}
+	}
+
+	var severity string
+	if r.Severity != 0 {
+		severity = r.Severity.String()
+	}
+	var fieldErrors []string
+	for _, field := range slices.Sorted(maps.Keys(r.FieldErrors)) {
+		fieldErrors = append(fieldErrors, field+": "+r.FieldErrors[field])
+	}
+	fields := []struct{ name, value string }{
+		{"code", string(r.Code)},
+		{"kind", string(r.Kind)},
+		{"severity", severity},
+		{"runbook", r.Runbook},
+		{"hint", r.Hint},
+		{"field errors", strings.Join(fieldErrors, ", ")},
+		{"scopes", strings.Join(r.Scopes, " > ")},
+		{"public message", r.PublicMessage},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field.name, field.value)
+		}
+	}
+
+	if r.ID != "" {
+		b.WriteString("id: 0000-0000\n")
+	}
+	return b.String()
+}
+
+// Snapshot compares Render(err) with the golden file of the test,
+// testdata/<test name>.golden, and fails the test when they differ.
+// Run the tests with -errxtest.update to write the golden files from the
+// current errors instead.
+func Snapshot(t testing.TB, err error) {
+	t.Helper()
+
+	got := Render(err)
+	path := filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("errxtest: could not create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("errxtest: could not write golden file: %v", err)
+		}
+		return
+	}
+
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("errxtest: could not read golden file, run with -errxtest.update to create it: %v", readErr)
+	}
+	if got != string(want) {
+		t.Errorf("errxtest: error doesn't match %s, run with -errxtest.update to accept it\n--- want\n%s--- got\n%s", path, want, got)
+	}
+}
+
+// Tree renders err's chain as a tree, one line per frame, wrapper message
+// and cause, with errors joined by errors.Join as branches:
+//
+//	api.Checkout (checkout.go:31): placing order
+//	testing.tRunner (testing.go:1792)
+//	checkout
+//	├─ db.Query (query.go:42)
+//	│  connection refused
+//	└─ cache.Get (cache.go:17)
+//	   timeout
+//
+// Returns an empty string if err is nil.
+func Tree(err error) string {
+	var b strings.Builder
+	writeTree(&b, err, "", "", make(map[error]bool))
+	return b.String()
+}
+
+// Log logs the tree of err, as rendered by Tree, so the full chain shows
+// in the test output instead of the single line of Error. It does nothing
+// if err is nil.
+func Log(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Logf("\n%s", Tree(err))
+	}
+}
+
+// Fatal fails and stops the test with the tree of err, as rendered by
+// Tree, when err is not nil, like require.NoError with the full chain.
+func Fatal(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error:\n%s", Tree(err))
+	}
+}
+
+// writeTree writes the lines of err's chain to b, the first one prefixed
+// by first and the others by rest. seen holds the errors written so far,
+// so chains unwrapping to an ancestor end instead of looping.
+func writeTree(b *strings.Builder, err error, first, rest string, seen map[error]bool) {
+	prefix := first
+	line := func(s string) {
+		b.WriteString(prefix)
+		b.WriteString(s)
+		b.WriteByte('\n')
+		prefix = rest
+	}
+
+	for err != nil {
+		if reflect.TypeOf(err).Comparable() {
+			if seen[err] {
+				line("... cycle")
+				return
+			}
+			seen[err] = true
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			var branches []error
+			for _, e := range joined.Unwrap() {
+				if e != nil {
+					branches = append(branches, e)
+				}
+			}
+			for i, branch := range branches {
+				if i < len(branches)-1 {
+					writeTree(b, branch, prefix+"├─ ", rest+"│  ", seen)
+				} else {
+					writeTree(b, branch, prefix+"└─ ", rest+"   ", seen)
+				}
+				prefix = rest
+			}
+			return
+		}
+
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			line(err.Error())
+			return
+		}
+
+		// the frames err adds, listed before the ones of its cause
+		frames := errx.Frames(err)
+		if n := len(frames) - len(errx.Frames(inner)); n > 0 {
+			for _, frame := range frames[:n] {
+				s := frame.String()
+				if frame.Message != "" {
+					s += ": " + frame.Message
+				}
+				line(s)
+			}
+		} else if msg, ok := strings.CutSuffix(err.Error(), inner.Error()); ok && msg != "" {
+			line(strings.TrimSuffix(msg, ": "))
+		}
+		err = inner
+	}
+}
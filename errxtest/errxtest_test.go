@@ -0,0 +1,164 @@
+package errxtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alesr/errx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures the first failure reported by Snapshot and the
+// lines logged. Unlike testing.T, its Fatalf doesn't stop the test.
+type recorder struct {
+	testing.TB
+	name   string
+	failed string
+	logged []string
+}
+
+func (r *recorder) Logf(format string, args ...any) {
+	r.logged = append(r.logged, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Name() string {
+	return r.name
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	if r.failed == "" {
+		r.failed = fmt.Sprintf(format, args...)
+	}
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func invoiceErr() error {
+	err := errx.Wrapf(errors.New("no rows"), "loading invoice")
+	err = errx.WithArgs(err, "INV-42")
+	err = errx.With(err, "tenant", "acme")
+	err = errx.WithCode(err, "billing.invoice.not_found")
+	err = errx.WithKind(err, errx.KindNotFound)
+	return errx.Wrap(err)
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renders the chain without line numbers, times or IDs", func(t *testing.T) {
+		t.Parallel()
+
+		got := Render(invoiceErr())
+		assert.Equal(t, `message: no rows
+frames:
+  errxtest.invoiceErr (errxtest_test.go:NN)
+  errxtest.invoiceErr (errxtest_test.go:NN)
+    args: "INV-42"
+  errxtest.invoiceErr (errxtest_test.go:NN): loading invoice
+attrs:
+  tenant: acme
+code: billing.invoice.not_found
+kind: not_found
+id: 0000-0000
+`, got)
+	})
+
+	t.Run("is stable across captures", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Render(invoiceErr()), Render(invoiceErr()))
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Render(nil))
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("matches the golden file", func(t *testing.T) {
+		Snapshot(t, invoiceErr())
+	})
+
+	t.Run("fails when the error changed", func(t *testing.T) {
+		rec := &recorder{TB: t, name: "TestSnapshot/matches_the_golden_file"}
+		Snapshot(rec, errx.Wrap(errors.New("no invoices")))
+
+		assert.Contains(t, rec.failed, "message: no invoices")
+		assert.Contains(t, rec.failed, "-errxtest.update")
+	})
+
+	t.Run("fails when the golden file is missing", func(t *testing.T) {
+		rec := &recorder{TB: t, name: "TestSnapshot/missing"}
+		Snapshot(rec, invoiceErr())
+
+		assert.Contains(t, rec.failed, "could not read golden file")
+	})
+
+	t.Run("writes the golden file with -errxtest.update", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		*update = true
+		t.Cleanup(func() { *update = false })
+
+		Snapshot(t, invoiceErr())
+
+		got, err := os.ReadFile(filepath.Join("testdata", "TestSnapshot", "writes_the_golden_file_with_-errxtest.update.golden"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(got), "message: no rows\n"))
+	})
+}
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	w := errx.NewWrapper(errx.Config{TrimBelow: []string{"testing.tRunner"}})
+
+	t.Run("renders frames, messages and branches", func(t *testing.T) {
+		t.Parallel()
+
+		db := w.Wrap(errors.New("connection refused"))
+		cache := errors.New("timeout")
+		err := w.Wrapf(fmt.Errorf("checkout: %w", errors.Join(db, cache)), "placing order")
+
+		got := regexp.MustCompile(`:\d+\)`).ReplaceAllString(Tree(err), ":NN)")
+		assert.Equal(t, `errxtest.TestTree.func1 (errxtest_test.go:NN): placing order
+checkout
+├─ errxtest.TestTree.func1 (errxtest_test.go:NN)
+│  connection refused
+└─ timeout
+`, got)
+	})
+
+	t.Run("plain errors", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "boom\n", Tree(errors.New("boom")))
+		assert.Empty(t, Tree(nil))
+	})
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{name: t.Name()}
+	Log(r, errors.New("boom"))
+	Log(r, nil)
+	assert.Equal(t, []string{"\nboom\n"}, r.logged)
+
+	Fatal(r, nil)
+	assert.Empty(t, r.failed)
+	Fatal(r, errx.Wrap(errors.New("boom")))
+	assert.Contains(t, r.failed, "unexpected error:\nerrxtest.TestLog (errxtest_test.go:")
+}
@@ -0,0 +1,63 @@
+// Package errxzap picks zap log levels from the severity of errx errors,
+// so logging layers stop logging every error at the error level.
+package errxzap
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/alesr/errx"
+)
+
+// Level returns the zap level matching s. SeverityCritical maps to
+// zapcore.ErrorLevel like SeverityError, as zap's more severe levels
+// panic or exit, DPanicLevel included in development loggers. The zero
+// Severity maps to zapcore.ErrorLevel too, like errx.SeverityOf.
+func Level(s errx.Severity) zapcore.Level {
+	switch s {
+	case errx.SeverityDebug:
+		return zapcore.DebugLevel
+	case errx.SeverityInfo:
+		return zapcore.InfoLevel
+	case errx.SeverityWarning:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// LogWithLevel logs err with logger at the level matching its severity.
+// The message is err's rendering, followed by its support ID, code and
+// kind when set, and its attributes, like errx.LogWithLevel does with slog.
+// Loggers adding the caller report the call to LogWithLevel. A nil
+// logger logs with zap.L.
+// It does nothing if err is nil.
+func LogWithLevel(logger *zap.Logger, err error) {
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.WithOptions(zap.AddCallerSkip(1))
+
+	entry := logger.Check(Level(errx.SeverityOf(err)), err.Error())
+	if entry == nil {
+		return
+	}
+
+	var fields []zap.Field
+	if id := errx.ID(err); id != "" {
+		fields = append(fields, zap.String("id", id))
+	}
+	if code := errx.CodeOf(err); code != "" {
+		fields = append(fields, zap.String("code", string(code)))
+	}
+	if kind := errx.KindOf(err); kind != "" {
+		fields = append(fields, zap.String("kind", string(kind)))
+	}
+	for _, attr := range errx.Attrs(err) {
+		fields = append(fields, zap.Any(attr.Key, attr.Value))
+	}
+	entry.Write(fields...)
+}
@@ -0,0 +1,84 @@
+package errxzap
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/alesr/errx"
+)
+
+func TestLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[errx.Severity]zapcore.Level{
+		0:                     zapcore.ErrorLevel,
+		errx.SeverityDebug:    zapcore.DebugLevel,
+		errx.SeverityInfo:     zapcore.InfoLevel,
+		errx.SeverityWarning:  zapcore.WarnLevel,
+		errx.SeverityError:    zapcore.ErrorLevel,
+		errx.SeverityCritical: zapcore.ErrorLevel,
+	}
+	for severity, want := range tests {
+		assert.Equal(t, want, Level(severity), severity.String())
+	}
+}
+
+func TestLogWithLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("logs at the error's level", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		err := errx.WithSeverity(errx.With(errors.New("cache miss"), "key", "user:42"), errx.SeverityWarning)
+		err = errx.WithKind(err, errx.KindNotFound)
+		LogWithLevel(zap.New(core), err)
+
+		require.Equal(t, 1, logs.Len())
+		entry := logs.All()[0]
+		assert.Equal(t, zapcore.WarnLevel, entry.Level)
+		assert.Equal(t, err.Error(), entry.Message)
+		assert.Equal(t, map[string]any{
+			"id":   errx.ID(err),
+			"kind": "not_found",
+			"key":  "user:42",
+		}, entry.ContextMap())
+	})
+
+	t.Run("skips disabled levels", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		LogWithLevel(zap.New(core), errx.WithSeverity(errors.New("retrying"), errx.SeverityDebug))
+		LogWithLevel(zap.New(core), nil)
+
+		assert.Zero(t, logs.Len())
+	})
+	t.Run("reports the caller", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.InfoLevel)
+		LogWithLevel(zap.New(core, zap.AddCaller()), errors.New("boom"))
+
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "errxzap_test.go", filepath.Base(logs.All()[0].Caller.File))
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		t.Parallel()
+
+		// nothing else in this package logs through the global logger
+		core, logs := observer.New(zapcore.InfoLevel)
+		defer zap.ReplaceGlobals(zap.New(core))()
+		LogWithLevel(nil, errors.New("boom"))
+
+		assert.Equal(t, 1, logs.Len())
+	})
+}
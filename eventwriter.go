@@ -0,0 +1,107 @@
+package errx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// eventEntry is a line written by EventWriter.
+type eventEntry struct {
+	// Time is when the error was wrapped or reported.
+	Time time.Time `json:"time"`
+	// Event is "wrap" or "report".
+	Event string `json:"event"`
+	Record
+}
+
+// EventWriter appends one JSON object per error to an io.Writer, as
+// newline-delimited JSON: a lightweight audit trail of errors kept apart
+// from the logging stack. Each object holds the fields of the error's
+// Record, along with "time" and "event", set to "wrap" for errors seen
+// through Attach and to "report" for the ones passed to Report.
+//
+// Every line is written with a single Write call and flushed right away
+// if the writer buffers, so a file rotated between two errors never holds
+// half a line; Rotate switches to a new writer between two lines.
+// An EventWriter implements Reporter and is safe for concurrent use.
+type EventWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf bytes.Buffer
+	err error
+}
+
+// NewEventWriter returns an EventWriter appending to w.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{w: w}
+}
+
+// Attach makes ew write every error wrapped from now on, re-wraps
+// included, through OnWrap. The returned function stops writing.
+func (ew *EventWriter) Attach() (detach func()) {
+	return OnWrap(func(err error) {
+		ew.write("wrap", wrapTime(err), err)
+	})
+}
+
+// Report writes err as a "report" event.
+// It does nothing if err is nil.
+func (ew *EventWriter) Report(err error) {
+	if err == nil {
+		return
+	}
+	ew.write("report", time.Now(), err)
+}
+
+// Rotate makes ew append to w from now on and returns the writer used so
+// far, holding every line written before, for the caller to close.
+// Lines are never split between the two.
+func (ew *EventWriter) Rotate(w io.Writer) (old io.Writer) {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	old = ew.w
+	ew.w = w
+	return old
+}
+
+// Err returns the first error met writing events, if any. Events failing
+// to be written are dropped, so logging it once in a while is enough to
+// notice a full disk.
+func (ew *EventWriter) Err() error {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	return ew.err
+}
+
+// write appends the line of the event to ew's writer.
+func (ew *EventWriter) write(event string, t time.Time, err error) {
+	entry := eventEntry{Time: t, Event: event, Record: NewRecord(err)}
+
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	ew.buf.Reset()
+	// the encoder ends the object with the newline
+	if encErr := json.NewEncoder(&ew.buf).Encode(entry); encErr != nil {
+		ew.fail(encErr)
+		return
+	}
+	if _, wErr := ew.w.Write(ew.buf.Bytes()); wErr != nil {
+		ew.fail(wErr)
+		return
+	}
+	if f, ok := ew.w.(interface{ Flush() error }); ok {
+		ew.fail(f.Flush())
+	}
+}
+
+// fail records err unless an earlier one was.
+func (ew *EventWriter) fail(err error) {
+	if ew.err == nil {
+		ew.err = err
+	}
+}
@@ -0,0 +1,87 @@
+package errx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestEventWriter(t *testing.T) {
+	// Attach registers a global hook, so this test can't run in parallel
+
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWrapper(Config{Clock: func() time.Time { return clock }})
+
+	decode := func(t *testing.T, data string) []map[string]any {
+		t.Helper()
+
+		var entries []map[string]any
+		for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("writes a line per wrap and report", func(t *testing.T) {
+		var out bytes.Buffer
+		ew := NewEventWriter(&out)
+
+		// With wraps too, so the cause is built before attaching
+		cause := With(errors.New("disk full"), "volume", "data")
+		detach := ew.Attach()
+		err := w.Wrap(cause)
+		detach()
+		_ = w.Wrap(errors.New("not written"))
+		ew.Report(err)
+		ew.Report(nil)
+
+		entries := decode(t, out.String())
+		require.Len(t, entries, 2)
+		assert.Equal(t, "wrap", entries[0]["event"])
+		assert.Equal(t, "2024-05-01T12:00:00Z", entries[0]["time"])
+		assert.Equal(t, "disk full", entries[0]["message"])
+		assert.Equal(t, ID(err), entries[0]["id"])
+		assert.NotEmpty(t, entries[0]["frames"])
+		assert.Equal(t, "report", entries[1]["event"])
+		assert.NoError(t, ew.Err())
+	})
+
+	t.Run("flushes buffered writers and rotates between lines", func(t *testing.T) {
+		var first, second bytes.Buffer
+		buffered := bufio.NewWriter(&first)
+		ew := NewEventWriter(buffered)
+
+		ew.Report(errors.New("before rotation"))
+		assert.Contains(t, first.String(), "before rotation", "lines don't wait in the buffer")
+
+		old := ew.Rotate(&second)
+		assert.Same(t, buffered, old)
+		ew.Report(errors.New("after rotation"))
+
+		assert.Len(t, decode(t, first.String()), 1)
+		assert.Len(t, decode(t, second.String()), 1)
+		assert.Contains(t, second.String(), "after rotation")
+	})
+
+	t.Run("keeps the first write error", func(t *testing.T) {
+		ew := NewEventWriter(failingWriter{})
+		ew.Report(errors.New("boom"))
+		ew.Report(errors.New("boom"))
+
+		assert.EqualError(t, ew.Err(), "disk full")
+	})
+}
@@ -26,32 +26,33 @@ func Example() {
 }
 
 func Example_output() {
-	// this example shows what errx output looks like
-	// Note: actual timestamps and line numbers will vary
+	// deterministic mode keeps the output stable across refactors:
+	// line numbers become NN and only wrap sites are listed
+	errx.SetConfig(errx.Config{Deterministic: true})
+	defer errx.SetConfig(errx.Config{})
 
-	originalErr := errors.New("database connection failed")
-	wrappedErr := errx.Wrap(originalErr)
+	fetch := func() error {
+		return errx.Wrap(errors.New("database connection failed"))
+	}
 
-	// the actual output will look similar to this:
-	// standard format shows all context in one line:
-	fmt.Println("Example standard format:")
-	fmt.Println("main.function (file.go:15): database connection failed")
+	err := fetch()
+	if err != nil {
+		err = errx.Wrap(err)
+	}
 
-	fmt.Println()
-	fmt.Println("Example verbose format:")
-	fmt.Println("[0] main.function (file.go:15): database connection failed")
-	fmt.Println("[1] main.caller (file.go:20): database connection failed")
+	fmt.Println("Standard format:")
+	fmt.Println(err)
 
 	fmt.Println()
-	fmt.Println("Error preserved:", errors.Is(wrappedErr, originalErr))
+	fmt.Println("Verbose format:")
+	fmt.Printf("%+v", err)
 
 	// Output:
-	// Example standard format:
-	// main.function (file.go:15): database connection failed
-	//
-	// Example verbose format:
-	// [0] main.function (file.go:15): database connection failed
-	// [1] main.caller (file.go:20): database connection failed
+	// Standard format:
+	// examples.Example_output (examples_test.go:NN): examples.Example_output.func1 (examples_test.go:NN): database connection failed
 	//
-	// Error preserved: true
+	// Verbose format:
+	// [0] examples.Example_output (examples_test.go:NN): database connection failed
+	// [1] examples.Example_output.func1 (examples_test.go:NN): database connection failed
+	// id: 0000-0000
 }
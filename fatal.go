@@ -0,0 +1,53 @@
+package errx
+
+import "runtime"
+
+// AttrGoroutines is the key of the goroutine dump attached by WrapFatal.
+const AttrGoroutines = "goroutines"
+
+// maxDumpSize bounds the goroutine dump captured by WrapFatal.
+const maxDumpSize = 16 << 20
+
+// GoroutineDump holds the stacks of all goroutines, as printed by
+// runtime.Stack. It is stored as captured and only turned into text when
+// rendered.
+type GoroutineDump []byte
+
+// String returns the dump as text.
+func (d GoroutineDump) String() string {
+	return string(d)
+}
+
+// MarshalText encodes the dump as text, so it reads as a string in JSON
+// rather than as base64.
+func (d GoroutineDump) MarshalText() ([]byte, error) {
+	return d, nil
+}
+
+// WrapFatal wraps err like Wrap and attaches the stacks of all goroutines
+// as the AttrGoroutines attribute, for failures such as deadlocks and
+// stuck workers where the wrapping goroutine's chain doesn't tell the
+// whole story. Capturing stops the world briefly, so keep it for errors
+// the program won't recover from. Dumps are cut at 16 MiB.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func WrapFatal(err error) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+
+	// the dump is attached before the OnWrap hooks see the error
+	return wrap(nil, err, 2, contextFrame{}, Attr{Key: AttrGoroutines, Value: captureGoroutines()})
+}
+
+// captureGoroutines returns the stacks of all goroutines, growing the
+// buffer until they fit or reach maxDumpSize.
+func captureGoroutines() GoroutineDump {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxDumpSize {
+			return GoroutineDump(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
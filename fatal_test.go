@@ -0,0 +1,51 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapFatal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WrapFatal(nil))
+	})
+
+	t.Run("attaches all goroutines", func(t *testing.T) {
+		t.Parallel()
+
+		started, blocked := make(chan struct{}), make(chan struct{})
+		defer close(blocked)
+		go func() {
+			close(started)
+			<-blocked
+		}()
+		<-started
+
+		err := WrapFatal(errors.New("workers stuck"))
+
+		assert.Regexp(t, `^errx\.TestWrapFatal\.\S+ \(fatal_test\.go:\d+\): `, err.Error())
+
+		dump, ok := Value[GoroutineDump](err, AttrGoroutines)
+		require.True(t, ok)
+		assert.Contains(t, dump.String(), "errx.TestWrapFatal")
+		assert.Contains(t, dump.String(), "created by github.com/alesr/errx.TestWrapFatal", "other goroutines are included")
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\ngoroutines: goroutine ")
+	})
+
+	t.Run("encodes as text", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(Attr{Key: AttrGoroutines, Value: GoroutineDump("goroutine 1 [running]:")})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"key":"goroutines","value":"goroutine 1 [running]:"}`, string(data))
+	})
+}
@@ -0,0 +1,187 @@
+package errx
+
+import (
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// FrameFields selects the fields of frames captured or rendered, as a
+// combination of the Field constants.
+type FrameFields uint8
+
+const (
+	// FieldFunction is the function name, "pkg.Func".
+	FieldFunction FrameFields = 1 << iota
+	// FieldFile is the source file, "file.go".
+	FieldFile
+	// FieldLine is the line number within the file.
+	FieldLine
+	// FieldTime is the capture time.
+	FieldTime
+	// FieldTags are the tags passed to Wrap, "#tag".
+	FieldTags
+
+	// AllFields selects every field.
+	AllFields = FieldFunction | FieldFile | FieldLine | FieldTime | FieldTags
+)
+
+// Has reports whether f selects every field of fields.
+func (f FrameFields) Has(fields FrameFields) bool {
+	return f&fields == fields
+}
+
+// CaptureFields sets the frame fields the Wrapper's errors record, see
+// Config.CaptureFields.
+func CaptureFields(f FrameFields) Option {
+	return optionFunc(func(c *Config) {
+		c.CaptureFields = f
+	})
+}
+
+// ErrorFields sets the frame fields Error renders for the Wrapper's
+// errors, see Config.ErrorFields.
+func ErrorFields(f FrameFields) Option {
+	return optionFunc(func(c *Config) {
+		c.ErrorFields = f
+	})
+}
+
+// VerboseFields sets the frame fields %+v renders for the Wrapper's
+// errors, see Config.VerboseFields.
+func VerboseFields(f FrameFields) Option {
+	return optionFunc(func(c *Config) {
+		c.VerboseFields = f
+	})
+}
+
+// RecordFields sets the frame fields NewRecord and Encode keep for the
+// Wrapper's errors, see Config.RecordFields.
+func RecordFields(f FrameFields) Option {
+	return optionFunc(func(c *Config) {
+		c.RecordFields = f
+	})
+}
+
+// captureFields returns the fields c captures, all of them unless set.
+// The function is always captured: deduplication and trimming match on it.
+func (c *Config) captureFields() FrameFields {
+	if c.CaptureFields == 0 {
+		return AllFields
+	}
+	return c.CaptureFields | FieldFunction
+}
+
+// errorFields returns the fields Error renders, all but the time unless set.
+func (c *Config) errorFields() FrameFields {
+	if c.ErrorFields == 0 {
+		return FieldFunction | FieldFile | FieldLine
+	}
+	return c.ErrorFields
+}
+
+// verboseFields returns the fields %+v renders, all of them unless set.
+func (c *Config) verboseFields() FrameFields {
+	if c.VerboseFields == 0 {
+		return AllFields
+	}
+	return c.VerboseFields
+}
+
+// recordFields returns the fields records keep, all of them unless set.
+func (c *Config) recordFields() FrameFields {
+	if c.RecordFields == 0 {
+		return AllFields
+	}
+	return c.RecordFields
+}
+
+// errorTime renders t for Error output when fields include the time.
+// Elapsed times need the whole chain, so only TimeAgo is kept; other
+// formats show timestamps.
+func (c *Config) errorTime(t time.Time, fields FrameFields) string {
+	if !fields.Has(FieldTime) {
+		return ""
+	}
+	if c.TimeFormat == TimeAgo {
+		return c.formatTime(TimeAgo, t, time.Time{})
+	}
+	return c.formatTime(TimeAbsolute, t, time.Time{})
+}
+
+// captured returns f without the fields c doesn't capture.
+func (c *Config) captured(f contextFrame) contextFrame {
+	fields := c.captureFields()
+	if !fields.Has(FieldFile) {
+		f.file = ""
+	}
+	if !fields.Has(FieldLine) {
+		f.line = 0
+	}
+	if !fields.Has(FieldTime) {
+		f.time = time.Time{}
+	}
+	if !fields.Has(FieldTags) {
+		f.tags = ""
+	}
+	return f
+}
+
+// keep returns f without the fields left out of fields.
+func (f Frame) keep(fields FrameFields) Frame {
+	if !fields.Has(FieldFunction) {
+		f.Function = ""
+	}
+	if !fields.Has(FieldFile) {
+		f.File = ""
+	}
+	if !fields.Has(FieldLine) {
+		f.Line = 0
+	}
+	if !fields.Has(FieldTime) {
+		f.Time = time.Time{}
+	}
+	if !fields.Has(FieldTags) {
+		f.Tags = ""
+	}
+	return f
+}
+
+// appendFields appends the location of f made of fields: "pkg.Func
+// (file.go:42)" with all of them, and "pkg.Func", "file.go:42" or
+// "pkg.Func (line 42)" with some. Fields f doesn't hold are left out.
+func (f contextFrame) appendFields(dst []byte, c *Config, fields FrameFields) []byte {
+	function := fields.Has(FieldFunction) && f.funcName != ""
+	file := fields.Has(FieldFile) && f.file != ""
+	line := fields.Has(FieldLine) && f.line != 0
+	if !function && !file && !line {
+		return dst
+	}
+
+	if function {
+		dst = append(dst, shortenFuncName(f.funcName)...)
+		if !file && !line {
+			return dst
+		}
+		dst = append(dst, " ("...)
+	}
+	if file {
+		dst = append(dst, filepath.Base(f.file)...)
+		if line {
+			dst = append(dst, TextLineSeparator...)
+		}
+	} else if line {
+		dst = append(dst, "line "...)
+	}
+	if line {
+		if c.Deterministic {
+			dst = append(dst, TextDeterministicLine...)
+		} else {
+			dst = strconv.AppendInt(dst, int64(f.line), 10)
+		}
+	}
+	if function {
+		dst = append(dst, ')')
+	}
+	return dst
+}
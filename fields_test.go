@@ -0,0 +1,92 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameFields(t *testing.T) {
+	t.Parallel()
+
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newWrapper := func(opts ...Option) *Wrapper {
+		return NewWrapper(append([]Option{Config{Clock: func() time.Time { return clock }, Depth: 1}}, opts...)...)
+	}
+
+	t.Run("error output", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			fields   FrameFields
+			expected string
+		}{
+			{name: "default", expected: `^errx\.TestFrameFields\.func[\d.]+ \(fields_test\.go:\d+\): boom$`},
+			{name: "function", fields: FieldFunction, expected: `^errx\.TestFrameFields\.func[\d.]+: boom$`},
+			{name: "file and line", fields: FieldFile | FieldLine, expected: `^fields_test\.go:\d+: boom$`},
+			{name: "function and line", fields: FieldFunction | FieldLine, expected: `^errx\.TestFrameFields\.func[\d.]+ \(line \d+\): boom$`},
+			{name: "line", fields: FieldLine, expected: `^line \d+: boom$`},
+			{name: "time", fields: FieldFile | FieldTime, expected: `^fields_test\.go \[2024-05-01T12:00:00Z\]: boom$`},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				err := newWrapper(ErrorFields(tc.fields)).Wrap(errors.New("boom"))
+				assert.Regexp(t, tc.expected, err.Error())
+			})
+		}
+	})
+
+	t.Run("verbose output independently", func(t *testing.T) {
+		t.Parallel()
+
+		w := newWrapper(ErrorFields(FieldFile|FieldLine), VerboseFields(FieldFunction), FrameTimes(TimeAbsolute))
+		err := w.Wrap(errors.New("boom"))
+
+		assert.Regexp(t, `^fields_test\.go:\d+: boom$`, err.Error())
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		assert.Regexp(t, `^\[0\] errx\.TestFrameFields\.func[\d.]+: boom$`, lines[0])
+	})
+
+	t.Run("capture", func(t *testing.T) {
+		t.Parallel()
+
+		err := newWrapper(CaptureFields(FieldLine)).Wrap(errors.New("boom"))
+		frames := Frames(err)
+		require.NotEmpty(t, frames)
+		assert.NotEmpty(t, frames[0].Function, "functions are always captured")
+		assert.Empty(t, frames[0].File)
+		assert.NotZero(t, frames[0].Line)
+		assert.True(t, frames[0].Time.IsZero())
+
+		err = newWrapper(CaptureFields(FieldLine)).Wrap(err)
+		assert.Empty(t, Frames(err)[0].File)
+	})
+
+	t.Run("records", func(t *testing.T) {
+		t.Parallel()
+
+		err := newWrapper(RecordFields(FieldFunction | FieldLine)).Wrap(errors.New("boom"))
+		data, encErr := Encode(err)
+		require.NoError(t, encErr)
+
+		var r Record
+		require.NoError(t, json.Unmarshal(data, &r))
+		require.NotEmpty(t, r.Frames)
+		assert.NotEmpty(t, r.Frames[0].Function)
+		assert.Empty(t, r.Frames[0].File)
+		assert.True(t, r.Frames[0].Time.IsZero())
+
+		// the error itself keeps everything
+		assert.NotEmpty(t, Frames(err)[0].File)
+	})
+}
@@ -0,0 +1,65 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+const keyGroupingKey = "errx.grouping_key"
+
+// Fingerprint returns a stable key identifying the kind of failure err
+// represents, meant for grouping alerts and deduplicating reports.
+// It is derived from the dynamic type of the root cause and the function
+// names along the captured frames, leaving out line numbers and messages
+// so it survives unrelated edits and variable data in messages.
+// A key set with WithGroupingKey takes precedence.
+// Returns an empty string if err is nil.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if key, ok := Value[string](err, keyGroupingKey); ok {
+		return key
+	}
+
+	h := fnv.New64a()
+	root := Root(err)
+	fmt.Fprintf(h, "%T", root)
+
+	var (
+		hasFrames bool
+		chain     visited
+	)
+	for e := err; e != nil && chain.add(e); e = errors.Unwrap(e) {
+		extErr, ok := e.(*extendedError)
+		if !ok {
+			continue
+		}
+		for _, frame := range extErr.frames() {
+			hasFrames = true
+			h.Write([]byte{0})
+			h.Write([]byte(frame.funcName))
+		}
+	}
+
+	// without frames the message is all we have to tell errors apart
+	if !hasFrames {
+		h.Write([]byte{0})
+		h.Write([]byte(root.Error()))
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// WithGroupingKey overrides the fingerprint computed for err, merging
+// failures raised from different call sites into one group or splitting
+// failures that share one.
+// Returns nil if err is nil.
+func WithGroupingKey(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyGroupingKey, Value: key}, 3)
+}
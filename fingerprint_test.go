@@ -0,0 +1,102 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newErr := func(msg string) error {
+		return Wrap(errors.New(msg))
+	}
+
+	otherSite := func(msg string) error {
+		return Wrap(errors.New(msg))
+	}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Fingerprint(nil))
+	})
+
+	t.Run("same call site", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Fingerprint(newErr("user 1 not found")), Fingerprint(newErr("user 2 not found")))
+	})
+
+	t.Run("different call sites", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotEqual(t, Fingerprint(newErr("not found")), Fingerprint(otherSite("not found")))
+	})
+
+	t.Run("different cause types", func(t *testing.T) {
+		t.Parallel()
+
+		typed := func() error {
+			return Wrap(fmt.Errorf("wrapped: %w", errors.New("not found")))
+		}
+		plain := func() error {
+			return Wrap(errors.New("not found"))
+		}
+
+		assert.NotEqual(t, Fingerprint(typed()), Fingerprint(plain()))
+	})
+
+	t.Run("stable for the same error", func(t *testing.T) {
+		t.Parallel()
+
+		err := newErr("not found")
+
+		assert.Equal(t, Fingerprint(err), Fingerprint(err))
+		assert.NotEmpty(t, Fingerprint(err))
+	})
+
+	t.Run("plain errors use the message", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, Fingerprint(errors.New("a")), Fingerprint(errors.New("a")))
+		assert.NotEqual(t, Fingerprint(errors.New("a")), Fingerprint(errors.New("b")))
+	})
+}
+
+func TestWithGroupingKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithGroupingKey(nil, "key"))
+	})
+
+	t.Run("merges call sites", func(t *testing.T) {
+		t.Parallel()
+
+		first := func() error {
+			return WithGroupingKey(errors.New("payment declined"), "payments.declined")
+		}
+		second := func() error {
+			return WithGroupingKey(errors.New("card declined"), "payments.declined")
+		}
+
+		assert.Equal(t, "payments.declined", Fingerprint(first()))
+		assert.Equal(t, Fingerprint(first()), Fingerprint(Wrap(second())))
+	})
+
+	t.Run("splits a call site", func(t *testing.T) {
+		t.Parallel()
+
+		build := func(key string) error {
+			return WithGroupingKey(errors.New("failed"), key)
+		}
+
+		assert.NotEqual(t, Fingerprint(build("a")), Fingerprint(build("b")))
+	})
+}
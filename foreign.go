@@ -0,0 +1,63 @@
+package errx
+
+import "errors"
+
+// ForeignFramer is implemented by errors that carry frames captured outside
+// the Go runtime, e.g. the JavaScript stack of a frontend error or a
+// traceback parsed from a Python service. When such an error is first
+// wrapped, its frames are listed after the Go frames, closest to the
+// cause, and render like them.
+type ForeignFramer interface {
+	ForeignFrames() []Frame
+}
+
+// WithForeignFrames attaches frames captured outside the Go runtime to err,
+// listed after its Go frames, closest to the cause. Frames go from the
+// outermost call to the innermost one, like Frames returns them. If err
+// is not an errx error yet it is wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func WithForeignFrames(err error, frames ...Frame) error {
+	if err == nil {
+		return nil
+	}
+
+	extErr, ok := err.(*extendedError)
+	if !ok {
+		if extErr, ok = wrap(nil, err, 2, contextFrame{}).(*extendedError); !ok {
+			extErr = &extendedError{err: err}
+		}
+	}
+
+	return &extendedError{
+		err:   extErr.err,
+		top:   extErr.top,
+		depth: extErr.depth,
+		base:  appendForeign(extErr.base[:len(extErr.base):len(extErr.base)], frames),
+		attrs: extErr.attrs,
+		cfg:   extErr.cfg,
+	}
+}
+
+// foreignFrames returns the frames carried by the first ForeignFramer
+// in err's chain. Errors below an errx error are skipped, their frames
+// were listed when that error was wrapped.
+func foreignFrames(err error) []Frame {
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *extendedError:
+			return nil
+		case ForeignFramer:
+			return e.ForeignFrames()
+		}
+	}
+	return nil
+}
+
+// appendForeign appends frames to dst in their internal form.
+func appendForeign(dst []contextFrame, frames []Frame) []contextFrame {
+	for _, frame := range frames {
+		dst = append(dst, importFrame(frame))
+	}
+	return dst
+}
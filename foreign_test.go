@@ -0,0 +1,109 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsError is a frontend error reported with its JavaScript stack.
+type jsError struct {
+	msg   string
+	stack []Frame
+}
+
+func (e *jsError) Error() string {
+	return e.msg
+}
+
+func (e *jsError) ForeignFrames() []Frame {
+	return e.stack
+}
+
+var jsStack = []Frame{
+	{Function: "onSubmit", File: "https://app.example.com/static/checkout.js", Line: 88},
+	{Function: "validateCard", File: "https://app.example.com/static/card.js", Line: 12},
+}
+
+func TestForeignFramer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("listed after the go frames", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(&jsError{msg: "invalid card number", stack: jsStack})
+
+		frames := Frames(err)
+		require.Greater(t, len(frames), 2)
+		assert.Equal(t, "github.com/alesr/errx.TestForeignFramer.func1", frames[0].Function)
+		assert.Equal(t, jsStack, frames[len(frames)-2:])
+		assert.Regexp(t, `: onSubmit \(checkout\.js:88\): validateCard \(card\.js:12\): invalid card number$`, err.Error())
+	})
+
+	t.Run("listed once", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(&jsError{msg: "invalid card number", stack: jsStack})
+		err = Wrap(fmt.Errorf("checkout: %w", err))
+		err = Wrap(err)
+
+		var count int
+		for _, frame := range Frames(err) {
+			if frame.Function == "onSubmit" {
+				count++
+			}
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestWithForeignFrames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithForeignFrames(nil, jsStack...))
+	})
+
+	t.Run("wraps plain errors", func(t *testing.T) {
+		t.Parallel()
+
+		cause := errors.New("invalid card number")
+		err := WithForeignFrames(cause, jsStack...)
+
+		assert.ErrorIs(t, err, cause)
+		frames := Frames(err)
+		assert.Equal(t, "github.com/alesr/errx.TestWithForeignFrames.func2", frames[0].Function)
+		assert.Equal(t, jsStack, frames[len(frames)-2:])
+		assert.NotEmpty(t, ID(err))
+	})
+
+	t.Run("appends to errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := With(errors.New("invalid card number"), "user_id", 42)
+		err := WithForeignFrames(wrapped, jsStack...)
+
+		assert.Len(t, Frames(err), len(Frames(wrapped))+2)
+		assert.Equal(t, Attrs(wrapped), Attrs(err))
+		assert.Equal(t, ID(wrapped), ID(err))
+
+		// the original error is untouched
+		assert.NotContains(t, wrapped.Error(), "onSubmit")
+	})
+
+	t.Run("labeled with their service", func(t *testing.T) {
+		t.Parallel()
+
+		remote := Frame{Function: "github.com/acme/billing.Charge", File: "/src/billing/charge.go", Line: 42, Service: "billing"}
+		err := WithForeignFrames(Wrap(errors.New("card declined")), remote)
+
+		assert.Equal(t, "[billing] billing.Charge (charge.go:42)", remote.String())
+		assert.Contains(t, err.Error(), ": [billing] billing.Charge (charge.go:42): card declined")
+		assert.Equal(t, remote, Frames(err)[len(Frames(err))-1])
+	})
+}
@@ -0,0 +1,134 @@
+package errx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoString renders e for %#v as the literal of the Record it captures,
+// laid out like gofmt would, e.g. for debugger output and failing test
+// dumps. Calling Err on the literal rebuilds an equivalent error. The
+// root cause, which a Record only holds the message of, is shown in a
+// comment.
+func (e *extendedError) GoString() string {
+	cause := strings.ReplaceAll(fmt.Sprintf("%#v", Root(e)), "\n", " ")
+	return NewRecord(e).goString("\t// cause: " + cause + "\n")
+}
+
+// GoString renders r as a Go literal, leaving out zero fields and the
+// Error rendering, which Err doesn't use.
+func (r Record) GoString() string {
+	return r.goString("")
+}
+
+// goString renders r as a Go literal starting with the lines in header.
+func (r Record) goString(header string) string {
+	var b strings.Builder
+	b.WriteString("errx.Record{\n" + header)
+
+	field := func(name, value string) {
+		b.WriteString("\t" + name + ": " + value + ",\n")
+	}
+	for _, f := range []struct{ name, value string }{
+		{"Message", r.Message},
+		{"CauseType", r.CauseType},
+		{"ID", r.ID},
+		{"PublicMessage", r.PublicMessage},
+		{"GroupingKey", r.GroupingKey},
+		{"Code", string(r.Code)},
+		{"Kind", string(r.Kind)},
+	} {
+		if f.value != "" {
+			field(f.name, strconv.Quote(f.value))
+		}
+	}
+	if r.Severity != 0 {
+		field("Severity", severityGoString(r.Severity))
+	}
+	if r.Runbook != "" {
+		field("Runbook", strconv.Quote(r.Runbook))
+	}
+	if r.Hint != "" {
+		field("Hint", strconv.Quote(r.Hint))
+	}
+	if len(r.FieldErrors) > 0 {
+		field("FieldErrors", fmt.Sprintf("%#v", r.FieldErrors))
+	}
+	if len(r.Scopes) > 0 {
+		field("Scopes", fmt.Sprintf("%#v", r.Scopes))
+	}
+	if r.Runtime != nil {
+		field("Runtime", fmt.Sprintf("&%#v", *r.Runtime))
+	}
+	if r.Truncated {
+		field("Truncated", "true")
+	}
+
+	if len(r.Frames) > 0 {
+		b.WriteString("\tFrames: []errx.Frame{\n")
+		for _, frame := range r.Frames {
+			b.WriteString("\t\t" + frame.goString() + ",\n")
+		}
+		b.WriteString("\t},\n")
+	}
+	if len(r.Attrs) > 0 {
+		b.WriteString("\tAttrs: []errx.Attr{\n")
+		for _, attr := range r.Attrs {
+			fmt.Fprintf(&b, "\t\t{Key: %q, Value: %#v},\n", attr.Key, attr.Value)
+		}
+		b.WriteString("\t},\n")
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+// goString renders f as an element of a []errx.Frame literal.
+func (f Frame) goString() string {
+	parts := []string{
+		"Function: " + strconv.Quote(f.Function),
+		"File: " + strconv.Quote(f.File),
+		"Line: " + strconv.Itoa(f.Line),
+	}
+	if !f.Time.IsZero() {
+		parts = append(parts, "Time: "+timeGoString(f.Time))
+	}
+	if f.Message != "" {
+		parts = append(parts, "Message: "+strconv.Quote(f.Message))
+	}
+	if f.WrapSite {
+		parts = append(parts, "WrapSite: true")
+	}
+	if f.Boundary {
+		parts = append(parts, "Boundary: true")
+	}
+	if f.Args != "" {
+		parts = append(parts, "Args: "+strconv.Quote(f.Args))
+	}
+	if f.Tags != "" {
+		parts = append(parts, "Tags: "+strconv.Quote(f.Tags))
+	}
+	if f.Service != "" {
+		parts = append(parts, "Service: "+strconv.Quote(f.Service))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// timeGoString renders t in UTC as a time.Date call.
+func timeGoString(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, time.UTC)",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+}
+
+// severityGoString renders s as the name of its constant, or as a
+// conversion for unknown severities.
+func severityGoString(s Severity) string {
+	if s > 0 && int(s) < len(severityNames) {
+		name := severityNames[s]
+		return "errx.Severity" + strings.ToUpper(name[:1]) + name[1:]
+	}
+	return "errx.Severity(" + strconv.Itoa(int(s)) + ")"
+}
@@ -0,0 +1,71 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("record literal", func(t *testing.T) {
+		t.Parallel()
+
+		r := Record{
+			Message:  "no rows",
+			ID:       "7KQ2-M9XD",
+			Code:     "billing.invoice.not_found",
+			Severity: SeverityCritical,
+			Scopes:   []string{"import batch 42"},
+			Frames: []Frame{
+				{Function: "github.com/me/app/db.Query", File: "/src/db.go", Line: 42, Time: time.Date(2024, time.May, 1, 12, 0, 0, 5, time.UTC), Message: "loading invoice", WrapSite: true},
+				{Function: "main.main", File: "/src/main.go", Line: 7, Service: "billing"},
+			},
+			Attrs: []Attr{{Key: "tenant", Value: "acme"}, {Key: "attempt", Value: 2}},
+		}
+
+		assert.Equal(t, `errx.Record{
+	Message: "no rows",
+	ID: "7KQ2-M9XD",
+	Code: "billing.invoice.not_found",
+	Severity: errx.SeverityCritical,
+	Scopes: []string{"import batch 42"},
+	Frames: []errx.Frame{
+		{Function: "github.com/me/app/db.Query", File: "/src/db.go", Line: 42, Time: time.Date(2024, time.May, 1, 12, 0, 0, 5, time.UTC), Message: "loading invoice", WrapSite: true},
+		{Function: "main.main", File: "/src/main.go", Line: 7, Service: "billing"},
+	},
+	Attrs: []errx.Attr{
+		{Key: "tenant", Value: "acme"},
+		{Key: "attempt", Value: 2},
+	},
+}`, fmt.Sprintf("%#v", r))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := With(Wrapf(errors.New("no rows"), "loading invoice"), "tenant", "acme")
+		got := fmt.Sprintf("%#v", err)
+
+		assert.Regexp(t, `^errx\.Record\{
+	// cause: &errors\.errorString\{s:"no rows"\}
+	Message: "no rows",
+	CauseType: "\*errors\.errorString",
+	ID: "\w{4}-\w{4}",
+	Frames: \[\]errx\.Frame\{
+		\{Function: "github\.com/alesr/errx\.TestGoString\.\S+", File: ".*gostring_test\.go", Line: \d+, Time: time\.Date\(.*\), Message: "loading invoice", WrapSite: true\},
+`, got)
+		assert.Contains(t, got, "\tAttrs: []errx.Attr{\n\t\t{Key: \"tenant\", Value: \"acme\"},\n\t},\n}")
+		assert.Equal(t, got, err.(fmt.GoStringer).GoString())
+	})
+
+	t.Run("unknown severities", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Contains(t, Record{Severity: 9}.GoString(), "Severity: errx.Severity(9),")
+	})
+}
@@ -0,0 +1,48 @@
+package errx
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	helpersMu sync.Mutex
+	helpers   atomic.Pointer[map[string]bool]
+)
+
+// MarkHelper marks the function calling it as a helper wrapping errors on
+// behalf of its callers, like testing.T.Helper does for test helpers:
+//
+//	func dbErr(err error, query string) error {
+//	    errx.MarkHelper()
+//	    return errx.Wrapf(err, "query %s", query)
+//	}
+//
+// Frames of helpers are left out when capturing, so the wrap site
+// recorded is the helper's caller, along with the message the helper
+// added, and the stack scanned above it skips helpers too. Unlike
+// Config.TrimAbove, it applies to every wrap, and needs no list kept in
+// sync with the code. Marking a function again costs a map lookup.
+func MarkHelper() {
+	funcName, _, _, ok := caller(1)
+	if !ok || isHelper(funcName) {
+		return
+	}
+
+	helpersMu.Lock()
+	defer helpersMu.Unlock()
+
+	marked := make(map[string]bool)
+	if p := helpers.Load(); p != nil {
+		maps.Copy(marked, *p)
+	}
+	marked[funcName] = true
+	helpers.Store(&marked)
+}
+
+// isHelper reports whether funcName was marked with MarkHelper.
+func isHelper(funcName string) bool {
+	p := helpers.Load()
+	return p != nil && (*p)[funcName]
+}
@@ -0,0 +1,68 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queryErr(err error, query string) error {
+	MarkHelper()
+	return Wrapf(err, "query %s", query)
+}
+
+func storageErr(err error) error {
+	MarkHelper()
+	return queryErr(err, "SELECT 1")
+}
+
+func withRetries(fn func() error) error {
+	MarkHelper()
+	return fn()
+}
+
+func TestMarkHelper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first wrap", func(t *testing.T) {
+		t.Parallel()
+
+		err := queryErr(errors.New("connection refused"), "SELECT 1")
+
+		frames := Frames(err)
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "github.com/alesr/errx.TestMarkHelper.func1", frames[0].Function)
+		assert.Equal(t, "query SELECT 1", frames[0].Message)
+		assert.True(t, frames[0].WrapSite)
+		for _, frame := range frames {
+			assert.NotContains(t, frame.Function, "queryErr")
+		}
+	})
+
+	t.Run("re-wrap through nested helpers", func(t *testing.T) {
+		t.Parallel()
+
+		err := storageErr(Wrap(errors.New("connection refused")))
+
+		frames := Frames(err)
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "github.com/alesr/errx.TestMarkHelper.func2", frames[0].Function)
+		assert.Equal(t, "query SELECT 1", frames[0].Message)
+	})
+
+	t.Run("helpers among callers", func(t *testing.T) {
+		t.Parallel()
+
+		err := withRetries(func() error {
+			return Wrap(errors.New("timeout"))
+		})
+
+		functions := fmt.Sprint(Frames(err))
+		assert.Contains(t, functions, "errx.TestMarkHelper.func3.1")
+		assert.Contains(t, functions, "errx.TestMarkHelper.func3 ")
+		assert.NotContains(t, functions, "withRetries")
+	})
+}
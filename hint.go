@@ -0,0 +1,21 @@
+package errx
+
+const keyHint = "errx.hint"
+
+// WithHint attaches an actionable next step for the user, such as
+// "retry with --force", kept apart from the diagnostic message so CLIs
+// and API responses can show it on its own.
+// Returns nil if err is nil.
+func WithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyHint, Value: hint}, 3)
+}
+
+// Hint returns the hint attached to err with WithHint, or an empty string.
+// When set more than once, the outermost hint wins.
+func Hint(err error) string {
+	hint, _ := Value[string](err, keyHint)
+	return hint
+}
@@ -0,0 +1,41 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithHint(nil, "retry with --force"))
+		assert.Empty(t, Hint(nil))
+		assert.Empty(t, Hint(errors.New("plain")))
+	})
+
+	t.Run("preserved through wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithHint(errors.New("file exists"), "retry with --force")
+		err = Wrap(fmt.Errorf("writing config: %w", err))
+
+		assert.Equal(t, "retry with --force", Hint(err))
+		assert.NotContains(t, err.Error(), "--force")
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nhint: retry with --force\n")
+	})
+
+	t.Run("outermost wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithHint(errors.New("file exists"), "retry with --force")
+		err = WithHint(err, "pick another name")
+
+		assert.Equal(t, "pick another name", Hint(err))
+	})
+}
@@ -0,0 +1,91 @@
+package errx
+
+import "errors"
+
+// keySetAt follows the code, kind, severity and public message attributes
+// set by a call along the chain, holding the frame of that call.
+const keySetAt = "errx.set_at"
+
+// Change is a code, kind, severity or public message set on an error,
+// as listed by History.
+type Change struct {
+	// Field names what was set: "code", "kind", "severity" or
+	// "public_message", like the fields of Record.
+	Field string
+	// Value is the value set: a Code, a Kind, a Severity or the public
+	// message string.
+	Value any
+	// Frame is the call that set it, recorded when Config.HistoryFrames
+	// is set. It is the zero Frame otherwise, and when the value came
+	// from elsewhere, such as Config.DefaultAttrs, the enrichment of OS
+	// errors or a decoded record.
+	Frame Frame
+}
+
+// historyFields maps the attribute keys History tracks to their fields.
+var historyFields = map[string]string{
+	keyCode:          "code",
+	keyKind:          "kind",
+	keySeverity:      "severity",
+	keyPublicMessage: "public_message",
+}
+
+// History lists every code, kind, severity and public message set along
+// err's chain, from the first one set to the last, along with the call
+// that set each when Config.HistoryFrames is set. The last change of a
+// field is the one CodeOf, KindOf, SeverityOf and PublicMessage return,
+// so History tells which layer overrode what, e.g. when an HTTP status
+// doesn't match the kind set where the error happened.
+// Returns nil if err is nil or none of them was set.
+func History(err error) []Change {
+	var layers []*extendedError
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		if extErr, ok := err.(*extendedError); ok {
+			layers = append(layers, extErr)
+		}
+	}
+
+	var changes []Change
+	for i := len(layers) - 1; i >= 0; i-- {
+		attrs := layers[i].attrs
+		for j, attr := range attrs {
+			field, ok := historyFields[attr.Key]
+			if !ok {
+				continue
+			}
+
+			change := Change{Field: field, Value: attr.Value}
+			if j+1 < len(attrs) && attrs[j+1].Key == keySetAt {
+				if at, ok := attrs[j+1].Value.(contextFrame); ok {
+					change.Frame = at.export()
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// setAt returns the attribute recording that the call skip levels above
+// setAt's caller set attr, when attr is tracked by History and c records
+// history frames.
+func (c *Config) setAt(attr Attr, skip int) (Attr, bool) {
+	if !c.HistoryFrames {
+		return Attr{}, false
+	}
+	if _, ok := historyFields[attr.Key]; !ok {
+		return Attr{}, false
+	}
+
+	funcName, file, line, ok := caller(skip + 1)
+	if !ok {
+		return Attr{}, false
+	}
+	return Attr{Key: keySetAt, Value: contextFrame{
+		funcName: funcName,
+		file:     file,
+		line:     line,
+		time:     c.now(),
+	}}, true
+}
@@ -0,0 +1,84 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no call sites by default", func(t *testing.T) {
+		t.Parallel()
+
+		changes := History(WithKind(Wrap(errors.New("no rows")), KindNotFound))
+		assert.Equal(t, []Change{{Field: "kind", Value: KindNotFound}}, changes)
+	})
+
+	t.Run("hidden from attributes", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithCode(Wrap(errors.New("boom")), "boom")
+		assert.Empty(t, Attrs(err))
+		assert.Equal(t, Code("boom"), CodeOf(err))
+	})
+
+	t.Run("nothing set", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, History(Wrap(errors.New("boom"))))
+		assert.Nil(t, History(nil))
+	})
+}
+
+func TestHistoryFrames(t *testing.T) {
+	WithTestConfig(t, Config{HistoryFrames: true})
+
+	t.Run("lists changes with their call sites", func(t *testing.T) {
+		repository := func() error {
+			return WithKind(Wrap(errors.New("no rows")), KindNotFound)
+		}
+		service := func() error {
+			err := WithCode(repository(), "billing.invoice.not_found")
+			return WithSeverity(err, SeverityWarning)
+		}
+		transport := func() error {
+			return WithKind(fmt.Errorf("handling request: %w", service()), KindInternal)
+		}
+
+		changes := History(transport())
+		require.Len(t, changes, 4)
+
+		assert.Equal(t, "kind", changes[0].Field)
+		assert.Equal(t, KindNotFound, changes[0].Value)
+		assert.Equal(t, "github.com/alesr/errx.TestHistoryFrames.func1.1", changes[0].Frame.Function)
+		assert.Equal(t, "history_test.go", filepath.Base(changes[0].Frame.File))
+
+		assert.Equal(t, "code", changes[1].Field)
+		assert.Equal(t, Code("billing.invoice.not_found"), changes[1].Value)
+		assert.Equal(t, "github.com/alesr/errx.TestHistoryFrames.func1.2", changes[1].Frame.Function)
+
+		assert.Equal(t, "severity", changes[2].Field)
+		assert.Equal(t, SeverityWarning, changes[2].Value)
+
+		// the transport layer overrode the kind
+		assert.Equal(t, "kind", changes[3].Field)
+		assert.Equal(t, KindInternal, changes[3].Value)
+		assert.Equal(t, "github.com/alesr/errx.TestHistoryFrames.func1.3", changes[3].Frame.Function)
+	})
+
+	t.Run("values set without a call have no frame", func(t *testing.T) {
+		w := NewWrapper(Config{HistoryFrames: true}, DefaultAttrs(Attr{Key: keyKind, Value: KindUnavailable}))
+		changes := History(WithPublicMessage(w.Wrap(errors.New("boom")), "try again later"))
+		require.Len(t, changes, 2)
+
+		assert.Equal(t, Change{Field: "kind", Value: KindUnavailable}, changes[0])
+		assert.Equal(t, "public_message", changes[1].Field)
+		assert.NotEmpty(t, changes[1].Frame.Function)
+	})
+}
@@ -0,0 +1,62 @@
+package errx
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// hook is a registered OnWrap callback. It is held by pointer so
+// registrations of the same function can be told apart.
+type hook struct {
+	fn func(err error)
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Pointer[[]*hook]
+)
+
+// OnWrap registers fn to be called with the result of every wrap, whether
+// done by the package-level functions or by a Wrapper. It is the extension
+// point for in-process observers such as errxring.
+// fn runs synchronously on the wrapping goroutine, so it must be fast and
+// safe for concurrent use, and must not wrap errors itself.
+// The returned function unregisters fn.
+func OnWrap(fn func(err error)) (remove func()) {
+	h := &hook{fn: fn}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	var registered []*hook
+	if p := hooks.Load(); p != nil {
+		registered = *p
+	}
+	registered = append(slices.Clip(registered), h)
+	hooks.Store(&registered)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			hooksMu.Lock()
+			defer hooksMu.Unlock()
+
+			remaining := slices.DeleteFunc(slices.Clone(*hooks.Load()), func(other *hook) bool {
+				return other == h
+			})
+			hooks.Store(&remaining)
+		})
+	}
+}
+
+// runHooks passes err to the registered OnWrap callbacks.
+func runHooks(err error) {
+	p := hooks.Load()
+	if p == nil {
+		return
+	}
+	for _, h := range *p {
+		h.fn(err)
+	}
+}
@@ -0,0 +1,72 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnWrap(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []error
+	)
+	remove := OnWrap(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, err)
+	})
+	defer remove()
+
+	first := Wrap(errors.New("boom"))
+	second := Wrapf(first, "retrying")
+	third := NewWrapper().Wrap(second)
+
+	require.Len(t, seen, 3)
+	assert.Same(t, first, seen[0])
+	assert.Same(t, second, seen[1])
+	assert.Same(t, third, seen[2])
+
+	t.Run("not called for ignored or nil errors", func(t *testing.T) {
+		WithTestConfig(t, Config{Ignore: []error{errSentinel}})
+
+		seen = nil
+		_ = Wrap(nil)
+		_ = Wrap(errSentinel)
+		_ = WrapIf(false, errors.New("boom"))
+		assert.Empty(t, seen)
+	})
+
+	t.Run("sees the attributes attached by the wrap call", func(t *testing.T) {
+		ctx := PushScope(context.Background(), "import")
+
+		seen = nil
+		_ = WrapCtx(ctx, errors.New("boom"))
+		_ = WrapCtx(ctx, Wrap(errors.New("boom")))
+		_ = WrapFatal(errors.New("boom"))
+
+		require.Len(t, seen, 4)
+		assert.Equal(t, []string{"import"}, ScopesOf(seen[0]), "on first wrap")
+		assert.Equal(t, []string{"import"}, ScopesOf(seen[2]), "on rewrap")
+		_, ok := Value[GoroutineDump](seen[3], AttrGoroutines)
+		assert.True(t, ok)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		var calls int
+		removeOther := OnWrap(func(error) { calls++ })
+
+		_ = Wrap(errors.New("boom"))
+		removeOther()
+		removeOther()
+		_ = Wrap(errors.New("boom"))
+
+		assert.Equal(t, 1, calls)
+	})
+}
+
+var errSentinel = errors.New("sentinel")
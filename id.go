@@ -0,0 +1,91 @@
+package errx
+
+import (
+	"math/rand/v2"
+)
+
+const (
+	keyID            = "errx.id"
+	keyPublicMessage = "errx.public_message"
+)
+
+// defaultPublicMessage is shown to end users when no public message was set.
+const defaultPublicMessage = "internal error"
+
+// crockford is the Crockford base32 alphabet. It leaves out I, L, O and U
+// so IDs survive being read out loud or typed back by hand.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ID returns the support ID assigned to err when it was first wrapped,
+// or an empty string if err has no errx error in its chain.
+func ID(err error) string {
+	id, _ := Value[string](err, keyID)
+	return id
+}
+
+// WithID assigns id as err's support ID, replacing the generated one.
+// Use it to carry an ID received from an upstream service.
+// Returns nil if err is nil.
+func WithID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyID, Value: id}, 3)
+}
+
+// PublicMessage returns a message that is safe to show to end users:
+// the message set with WithPublicMessage (or a generic one) followed by
+// the support ID, e.g. "internal error (ref: 7KQ2-M9XD)".
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	msg, ok := Value[string](err, keyPublicMessage)
+	if !ok {
+		msg = defaultPublicMessage
+	}
+	return withRef(err, msg)
+}
+
+// withRef appends err's support ID to msg, if it has one.
+func withRef(err error, msg string) string {
+	if id := ID(err); id != "" {
+		return msg + " (ref: " + id + ")"
+	}
+	return msg
+}
+
+// WithPublicMessage sets the user-facing message returned by PublicMessage.
+// Returns nil if err is nil.
+func WithPublicMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyPublicMessage, Value: msg}, 3)
+}
+
+// newID returns a random ID formatted as two groups of four characters.
+func newID(c *Config) string {
+	if c.Deterministic {
+		return "0000-0000"
+	}
+
+	n := rand.Uint64()
+
+	var b [9]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		if i == 4 {
+			b[i] = '-'
+			continue
+		}
+		b[i] = crockford[n&31]
+		n >>= 5
+	}
+	return string(b[:])
+}
+
+// hasExtendedError reports whether err's chain already holds an errx error.
+func hasExtendedError(err error) bool {
+	return firstExtended(err) != nil
+}
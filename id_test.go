@@ -0,0 +1,108 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("assigned at first wrap", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("test error"))
+
+		assert.Regexp(t, regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`), ID(err))
+	})
+
+	t.Run("stable across wraps", func(t *testing.T) {
+		t.Parallel()
+
+		first := Wrap(errors.New("test error"))
+		second := Wrap(first)
+		third := Wrap(fmt.Errorf("context: %w", second))
+
+		assert.Equal(t, ID(first), ID(second))
+		assert.Equal(t, ID(first), ID(third))
+	})
+
+	t.Run("unique per error", func(t *testing.T) {
+		t.Parallel()
+
+		first := Wrap(errors.New("test error"))
+		second := Wrap(errors.New("test error"))
+
+		assert.NotEqual(t, ID(first), ID(second))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, ID(errors.New("plain")))
+		assert.Empty(t, ID(nil))
+	})
+
+	t.Run("accepts an upstream id", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithID(errors.New("test error"), "UPSTREAM-1")
+		err = Wrap(err)
+
+		assert.Equal(t, "UPSTREAM-1", ID(err))
+		assert.Nil(t, WithID(nil, "UPSTREAM-1"))
+	})
+
+	t.Run("not exposed as attribute", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Attrs(Wrap(errors.New("test error"))))
+	})
+
+	t.Run("shown in verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("test error"))
+
+		assert.Contains(t, fmt.Sprintf("%+v", err), "id: "+ID(err))
+		assert.NotContains(t, err.Error(), ID(err))
+	})
+}
+
+func TestPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	err := WithID(errors.New("password column missing"), "7KQ2-M9XD")
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("plain"), "internal error"},
+		{"default message", err, "internal error (ref: 7KQ2-M9XD)"},
+		{"custom message", WithPublicMessage(err, "could not sign in"), "could not sign in (ref: 7KQ2-M9XD)"},
+		{"survives wrapping", Wrap(WithPublicMessage(err, "could not sign in")), "could not sign in (ref: 7KQ2-M9XD)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, PublicMessage(tc.err))
+		})
+	}
+
+	t.Run("does not leak the cause", func(t *testing.T) {
+		t.Parallel()
+
+		msg := PublicMessage(Wrap(errors.New("password column missing")))
+		require.NotEmpty(t, msg)
+		assert.NotContains(t, msg, "password")
+	})
+}
@@ -0,0 +1,121 @@
+package errx
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Join joins errs like errors.Join, ordered by the time they were first
+// captured rather than by the order their goroutines finished. Errors
+// captured at the same time keep the order they were passed in, and
+// errors without a capture time, not wrapped by errx, follow the others
+// in that order.
+// The order is only as stable as the clock: errors captured close
+// together can swap places from one run to the next. In deterministic
+// mode, where capture times are fixed, ties are ordered by message
+// instead, so golden files don't change.
+// Returns nil if every error is nil.
+func Join(errs ...error) error {
+	kept := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			kept = append(kept, err)
+		}
+	}
+	sortByCapture(kept, func(err error) error { return err })
+	return errors.Join(kept...)
+}
+
+// Collector gathers the errors of concurrent work, such as the goroutines
+// of a fan-out, and joins them with Join:
+//
+//	var errs errx.Collector
+//	for _, shard := range shards {
+//	    go func() {
+//	        defer wg.Done()
+//	        errs.Add(shard.Sync(ctx))
+//	    }()
+//	}
+//	wg.Wait()
+//	return errs.Err()
+//
+// The zero Collector is ready to use. It is safe for concurrent use.
+type Collector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// Add adds err to the errors collected. It does nothing if err is nil.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+// Err returns the errors collected so far joined with Join.
+// Returns nil if there are none.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Join(c.errs...)
+}
+
+// sortByCapture stably sorts s by the capture time of the error errOf
+// returns for each element, errors without one last. In deterministic
+// mode, ties are sorted by message.
+func sortByCapture[T any](s []T, errOf func(T) error) {
+	type entry struct {
+		v   T
+		t   time.Time
+		msg string
+	}
+	deterministic := loadConfig().Deterministic
+	entries := make([]entry, len(s))
+	for i, v := range s {
+		entries[i] = entry{v: v, t: captureTime(errOf(v))}
+		if deterministic {
+			entries[i].msg = errOf(v).Error()
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		switch {
+		case a.t.IsZero() && b.t.IsZero():
+		case a.t.IsZero():
+			return 1
+		case b.t.IsZero():
+			return -1
+		default:
+			if c := a.t.Compare(b.t); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.msg, b.msg)
+	})
+	for i, e := range entries {
+		s[i] = e.v
+	}
+}
+
+// captureTime returns the earliest time a frame of err's chain was
+// captured, or the zero time when it holds none.
+func captureTime(err error) time.Time {
+	var first time.Time
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+		if t := earliest(extErr.frames()); !t.IsZero() && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+	}
+	return first
+}
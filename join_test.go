@@ -0,0 +1,96 @@
+package errx
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	capturedAt := func(offset time.Duration, msg string) error {
+		at := base.Add(offset)
+		return NewWrapper(Config{Depth: 1}, TimeSource(func() time.Time { return at })).Wrap(errors.New(msg))
+	}
+
+	t.Run("by capture time then insertion", func(t *testing.T) {
+		t.Parallel()
+
+		late := capturedAt(2*time.Second, "late")
+		plain := errors.New("plain")
+		early := capturedAt(time.Second, "early")
+		tiedFirst := capturedAt(0, "tied first")
+		tiedSecond := capturedAt(0, "tied second")
+
+		err := Join(late, nil, plain, early, tiedFirst, tiedSecond)
+		require.Error(t, err)
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok)
+		assert.Equal(t, []error{tiedFirst, tiedSecond, early, late, plain}, joined.Unwrap())
+		assert.ErrorIs(t, err, plain)
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, Join())
+		assert.NoError(t, Join(nil, nil))
+	})
+
+	t.Run("collector", func(t *testing.T) {
+		t.Parallel()
+
+		errs := make([]error, 20)
+		for i := range errs {
+			errs[i] = capturedAt(time.Duration(i)*time.Millisecond, "shard "+strconv.Itoa(i))
+		}
+
+		var want string
+		for range 5 {
+			var c Collector
+			var wg sync.WaitGroup
+			for i := len(errs) - 1; i >= 0; i-- {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					c.Add(errs[i])
+					c.Add(nil)
+				}()
+			}
+			wg.Wait()
+
+			got := c.Err().Error()
+			if want == "" {
+				want = got
+			}
+			assert.Equal(t, want, got)
+		}
+		assert.Regexp(t, `^errx\.TestJoin\.func1 \(join_test\.go:\d+\): shard 0\n`, want)
+
+		var empty Collector
+		assert.NoError(t, empty.Err())
+	})
+}
+
+func TestJoinDeterministic(t *testing.T) {
+	WithTestConfig(t, Config{Deterministic: true})
+
+	// deterministic mode captures every error at the same time
+	b, a := Wrap(errors.New("b")), Wrap(errors.New("a"))
+	plainB, plainA := errors.New("plain b"), errors.New("plain a")
+
+	err := Join(plainB, b, plainA, a)
+	require.Error(t, err)
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+	assert.Equal(t, []error{a, b, plainA, plainB}, joined.Unwrap(), "ties are ordered by message")
+}
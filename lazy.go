@@ -0,0 +1,43 @@
+package errx
+
+import "sync"
+
+// WrapLazy wraps err like Wrap and adds a message built by fn to the
+// current frame. fn is only called the first time the error is rendered,
+// so expensive context such as serialized requests or state dumps costs
+// nothing on the failure path unless someone looks at it.
+// Returns nil if err is nil.
+func WrapLazy(err error, fn func() string) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+
+	return wrap(nil, err, 2, contextFrame{msg: &lazyMessage{fn: fn}})
+}
+
+// lazyMessage defers building a wrap message until it's rendered.
+// Messages known upfront are stored in msg with a nil fn and eager set.
+type lazyMessage struct {
+	once sync.Once
+	fn   func() string
+	msg  string
+	// eager is set on messages that were built upfront; msg never
+	// changes for them, so it can be read without going through once
+	eager bool
+}
+
+// eagerMessage returns a message that is already built.
+func eagerMessage(msg string) *lazyMessage {
+	return &lazyMessage{msg: msg, eager: true}
+}
+
+// String builds the message on first use and caches it.
+func (m *lazyMessage) String() string {
+	m.once.Do(func() {
+		if m.fn != nil {
+			m.msg = m.fn()
+			m.fn = nil
+		}
+	})
+	return m.msg
+}
@@ -0,0 +1,98 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapLazy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		err := WrapLazy(nil, func() string {
+			called = true
+			return "context"
+		})
+
+		assert.Nil(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("not evaluated until rendered", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		originalErr := errors.New("boom")
+		err := WrapLazy(originalErr, func() string {
+			calls.Add(1)
+			return "request body: {...}"
+		})
+
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, originalErr))
+		assert.Zero(t, calls.Load())
+
+		assert.Contains(t, err.Error(), "TestWrapLazy.func2 (lazy_test.go:")
+		assert.Contains(t, err.Error(), "): request body: {...}: ")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("evaluated once", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		err := WrapLazy(errors.New("boom"), func() string {
+			calls.Add(1)
+			return "state"
+		})
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = fmt.Sprintf("%+v", err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("on a re-wrap", func(t *testing.T) {
+		t.Parallel()
+
+		inner := Wrap(errors.New("boom"))
+		err := WrapLazy(inner, func() string { return "outer context" })
+
+		assert.True(t, strings.HasSuffix(err.Error(), "boom"))
+		assert.Contains(t, err.Error(), "outer context")
+		assert.NotContains(t, inner.Error(), "outer context")
+	})
+
+	t.Run("shown in verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapLazy(errors.New("boom"), func() string { return "loading user 42" })
+
+		assert.Contains(t, fmt.Sprintf("%+v", err), "): loading user 42: boom\n")
+	})
+
+	t.Run("left out of the summary", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapLazy(errors.New("boom"), func() string { return "loading user 42" })
+
+		assert.NotContains(t, Summary(err), "loading user 42")
+	})
+}
@@ -0,0 +1,67 @@
+package errx
+
+import (
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+)
+
+const keyLeak = "errx.leak"
+
+// leakTracking is set once an error was tracked by leak checks, so
+// rendering errors skips looking for their tracker until then.
+var leakTracking atomic.Bool
+
+// leakTracker is shared by the errors of a chain tracked by leak checks.
+// It becomes unreachable along with the last of them, which triggers the
+// check of its leakStatus.
+type leakTracker struct {
+	status *leakStatus
+}
+
+// leakStatus is kept apart from its tracker so the cleanup checking it
+// doesn't keep the tracker alive.
+type leakStatus struct {
+	handled atomic.Bool
+	origin  contextFrame
+}
+
+// LeakCheck makes the Wrapper's errors warn when they are dropped
+// without being handled, see Config.LeakCheck.
+func LeakCheck() Option {
+	return optionFunc(func(c *Config) {
+		c.LeakCheck = true
+	})
+}
+
+// trackLeak returns the attribute tracking the chain starting at origin.
+func trackLeak(origin contextFrame) Attr {
+	leakTracking.Store(true)
+
+	tracker := &leakTracker{status: &leakStatus{origin: origin}}
+	runtime.AddCleanup(tracker, checkLeak, tracker.status)
+	return Attr{Key: keyLeak, Value: tracker}
+}
+
+// checkLeak warns about chains collected without being handled.
+func checkLeak(status *leakStatus) {
+	if status.handled.Load() {
+		return
+	}
+	slog.Warn("errx: error dropped without being handled",
+		"origin", status.origin.site(),
+		"fix", "return, log or report the error, or mark it with errx.Handled",
+	)
+}
+
+// Handled marks err as dealt with for leak checks, when it is dropped on
+// purpose, e.g. after matching it with errors.Is. Formatting and
+// reporting an error mark it too.
+func Handled(err error) {
+	if err == nil || !leakTracking.Load() {
+		return
+	}
+	if tracker, ok := Value[*leakTracker](err, keyLeak); ok {
+		tracker.status.handled.Store(true)
+	}
+}
@@ -0,0 +1,78 @@
+package errx
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of
+// cleanups.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// collect runs the garbage collector until cond holds or a second passed.
+func collect(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+//go:noinline
+func swallow(w *Wrapper) {
+	err := w.Wrap(errors.New("disk full"))
+	_ = w.Wrap(err)
+}
+
+//go:noinline
+func handle(w *Wrapper, mark func(error)) {
+	err := w.Wrap(errors.New("quota exceeded"))
+	mark(err)
+}
+
+func TestLeakCheck(t *testing.T) {
+	var logs syncBuffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	w := NewWrapper(LeakCheck(), ReportTo(ReporterFunc(func(error) {})))
+
+	swallow(w)
+	handle(w, Handled)
+	handle(w, func(err error) { _ = err.Error() })
+	handle(w, w.Report)
+
+	assert.True(t, collect(func() bool {
+		return strings.Contains(logs.String(), "errx.swallow")
+	}), "dropped errors are reported")
+	assert.Regexp(t, `msg="errx: error dropped without being handled" origin="errx\.swallow \(.*/leak_test\.go:\d+\)"`, logs.String())
+	assert.Equal(t, 1, strings.Count(logs.String(), "dropped without being handled"), "handled errors aren't, and re-wraps share their chain's check")
+}
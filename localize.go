@@ -0,0 +1,48 @@
+package errx
+
+// Translator looks up the user-facing message for code in the language
+// tagged lang, e.g. "pt-BR", filling in params. It reports false when it
+// has none. errxi18n provides one loaded from translation files.
+type Translator interface {
+	Translate(code Code, lang string, params map[string]any) (string, bool)
+}
+
+// TranslateWith sets the Translator used by LocalizedMessage for the
+// errors wrapped under the Wrapper's configuration.
+func TranslateWith(t Translator) Option {
+	return optionFunc(func(c *Config) {
+		c.Translator = t
+	})
+}
+
+// LocalizedMessage returns PublicMessage in the language tagged lang:
+// the message the configured Translator has for err's code, filled in
+// with err's attributes, followed by the support ID. Without a
+// translation, it returns PublicMessage(err).
+// The Translator is the one of the configuration err was last wrapped
+// under, see Wrapper.
+func LocalizedMessage(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+
+	c := loadConfig()
+	if extErr := firstExtended(err); extErr != nil {
+		c = extErr.config()
+	}
+
+	code := CodeOf(err)
+	if c.Translator == nil || code == "" {
+		return PublicMessage(err)
+	}
+
+	params := make(map[string]any)
+	for _, attr := range Attrs(err) {
+		params[attr.Key] = attr.Value
+	}
+	msg, ok := c.Translator.Translate(code, lang, params)
+	if !ok {
+		return PublicMessage(err)
+	}
+	return withRef(err, msg)
+}
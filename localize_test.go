@@ -0,0 +1,54 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// translations is a Translator backed by a map of "lang/code" keys.
+type translations map[string]string
+
+func (t translations) Translate(code Code, lang string, params map[string]any) (string, bool) {
+	msg, ok := t[lang+"/"+string(code)]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(msg, params["count"]), true
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(TranslateWith(translations{
+		"pt/billing.invoices.overdue": "%v faturas vencidas",
+	}))
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, LocalizedMessage(nil, "pt"))
+	})
+
+	t.Run("translated with attributes", func(t *testing.T) {
+		t.Parallel()
+
+		err := w.Wrap(With(WithCode(errors.New("overdue"), "billing.invoices.overdue"), "count", 3))
+		assert.Equal(t, "3 faturas vencidas (ref: "+ID(err)+")", LocalizedMessage(err, "pt"))
+	})
+
+	t.Run("falls back to the public message", func(t *testing.T) {
+		t.Parallel()
+
+		coded := w.Wrap(WithPublicMessage(WithCode(errors.New("overdue"), "billing.invoices.overdue"), "invoices overdue"))
+		assert.Equal(t, PublicMessage(coded), LocalizedMessage(coded, "en"))
+
+		uncoded := w.Wrap(errors.New("boom"))
+		assert.Equal(t, PublicMessage(uncoded), LocalizedMessage(uncoded, "pt"))
+
+		untranslated := WithCode(errors.New("overdue"), "billing.invoices.overdue")
+		assert.Equal(t, PublicMessage(untranslated), LocalizedMessage(untranslated, "pt"))
+	})
+}
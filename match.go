@@ -0,0 +1,185 @@
+package errx
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPatterns bounds the number of patterns whose regexps Match caches.
+// Past it, new patterns are compiled on every call, so patterns built
+// from dynamic input can't grow the cache without limit.
+const maxPatterns = 1024
+
+var (
+	// patterns caches the regexps compiled by Match, keyed by pattern.
+	patterns     sync.Map
+	patternCount atomic.Int64
+)
+
+// Match reports whether pattern matches err, for assertions in tests and
+// routing rules such as alerting policies. The pattern is matched against
+// each of:
+//
+//   - the compact rendering returned by Error
+//   - the message of the root cause
+//   - the codes along the chain
+//   - the function names of the frames, full and shortened
+//
+// and err matches if any of them does. Patterns are globs where * matches
+// any run of characters and ? a single one, e.g. "repo.*: *timeout*", and
+// must match a subject in full; or regular expressions when enclosed in
+// slashes, e.g. `/^db\..*refused$/`, which match anywhere unless anchored.
+// Invalid regular expressions match nothing. The first 1024 distinct
+// patterns are compiled once and cached, later ones on every call.
+// It reports false if err is nil.
+func Match(err error, pattern string) bool {
+	if err == nil {
+		return false
+	}
+
+	re := compilePattern(pattern)
+	if re == nil {
+		return false
+	}
+
+	if re.MatchString(err.Error()) || re.MatchString(Root(err).Error()) {
+		return true
+	}
+	var chain visited
+	for e := err; e != nil && chain.add(e); e = errors.Unwrap(e) {
+		extErr, ok := e.(*extendedError)
+		if !ok {
+			continue
+		}
+		for _, attr := range extErr.attrs {
+			if code, ok := attr.Value.(Code); ok && attr.Key == keyCode && re.MatchString(string(code)) {
+				return true
+			}
+		}
+		for _, frame := range extErr.frames() {
+			if frame.funcName == "" {
+				continue
+			}
+			if re.MatchString(frame.funcName) || re.MatchString(shortenFuncName(frame.funcName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// frameMatcher is a target for errors.Is matching errors by their frames.
+type frameMatcher struct {
+	desc  string
+	match func(funcName string) bool
+}
+
+func (m *frameMatcher) Error() string {
+	return m.desc
+}
+
+// FromPackage returns a target for errors.Is matching the errors that
+// have a frame in the package with import path path, or in one of its
+// subpackages. path may leave out a prefix of whole elements, e.g.
+// "internal/db" for "github.com/me/app/internal/db":
+//
+//	var fromDB = errx.FromPackage("internal/db")
+//
+//	if errors.Is(err, fromDB) {
+//	    return errx.Retryable(err)
+//	}
+//
+// Frames scanned from the stack on first wrap count along with wrap sites.
+func FromPackage(path string) error {
+	path = strings.Trim(path, "/")
+	return &frameMatcher{
+		desc: "from package " + path,
+		match: func(funcName string) bool {
+			for pkg := funcPackage(funcName); pkg != ""; pkg = parentPackage(pkg) {
+				if pkg == path || strings.HasSuffix(pkg, "/"+path) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// ThroughFunc returns a target for errors.Is matching the errors that
+// have a frame of the function named name, either in full or shortened,
+// e.g. "repo.(*Store).Save". Like FromPackage, it counts the frames
+// scanned from the stack on first wrap.
+func ThroughFunc(name string) error {
+	patterns := []string{name}
+	return &frameMatcher{
+		desc: "through func " + name,
+		match: func(funcName string) bool {
+			return matchFunc(patterns, funcName)
+		},
+	}
+}
+
+// Is reports whether target is a matcher returned by FromPackage or
+// ThroughFunc that matches one of e's frames.
+func (e *extendedError) Is(target error) bool {
+	m, ok := target.(*frameMatcher)
+	if !ok {
+		return false
+	}
+	for _, frame := range e.frames() {
+		if frame.funcName != "" && m.match(frame.funcName) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePattern returns the regexp matching pattern as described by
+// Match, or nil if it is an invalid regular expression.
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	var expr string
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expr = pattern[1 : len(pattern)-1]
+	} else {
+		expr = globExpr(pattern)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = nil
+	}
+	// reserve a slot first, so concurrent calls can't overshoot the cap
+	if patternCount.Add(1) > maxPatterns {
+		patternCount.Add(-1)
+		return re
+	}
+	if _, loaded := patterns.LoadOrStore(pattern, re); loaded {
+		patternCount.Add(-1)
+	}
+	return re
+}
+
+// globExpr translates a glob into an anchored regular expression.
+func globExpr(glob string) string {
+	var b strings.Builder
+	b.WriteString(`^(?s:`)
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`)$`)
+	return b.String()
+}
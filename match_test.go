@@ -0,0 +1,87 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findUser() error {
+	return Wrap(errors.New("connection timeout"))
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	err := WithCode(findUser(), "users.db_unavailable")
+	err = Wrapf(fmt.Errorf("loading profile: %w", err), "handling request")
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{pattern: "*errx.findUser (match_test.go:*): connection timeout", want: true},
+		{pattern: "*timeout", want: true},
+		{pattern: "connection timeout", want: true},
+		{pattern: "connection ?imeout", want: true},
+		{pattern: "timeout", want: false},
+		{pattern: "users.*", want: true},
+		{pattern: "users.db_unavailable", want: true},
+		{pattern: "billing.*", want: false},
+		{pattern: "errx.findUser", want: true},
+		{pattern: "github.com/alesr/errx.find*", want: true},
+		{pattern: "errx.find", want: false},
+		{pattern: "/time?out$/", want: true},
+		{pattern: "/^timeout/", want: false},
+		{pattern: "/[/", want: false},
+		{pattern: "*", want: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Match(err, tt.pattern), "pattern %q", tt.pattern)
+	}
+
+	assert.False(t, Match(nil, "*"))
+	assert.True(t, Match(errors.New("plain\nmultiline"), "plain*"))
+
+	t.Run("bounded cache", func(t *testing.T) {
+		t.Parallel()
+
+		plain := errors.New("request 1500 failed")
+		for i := range maxPatterns + 500 {
+			Match(plain, "request "+strconv.Itoa(i)+" *")
+		}
+		assert.LessOrEqual(t, patternCount.Load(), int64(maxPatterns))
+		assert.True(t, Match(plain, "request 1500 *"), "patterns past the cap still match")
+	})
+}
+
+func TestFrameMatchers(t *testing.T) {
+	t.Parallel()
+
+	err := Wrapf(fmt.Errorf("loading profile: %w", findUser()), "handling request")
+
+	tests := []struct {
+		target error
+		want   bool
+	}{
+		{target: FromPackage("github.com/alesr/errx"), want: true},
+		{target: FromPackage("alesr/errx"), want: true},
+		{target: FromPackage("errx"), want: true},
+		{target: FromPackage("rx"), want: false},
+		{target: FromPackage("github.com/alesr"), want: true},
+		{target: FromPackage("internal/db"), want: false},
+		{target: ThroughFunc("errx.findUser"), want: true},
+		{target: ThroughFunc("github.com/alesr/errx.findUser"), want: true},
+		{target: ThroughFunc("findUser"), want: false},
+		{target: ThroughFunc("repo.Save"), want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, errors.Is(err, tt.target), "target %q", tt.target)
+	}
+
+	assert.False(t, errors.Is(errors.New("plain"), FromPackage("errx")))
+	assert.False(t, errors.Is(Wrap(errors.New("plain")), errors.New("plain")))
+}
@@ -0,0 +1,47 @@
+package errx
+
+import "runtime/metrics"
+
+// Keys of the attributes attached when Config.MemStats is set.
+const (
+	// AttrHeapAlloc holds the bytes of allocated heap objects, as
+	// runtime.MemStats.HeapAlloc, as a uint64.
+	AttrHeapAlloc = "mem.heap_alloc"
+	// AttrNumGC holds the number of completed GC cycles, as
+	// runtime.MemStats.NumGC, as a uint64.
+	AttrNumGC = "mem.num_gc"
+)
+
+// memSamples lists the runtime metrics behind the memory attributes,
+// in the order of the attribute keys.
+var memSamples = [...]struct{ attr, metric string }{
+	{AttrHeapAlloc, "/memory/classes/heap/objects:bytes"},
+	{AttrNumGC, "/gc/cycles/total:gc-cycles"},
+}
+
+// CaptureMemStats attaches memory statistics to the errors the Wrapper
+// wraps first, see Config.MemStats.
+func CaptureMemStats() Option {
+	return optionFunc(func(c *Config) {
+		c.MemStats = true
+	})
+}
+
+// memAttrs reads the memory statistics attached on first wrap. Unlike
+// runtime.ReadMemStats, reading them doesn't stop the world.
+func memAttrs() []Attr {
+	var samples [len(memSamples)]metrics.Sample
+	for i, s := range memSamples {
+		samples[i].Name = s.metric
+	}
+	metrics.Read(samples[:])
+
+	attrs := make([]Attr, 0, len(samples))
+	for i, sample := range samples {
+		if sample.Value.Kind() != metrics.KindUint64 {
+			continue
+		}
+		attrs = append(attrs, Attr{Key: memSamples[i].attr, Value: sample.Value.Uint64()})
+	}
+	return attrs
+}
@@ -0,0 +1,43 @@
+package errx
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("attached on first wrap", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(CaptureMemStats())
+		runtime.GC()
+		err := w.Wrap(w.Wrap(errors.New("out of buffers")))
+
+		heap, ok := Value[uint64](err, AttrHeapAlloc)
+		require.True(t, ok)
+		assert.Positive(t, heap)
+
+		numGC, ok := Value[uint64](err, AttrNumGC)
+		require.True(t, ok)
+		assert.Positive(t, numGC)
+
+		var keys []string
+		for _, attr := range err.(*extendedError).attrs {
+			keys = append(keys, attr.Key)
+		}
+		assert.Equal(t, []string{keyID, AttrHeapAlloc, AttrNumGC}, keys, "re-wraps don't attach them again")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := Value[uint64](NewWrapper().Wrap(errors.New("out of buffers")), AttrHeapAlloc)
+		assert.False(t, ok)
+	})
+}
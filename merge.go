@@ -0,0 +1,130 @@
+package errx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Occurrence is a group of errors passed to Merge sharing a fingerprint.
+type Occurrence struct {
+	// Err is the first error of the group, standing for the others.
+	Err error
+	// Count is the number of errors in the group.
+	Count int
+	// Sources lists the positions of the errors of the group among the
+	// errors passed to Merge, e.g. the shards that failed this way.
+	Sources []int
+}
+
+// mergedError is the aggregate returned by Merge.
+type mergedError struct {
+	occurrences []Occurrence
+	// total is the number of errors passed to Merge, nils included
+	total int
+}
+
+// Merge aggregates the errors of a fan-in, such as the results of calls
+// to every shard, deduplicating the ones sharing a Fingerprint: each
+// group keeps its first error and counts the others, so forty shards
+// failing the same way render as one chain, e.g.
+// "37 of 40 failed: db.Query (db.go:42): connection refused". Groups are
+// listed one per line, ordered like Join orders errors by the capture
+// time of their first error, then by its position; see Occurrences for
+// their counts and sources. errors.Is and errors.As
+// match the first error of every group.
+// Returns nil if every error is nil, and the error itself when it is the
+// only one.
+func Merge(errs ...error) error {
+	var (
+		occurrences []Occurrence
+		groups      map[string]int
+	)
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		key := Fingerprint(err)
+		if g, ok := groups[key]; ok {
+			occurrences[g].Count++
+			occurrences[g].Sources = append(occurrences[g].Sources, i)
+			continue
+		}
+		if groups == nil {
+			groups = make(map[string]int)
+		}
+		groups[key] = len(occurrences)
+		occurrences = append(occurrences, Occurrence{Err: err, Count: 1, Sources: []int{i}})
+	}
+
+	switch {
+	case len(occurrences) == 0:
+		return nil
+	case len(occurrences) == 1 && occurrences[0].Count == 1:
+		return occurrences[0].Err
+	}
+	sortByCapture(occurrences, func(o Occurrence) error { return o.Err })
+	return &mergedError{occurrences: occurrences, total: len(errs)}
+}
+
+// Occurrences returns the groups of the first aggregate returned by Merge
+// found in err's chain, or nil when there is none.
+func Occurrences(err error) []Occurrence {
+	var merged *mergedError
+	Walk(err, func(err error) bool {
+		merged, _ = err.(*mergedError)
+		return merged == nil
+	})
+	if merged == nil {
+		return nil
+	}
+	return merged.occurrences
+}
+
+func (e *mergedError) Error() string {
+	var b []byte
+	for i, o := range e.occurrences {
+		if i > 0 {
+			b = append(b, '\n')
+		}
+		b = e.appendHeader(b, o)
+		b = append(b, ": "...)
+		b = append(b, o.Err.Error()...)
+	}
+	return string(b)
+}
+
+// appendHeader appends "N of M failed" for o to dst.
+func (e *mergedError) appendHeader(dst []byte, o Occurrence) []byte {
+	dst = strconv.AppendInt(dst, int64(o.Count), 10)
+	dst = append(dst, " of "...)
+	dst = strconv.AppendInt(dst, int64(e.total), 10)
+	return append(dst, " failed"...)
+}
+
+// Unwrap returns the first error of every group.
+func (e *mergedError) Unwrap() []error {
+	errs := make([]error, len(e.occurrences))
+	for i, o := range e.occurrences {
+		errs[i] = o.Err
+	}
+	return errs
+}
+
+// Format renders each group with %+v as its header followed by the
+// verbose output of its first error, and like Error otherwise.
+func (e *mergedError) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		fmt.Fprint(s, e.Error())
+		return
+	}
+
+	for _, o := range e.occurrences {
+		b := append(e.appendHeader(nil, o), ":\n"...)
+		b = fmt.Appendf(b, "%+v", o.Err)
+		if b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+		_, _ = s.Write(b)
+	}
+}
@@ -0,0 +1,67 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queryShard(cause error) error {
+	return Wrap(cause)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("groups errors by fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		refused := errors.New("connection refused")
+		errs := make([]error, 40)
+		for i := range 37 {
+			errs[i] = queryShard(refused)
+		}
+		errs[38] = errors.New("disk full")
+
+		err := Merge(errs...)
+		require.Error(t, err)
+
+		lines := strings.Split(err.Error(), "\n")
+		require.Len(t, lines, 2)
+		assert.Regexp(t, `^37 of 40 failed: errx\.queryShard \(merge_test\.go:\d+\): .*connection refused$`, lines[0])
+		assert.Equal(t, "1 of 40 failed: disk full", lines[1])
+
+		occurrences := Occurrences(fmt.Errorf("scatter: %w", err))
+		require.Len(t, occurrences, 2)
+		assert.Same(t, errs[0], occurrences[0].Err)
+		assert.Equal(t, 37, occurrences[0].Count)
+		assert.Len(t, occurrences[0].Sources, 37)
+		assert.Equal(t, []int{38}, occurrences[1].Sources)
+
+		assert.ErrorIs(t, err, refused)
+	})
+
+	t.Run("verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		err := Merge(queryShard(errors.New("timeout")), queryShard(errors.New("timeout")), errors.New("canceled"))
+
+		out := fmt.Sprintf("%+v", err)
+		assert.Regexp(t, `^2 of 3 failed:\n\[0\] errx\.queryShard`, out)
+		assert.True(t, strings.HasSuffix(out, "1 of 3 failed:\ncanceled\n"), out)
+	})
+
+	t.Run("single and no errors", func(t *testing.T) {
+		t.Parallel()
+
+		only := errors.New("boom")
+		assert.Same(t, only, Merge(nil, only, nil))
+		assert.Nil(t, Merge(nil, nil))
+		assert.Nil(t, Merge())
+		assert.Nil(t, Occurrences(only))
+	})
+}
@@ -0,0 +1,101 @@
+package errx
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Namespace is a dotted prefix grouping the codes of one team, service
+// or package, e.g. "billing" or "billing.invoice".
+type Namespace string
+
+var (
+	namespacesMu sync.Mutex
+	// namespaces maps registered namespaces to the package that
+	// registered them
+	namespaces = make(map[Namespace]string)
+)
+
+// RegisterNamespace claims the namespace name for the calling package
+// and returns it, so codes are declared under it:
+//
+//	var billing = errx.RegisterNamespace("billing")
+//	var ErrInvoiceNotFound = billing.Code("invoice.not_found")
+//
+// It panics if another package registered the same namespace, which
+// catches teams of a monolith picking the same one at startup.
+// Registering it again from the same package returns it.
+// It is meant to be called from package-level variable declarations.
+func RegisterNamespace(name string) Namespace {
+	ns := Namespace(strings.Trim(name, "."))
+	if ns == "" {
+		panic("errx: empty namespace")
+	}
+
+	var pkg string
+	if funcName, _, _, ok := caller(1); ok {
+		pkg = funcPackage(funcName)
+	}
+
+	namespacesMu.Lock()
+	defer namespacesMu.Unlock()
+
+	if owner, ok := namespaces[ns]; ok && owner != pkg {
+		panic("errx: namespace " + string(ns) + " already registered by " + owner)
+	}
+	namespaces[ns] = pkg
+	return ns
+}
+
+// Code returns the code named name within ns, e.g. "billing.invoice.not_found"
+// for name "invoice.not_found" in namespace "billing".
+func (ns Namespace) Code(name string) Code {
+	if ns == "" {
+		return Code(name)
+	}
+	return Code(string(ns) + "." + name)
+}
+
+// Contains reports whether code lies within ns, at any depth.
+func (ns Namespace) Contains(code Code) bool {
+	return ns != "" && strings.HasPrefix(string(code), string(ns)+".")
+}
+
+// Namespace returns the namespace c lies directly within, e.g.
+// "billing.invoice" for "billing.invoice.not_found", or an empty
+// Namespace for codes without dots.
+func (c Code) Namespace() Namespace {
+	i := strings.LastIndexByte(string(c), '.')
+	if i < 0 {
+		return ""
+	}
+	return Namespace(c[:i])
+}
+
+// IsCode reports whether any error along err's chain carries code, or a
+// code below it: "billing.invoice" matches errors coded
+// "billing.invoice.not_found". Unlike CodeOf, it also looks past the
+// outermost code, the way errors.Is looks past the outermost error.
+func IsCode(err error, code Code) bool {
+	if code == "" {
+		return false
+	}
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+		for _, attr := range extErr.attrs {
+			c, ok := attr.Value.(Code)
+			if !ok || attr.Key != keyCode {
+				continue
+			}
+			if c == code || Namespace(code).Contains(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
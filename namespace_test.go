@@ -0,0 +1,74 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterNamespace(t *testing.T) {
+	t.Parallel()
+
+	ns := RegisterNamespace("test_register_namespace.")
+	assert.Equal(t, Namespace("test_register_namespace"), ns)
+
+	// the same package may register it again
+	assert.Equal(t, ns, RegisterNamespace("test_register_namespace"))
+
+	// another package may not
+	namespacesMu.Lock()
+	namespaces["test_register_namespace_taken"] = "github.com/acme/other"
+	namespacesMu.Unlock()
+	assert.PanicsWithValue(t, "errx: namespace test_register_namespace_taken already registered by github.com/acme/other", func() {
+		RegisterNamespace("test_register_namespace_taken")
+	})
+
+	assert.Panics(t, func() { RegisterNamespace("") })
+}
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+
+	const billing Namespace = "billing"
+
+	code := billing.Code("invoice.not_found")
+	assert.Equal(t, Code("billing.invoice.not_found"), code)
+	assert.Equal(t, Namespace("billing.invoice"), code.Namespace())
+	assert.Empty(t, Code("not_found").Namespace())
+	assert.Equal(t, Code("not_found"), Namespace("").Code("not_found"))
+
+	assert.True(t, billing.Contains(code))
+	assert.True(t, Namespace("billing.invoice").Contains(code))
+	assert.False(t, Namespace("bill").Contains(code))
+	assert.False(t, Namespace("billing.invoice.not_found").Contains(code))
+	assert.False(t, Namespace("").Contains(code))
+}
+
+func TestIsCode(t *testing.T) {
+	t.Parallel()
+
+	err := WithCode(errors.New("invoice not found"), "billing.invoice.not_found")
+	err = WithCode(fmt.Errorf("charging: %w", err), "payments.charge_failed")
+
+	tests := []struct {
+		code Code
+		want bool
+	}{
+		{code: "payments.charge_failed", want: true},
+		{code: "payments", want: true},
+		{code: "billing.invoice.not_found", want: true},
+		{code: "billing.invoice", want: true},
+		{code: "billing", want: true},
+		{code: "bill", want: false},
+		{code: "billing.invoice.not_found.extra", want: false},
+		{code: "", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsCode(err, tt.code), "code %q", tt.code)
+	}
+
+	assert.False(t, IsCode(nil, "billing"))
+	assert.False(t, IsCode(errors.New("plain"), "billing"))
+}
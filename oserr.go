@@ -0,0 +1,190 @@
+package errx
+
+import (
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Keys of the attributes attached to errors caused by failed file and
+// system operations.
+const (
+	// AttrOSOp holds the failed operation, e.g. "open", as a string.
+	AttrOSOp = "os.op"
+	// AttrOSPath holds the file the operation was applied to.
+	AttrOSPath = "os.path"
+	// AttrOSNewPath holds the destination of failed links and renames.
+	AttrOSNewPath = "os.new_path"
+	// AttrOSSyscall holds the failed system call, e.g. "fsync".
+	AttrOSSyscall = "os.syscall"
+	// AttrOSErrno holds the symbolic name of the error number returned by
+	// the system, e.g. "ENOENT", or the number itself for uncommon ones.
+	AttrOSErrno = "os.errno"
+)
+
+// FromErrno returns an error describing errno returned by the system or C
+// function op, wrapped at the caller. It carries op and the symbolic name
+// of errno as the AttrOSSyscall and AttrOSErrno attributes, and the kind
+// matching errno, so context survives the cgo boundary:
+//
+//	if _, errno := C.mdb_env_open(env, path, 0, 0644); errno != nil {
+//		return errx.FromErrno("mdb_env_open", errno.(syscall.Errno))
+//	}
+//
+// Returns nil if errno is 0, and the error unwrapped if it matches
+// Config.Ignore.
+func FromErrno(op string, errno syscall.Errno) error {
+	if errno == 0 {
+		return nil
+	}
+
+	err := os.NewSyscallError(op, errno)
+	if loadConfig().ignored(err) {
+		return err
+	}
+	return wrap(nil, err, 2, contextFrame{})
+}
+
+// Enricher extracts details from the cause of an error when it's first
+// wrapped, such as the path of a failed file operation, and optionally
+// classifies it. It returns nothing for causes it doesn't know about.
+type Enricher func(cause error) (attrs []Attr, kind Kind)
+
+var (
+	enrichersMu sync.Mutex
+	enrichers   atomic.Pointer[[]Enricher]
+)
+
+// RegisterEnricher adds e to the enrichers consulted on first wrap, after
+// the built-in one for *fs.PathError, *os.LinkError, *os.SyscallError and
+// syscall.Errno. Attributes from every enricher are attached, and the
+// first kind returned is set, taking precedence over DefaultKind but not
+// over kinds set later with WithKind.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterEnricher(e Enricher) {
+	enrichersMu.Lock()
+	defer enrichersMu.Unlock()
+
+	var registered []Enricher
+	if p := enrichers.Load(); p != nil {
+		registered = *p
+	}
+	registered = append(slices.Clip(registered), e)
+	enrichers.Store(&registered)
+}
+
+// enrich returns the attributes the enrichers extract from cause,
+// followed by the kind they classify it as, if any.
+func enrich(cause error) []Attr {
+	attrs, kind := osDetails(cause)
+	if p := enrichers.Load(); p != nil {
+		for _, e := range *p {
+			more, k := e(cause)
+			attrs = append(attrs, more...)
+			if kind == "" {
+				kind = k
+			}
+		}
+	}
+	if kind != "" {
+		attrs = append(attrs, Attr{Key: keyKind, Value: kind})
+	}
+	return attrs
+}
+
+// osKinds lists the kinds of the errors matching each target, in order
+// of precedence.
+var osKinds = [...]struct {
+	target error
+	kind   Kind
+}{
+	{fs.ErrNotExist, KindNotFound},
+	{fs.ErrPermission, KindPermissionDenied},
+	{fs.ErrExist, KindConflict},
+	{os.ErrDeadlineExceeded, KindTimeout},
+}
+
+// osDetails is the built-in Enricher for file and system errors. Errors
+// matching fs.ErrNotExist, fs.ErrPermission, fs.ErrExist and
+// os.ErrDeadlineExceeded are classified as KindNotFound,
+// KindPermissionDenied, KindConflict and KindTimeout. It runs on every
+// first wrap, so it walks cause's tree once, matching the targets along
+// the way as errors.Is would, rather than once per target.
+func osDetails(cause error) ([]Attr, Kind) {
+	var (
+		attrs   []Attr
+		matched [len(osKinds)]bool
+	)
+	Walk(cause, func(err error) bool {
+		x, hasIs := err.(interface{ Is(error) bool })
+		for i, k := range osKinds {
+			if !matched[i] && (err == k.target || hasIs && x.Is(k.target)) {
+				matched[i] = true
+			}
+		}
+
+		switch err := err.(type) {
+		case *fs.PathError:
+			attrs = append(attrs, Attr{Key: AttrOSOp, Value: err.Op}, Attr{Key: AttrOSPath, Value: err.Path})
+		case *os.LinkError:
+			attrs = append(attrs,
+				Attr{Key: AttrOSOp, Value: err.Op},
+				Attr{Key: AttrOSPath, Value: err.Old},
+				Attr{Key: AttrOSNewPath, Value: err.New},
+			)
+		case *os.SyscallError:
+			attrs = append(attrs, Attr{Key: AttrOSSyscall, Value: err.Syscall})
+		case syscall.Errno:
+			attrs = append(attrs, Attr{Key: AttrOSErrno, Value: errnoName(err)})
+		}
+		return true
+	})
+
+	for i, k := range osKinds {
+		if matched[i] {
+			return attrs, k.kind
+		}
+	}
+	return attrs, ""
+}
+
+// errnoNames holds the symbolic names of common error numbers.
+var errnoNames = map[syscall.Errno]string{
+	syscall.EACCES:       "EACCES",
+	syscall.EADDRINUSE:   "EADDRINUSE",
+	syscall.EAGAIN:       "EAGAIN",
+	syscall.EBADF:        "EBADF",
+	syscall.EBUSY:        "EBUSY",
+	syscall.ECONNREFUSED: "ECONNREFUSED",
+	syscall.ECONNRESET:   "ECONNRESET",
+	syscall.EEXIST:       "EEXIST",
+	syscall.EHOSTUNREACH: "EHOSTUNREACH",
+	syscall.EINTR:        "EINTR",
+	syscall.EINVAL:       "EINVAL",
+	syscall.EIO:          "EIO",
+	syscall.EISDIR:       "EISDIR",
+	syscall.EMFILE:       "EMFILE",
+	syscall.ENETUNREACH:  "ENETUNREACH",
+	syscall.ENOENT:       "ENOENT",
+	syscall.ENOMEM:       "ENOMEM",
+	syscall.ENOSPC:       "ENOSPC",
+	syscall.ENOTDIR:      "ENOTDIR",
+	syscall.ENOTEMPTY:    "ENOTEMPTY",
+	syscall.EPERM:        "EPERM",
+	syscall.EPIPE:        "EPIPE",
+	syscall.EROFS:        "EROFS",
+	syscall.ETIMEDOUT:    "ETIMEDOUT",
+}
+
+// errnoName returns the symbolic name of errno, or its number when it's
+// not a common one.
+func errnoName(errno syscall.Errno) string {
+	if name, ok := errnoNames[errno]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(errno), 10)
+}
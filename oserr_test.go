@@ -0,0 +1,153 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("path errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, cause := os.Open(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, cause)
+		err := Wrap(cause)
+
+		op, _ := Value[string](err, AttrOSOp)
+		path, _ := Value[string](err, AttrOSPath)
+		assert.Equal(t, "open", op)
+		assert.Equal(t, "missing.yaml", filepath.Base(path))
+		assert.Equal(t, KindNotFound, KindOf(err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nos.op: open\n")
+	})
+
+	t.Run("link errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(&os.LinkError{Op: "rename", Old: "/a", New: "/b", Err: syscall.EACCES})
+
+		assert.Equal(t, []Attr{
+			{Key: AttrOSOp, Value: "rename"},
+			{Key: AttrOSPath, Value: "/a"},
+			{Key: AttrOSNewPath, Value: "/b"},
+			{Key: AttrOSErrno, Value: "EACCES"},
+		}, Attrs(err))
+		assert.Equal(t, KindPermissionDenied, KindOf(err))
+	})
+
+	t.Run("syscall errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(fmt.Errorf("flushing: %w", os.NewSyscallError("fsync", syscall.Errno(1234))))
+
+		assert.Equal(t, []Attr{
+			{Key: AttrOSSyscall, Value: "fsync"},
+			{Key: AttrOSErrno, Value: "1234"},
+		}, Attrs(err))
+		assert.Empty(t, KindOf(err))
+	})
+
+	t.Run("existing files", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(&fs.PathError{Op: "mkdir", Path: "/tmp", Err: fs.ErrExist})
+		assert.Equal(t, KindConflict, KindOf(err))
+	})
+
+	t.Run("kinds set with WithKind win", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithKind(&fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist}, KindInternal)
+		assert.Equal(t, KindInternal, KindOf(err))
+	})
+
+	t.Run("enriched kinds beat default ones", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(DefaultKind(KindUnavailable))
+		err := w.Wrap(&fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist})
+		assert.Equal(t, KindNotFound, KindOf(err))
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("boom"))
+		assert.Empty(t, Attrs(err))
+		assert.Empty(t, KindOf(err))
+	})
+}
+
+type quotaError struct {
+	tenant string
+}
+
+func (e *quotaError) Error() string {
+	return "quota exceeded for " + e.tenant
+}
+
+func TestRegisterEnricher(t *testing.T) {
+	RegisterEnricher(func(cause error) ([]Attr, Kind) {
+		var quotaErr *quotaError
+		if !errors.As(cause, &quotaErr) {
+			return nil, ""
+		}
+		return []Attr{{Key: "quota.tenant", Value: quotaErr.tenant}}, KindUnavailable
+	})
+	t.Cleanup(func() { enrichers.Store(nil) })
+
+	err := Wrap(fmt.Errorf("uploading: %w", &quotaError{tenant: "acme"}))
+
+	assert.Equal(t, []Attr{{Key: "quota.tenant", Value: "acme"}}, Attrs(err))
+	assert.Equal(t, KindUnavailable, KindOf(err))
+
+	t.Run("attached once per chain", func(t *testing.T) {
+		err := Wrap(fmt.Errorf("retrying: %w", err))
+
+		var count int
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			if extErr, ok := e.(*extendedError); ok {
+				for _, attr := range extErr.attrs {
+					if attr.Key == "quota.tenant" {
+						count++
+					}
+				}
+			}
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestFromErrno(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero errno", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, FromErrno("mdb_env_open", 0))
+	})
+
+	t.Run("describes the call", func(t *testing.T) {
+		t.Parallel()
+
+		err := FromErrno("mdb_env_open", syscall.ENOENT)
+
+		assert.Regexp(t, `^errx\.TestFromErrno\.\S+ \(oserr_test\.go:\d+\): .*mdb_env_open: `, err.Error())
+		assert.Equal(t, []Attr{
+			{Key: AttrOSSyscall, Value: "mdb_env_open"},
+			{Key: AttrOSErrno, Value: "ENOENT"},
+		}, Attrs(err))
+		assert.Equal(t, KindNotFound, KindOf(err))
+		assert.ErrorIs(t, err, syscall.ENOENT)
+	})
+}
@@ -0,0 +1,68 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxPanicDepth bounds the program counters captured from a panicking
+// stack, runtime frames included.
+const maxPanicDepth = 64
+
+// FromPanic turns v, a value returned by recover, into an error holding
+// the frames of the panicking stack, from the panic site up, and v as
+// cause, "panic: " followed by v, matched by errors.Is when v is an error:
+//
+//	defer func() {
+//	    if v := recover(); v != nil {
+//	        err = errx.FromPanic(v)
+//	    }
+//	}()
+//
+// It must be called by the deferred function that recovered v, while the
+// panicking stack is still there.
+// Returns nil if v is nil.
+func FromPanic(v any) error {
+	if v == nil {
+		return nil
+	}
+
+	cause, ok := v.(error)
+	if !ok {
+		cause = errors.New(fmt.Sprint(v))
+	}
+	err := fmt.Errorf("panic: %w", cause)
+
+	var buf [maxPanicDepth]uintptr
+	// skip runtime.Callers and FromPanic
+	pcs := buf[:runtime.Callers(2, buf[:])]
+	return WrapPCs(err, panicSite(pcs))
+}
+
+// panicSite returns the program counters of pcs from the call that
+// panicked up, skipping runtime.gopanic and the runtime frames raising
+// panics such as nil dereferences. pcs is returned as is when it holds
+// no runtime.gopanic frame.
+func panicSite(pcs []uintptr) []uintptr {
+	for i, pc := range pcs {
+		if pcFuncName(pc) != "runtime.gopanic" {
+			continue
+		}
+		rest := pcs[i+1:]
+		for len(rest) > 0 && strings.HasPrefix(pcFuncName(rest[0]), "runtime.") {
+			rest = rest[1:]
+		}
+		return rest
+	}
+	return pcs
+}
+
+// pcFuncName returns the name of the function holding the return address pc.
+func pcFuncName(pc uintptr) string {
+	if fn := runtime.FuncForPC(pc - 1); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dereference(p *int) int {
+	return *p
+}
+
+func recovered(fn func()) (err error) {
+	defer func() {
+		err = FromPanic(recover())
+	}()
+	fn()
+	return nil
+}
+
+func TestFromPanic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, recovered(func() {}))
+	})
+
+	t.Run("error values", func(t *testing.T) {
+		t.Parallel()
+
+		errBroken := errors.New("broken invariant")
+		err := recovered(func() { panic(errBroken) })
+
+		assert.ErrorIs(t, err, errBroken)
+		assert.Contains(t, err.Error(), ": panic: broken invariant")
+		assert.Equal(t, "github.com/alesr/errx.TestFromPanic.func2.1", Frames(err)[0].Function)
+	})
+
+	t.Run("runtime panics", func(t *testing.T) {
+		t.Parallel()
+
+		err := recovered(func() { dereference(nil) })
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "panic: runtime error: invalid memory address")
+		assert.Equal(t, "github.com/alesr/errx.dereference", Frames(err)[0].Function)
+	})
+}
@@ -0,0 +1,30 @@
+package errx
+
+// WrapPCs wraps err like Wrap, with the frames of pcs instead of the ones
+// of the calling stack: program counters already captured with
+// runtime.Callers, by an assertion helper or a panic handler for
+// instance, so the frames match what that code saw. pcs goes from the
+// innermost call up, the first one becoming the wrap site; trimming and
+// Config.Depth apply as on first wrap. If err already is an errx error,
+// only the first one is added, as its new wrap site. When none of pcs can
+// be resolved, err is wrapped like by Wrap.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func WrapPCs(err error, pcs []uintptr) error {
+	c := loadConfig()
+	if err == nil || c.ignored(err) {
+		return err
+	}
+
+	sites := sitesAt(pcs)
+	if len(sites) == 0 {
+		return wrap(nil, err, 2, contextFrame{})
+	}
+	if _, ok := err.(*extendedError); ok {
+		return wrap(nil, err, 2, contextFrame{funcName: sites[0].funcName, file: sites[0].file, line: sites[0].line})
+	}
+
+	c = packageConfig(c, sites[0].funcName)
+	sites = sites[:min(len(sites), c.depth())]
+	frames := c.appendSites(make([]contextFrame, 0, len(sites)), sites, c.now())
+	return newChain(c, nil, err, frames, false, nil)
+}
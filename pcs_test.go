@@ -0,0 +1,67 @@
+package errx
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturePCs records the stack of its caller, as an assertion helper
+// would.
+func capturePCs() []uintptr {
+	pcs := make([]uintptr, 32)
+	return pcs[:runtime.Callers(2, pcs)]
+}
+
+func TestWrapPCs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WrapPCs(nil, capturePCs()))
+	})
+
+	t.Run("uses the given frames", func(t *testing.T) {
+		t.Parallel()
+
+		pcs := capturePCs()
+		err := helperWrapPCs(errors.New("boom"), pcs)
+
+		frames := Frames(err)
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "github.com/alesr/errx.TestWrapPCs.func2", frames[0].Function)
+		assert.True(t, frames[0].WrapSite)
+		for _, frame := range frames {
+			assert.NotEqual(t, "github.com/alesr/errx.helperWrapPCs", frame.Function, "the caller's stack isn't captured")
+		}
+		assert.NotEmpty(t, ID(err))
+	})
+
+	t.Run("adds a wrap site to errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := Wrap(errors.New("boom"))
+		err := helperWrapPCs(wrapped, capturePCs())
+
+		frames := Frames(err)
+		assert.Len(t, frames, len(Frames(wrapped))+1)
+		assert.Equal(t, "github.com/alesr/errx.TestWrapPCs.func3", frames[0].Function)
+		assert.Equal(t, ID(wrapped), ID(err))
+	})
+
+	t.Run("falls back to the caller", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapPCs(errors.New("boom"), nil)
+		assert.Equal(t, "github.com/alesr/errx.TestWrapPCs.func4", Frames(err)[0].Function)
+	})
+}
+
+// helperWrapPCs calls WrapPCs away from the frames it is given.
+func helperWrapPCs(err error, pcs []uintptr) error {
+	return WrapPCs(err, pcs)
+}
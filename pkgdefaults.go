@@ -0,0 +1,88 @@
+package errx
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	packageDefaultsMu sync.RWMutex
+	packageDefaults   = make(map[string][]Option)
+	// hasPackageDefaults skips the lookup until something is registered
+	hasPackageDefaults atomic.Bool
+)
+
+// RegisterPackageDefaults applies opts to the errors first wrapped in the
+// package with import path pkgPath or in its subpackages, on top of the
+// configuration in effect, e.g. to give every error from the database
+// layer a component attribute, a deeper capture or a default kind.
+// When several registered paths match, the longest one wins.
+// Registering a path again replaces its options.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterPackageDefaults(pkgPath string, opts ...Option) {
+	packageDefaultsMu.Lock()
+	defer packageDefaultsMu.Unlock()
+
+	packageDefaults[strings.TrimSuffix(pkgPath, "/")] = opts
+	hasPackageDefaults.Store(true)
+}
+
+// DefaultKind sets the kind of errors when they are first wrapped.
+// A kind set later with WithKind takes precedence.
+func DefaultKind(kind Kind) Option {
+	return DefaultAttrs(Attr{Key: keyKind, Value: kind})
+}
+
+// packageConfig returns c adjusted by the defaults registered for the
+// package of the function named funcName, or c itself if there are none.
+func packageConfig(c *Config, funcName string) *Config {
+	if !hasPackageDefaults.Load() || funcName == "" {
+		return c
+	}
+
+	packageDefaultsMu.RLock()
+	defer packageDefaultsMu.RUnlock()
+
+	var (
+		opts  []Option
+		found bool
+	)
+	for pkg := funcPackage(funcName); pkg != ""; pkg = parentPackage(pkg) {
+		if opts, found = packageDefaults[pkg]; found {
+			break
+		}
+	}
+	if !found {
+		return c
+	}
+
+	adjusted := c.clone()
+	for _, opt := range opts {
+		opt.apply(&adjusted)
+	}
+	return &adjusted
+}
+
+// funcPackage returns the import path of the package declaring the
+// function named full, e.g. "github.com/me/app/db" for
+// "github.com/me/app/db.(*Store).Query". The runtime escapes dots in the
+// last path element as %2e, they are restored.
+func funcPackage(full string) string {
+	slash := strings.LastIndexByte(full, '/')
+	dot := strings.IndexByte(full[slash+1:], '.')
+	if dot >= 0 {
+		full = full[:slash+1+dot]
+	}
+	return strings.ReplaceAll(full, "%2e", ".")
+}
+
+// parentPackage returns the import path one level above pkg, or an empty
+// string at the top.
+func parentPackage(pkg string) string {
+	slash := strings.LastIndexByte(pkg, '/')
+	if slash < 0 {
+		return ""
+	}
+	return pkg[:slash]
+}
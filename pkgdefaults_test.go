@@ -0,0 +1,102 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageConfig(t *testing.T) {
+	t.Parallel()
+
+	// registrations are global, so the paths are unique to this test
+	RegisterPackageDefaults("example.com/pkgdefaults/internal/db",
+		Depth(20),
+		DefaultAttrs(Attr{Key: "component", Value: "db"}),
+	)
+	RegisterPackageDefaults("example.com/pkgdefaults/internal/db/migrations/",
+		DefaultKind(KindInternal),
+	)
+
+	base := &Config{
+		Deterministic: true,
+		DefaultAttrs:  []Attr{{Key: "service", Value: "billing"}},
+	}
+
+	t.Run("registered package", func(t *testing.T) {
+		t.Parallel()
+
+		c := packageConfig(base, "example.com/pkgdefaults/internal/db.(*Store).Query")
+
+		assert.Equal(t, 20, c.Depth)
+		assert.True(t, c.Deterministic)
+		assert.Equal(t, []Attr{
+			{Key: "service", Value: "billing"},
+			{Key: "component", Value: "db"},
+		}, c.DefaultAttrs)
+
+		// the base configuration is untouched
+		assert.Len(t, base.DefaultAttrs, 1)
+	})
+
+	t.Run("subpackage", func(t *testing.T) {
+		t.Parallel()
+
+		c := packageConfig(base, "example.com/pkgdefaults/internal/db/pool.Acquire")
+		assert.Equal(t, 20, c.Depth)
+	})
+
+	t.Run("longest path wins", func(t *testing.T) {
+		t.Parallel()
+
+		c := packageConfig(base, "example.com/pkgdefaults/internal/db/migrations.Run.func1")
+
+		assert.Zero(t, c.Depth)
+		assert.Equal(t, []Attr{
+			{Key: "service", Value: "billing"},
+			{Key: keyKind, Value: KindInternal},
+		}, c.DefaultAttrs)
+	})
+
+	t.Run("other packages", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Same(t, base, packageConfig(base, "example.com/pkgdefaults/internal/dbx.Query"))
+		assert.Same(t, base, packageConfig(base, "example.com/pkgdefaults/internal.Run"))
+		assert.Same(t, base, packageConfig(base, ""))
+	})
+}
+
+func TestFuncPackage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		given    string
+		expected string
+	}{
+		{given: "main.main", expected: "main"},
+		{given: "github.com/me/app/db.Query", expected: "github.com/me/app/db"},
+		{given: "github.com/me/app/db.(*Store).Query.func1", expected: "github.com/me/app/db"},
+		{given: "gopkg.in/yaml%2ev3.Unmarshal", expected: "gopkg.in/yaml.v3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.given, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, funcPackage(tc.given))
+		})
+	}
+}
+
+func TestDefaultKind(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(DefaultKind(KindUnavailable))
+
+	err := w.Wrap(errors.New("connection refused"))
+	assert.Equal(t, KindUnavailable, KindOf(err))
+
+	err = WithKind(err, KindTimeout)
+	assert.Equal(t, KindTimeout, KindOf(err))
+}
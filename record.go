@@ -0,0 +1,308 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Frame describes a call site captured along an error chain.
+type Frame struct {
+	// Function is the fully qualified function name,
+	// e.g. "github.com/me/app/internal/db.Query".
+	Function string `json:"function"`
+	// File is the path of the source file as recorded by the runtime.
+	File string `json:"file"`
+	// Line is the line number within File.
+	Line int `json:"line"`
+	// Time is when the frame was captured.
+	Time time.Time `json:"time"`
+	// Message is the context added at the wrap site, if any.
+	Message string `json:"message,omitempty"`
+	// WrapSite is set on frames recorded by a wrap call, as opposed to
+	// the ones found by scanning the stack above the first wrap.
+	WrapSite bool `json:"wrap_site,omitempty"`
+	// Boundary is set on frames recorded by Boundary.
+	Boundary bool `json:"boundary,omitempty"`
+	// Args lists the values recorded with WithArgs, as rendered then,
+	// separated by commas.
+	Args string `json:"args,omitempty"`
+	// Tags lists the tags passed to Wrap, separated by commas.
+	Tags string `json:"tags,omitempty"`
+	// Service names the service that captured the frame, for frames
+	// imported from upstream services. It is empty for local frames.
+	Service string `json:"service,omitempty"`
+}
+
+// String renders the frame like Error does, as "pkg.Func (file.go:42)",
+// prefixed by "[service] " when Service is set.
+func (f Frame) String() string {
+	s := shortenFuncName(f.Function) + " (" + filepath.Base(f.File) + ":" + strconv.Itoa(f.Line) + ")"
+	if f.Service != "" {
+		s = "[" + f.Service + "] " + s
+	}
+	return s
+}
+
+// Source returns the frame's location in the shape slog uses for record
+// sources, so handlers that understand slog.Source can render it.
+func (f Frame) Source() *slog.Source {
+	return &slog.Source{
+		Function: f.Function,
+		File:     f.File,
+		Line:     f.Line,
+	}
+}
+
+// Runtime returns the frame as a runtime.Frame, for tooling written
+// against the runtime package such as symbolizers. Frames captured by
+// this process get the program counter and function they were resolved
+// from as well; others, decoded from records or parsed from text, only
+// hold their function, file and line.
+func (f Frame) Runtime() runtime.Frame {
+	if frame, ok := runtimeFrame(callSite{funcName: f.Function, file: f.File, line: f.Line}); ok {
+		return frame
+	}
+	return runtime.Frame{Function: f.Function, File: f.File, Line: f.Line}
+}
+
+// Frames returns the frames captured along err's chain, in the order
+// they are rendered by Error. Frames of errors nested with fmt.Errorf
+// follow those of the errors wrapping them.
+func Frames(err error) []Frame {
+	var frames []Frame
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+
+		for _, frame := range extErr.dedupedFrames() {
+			frames = append(frames, frame.export())
+		}
+	}
+	return frames
+}
+
+// Record is the serializable form of an error chain, used by Encode and
+// Decode and by integrations that ship errors to other systems.
+type Record struct {
+	// Error is the compact rendering of the whole chain, as returned by Error.
+	Error string `json:"error"`
+	// Message is the message of the root cause.
+	Message string `json:"message"`
+	// CauseType is the Go type of the root cause, as returned by CauseType.
+	CauseType string `json:"cause_type,omitempty"`
+	// ID is the support ID assigned on first wrap.
+	ID string `json:"id,omitempty"`
+	// PublicMessage is the user-facing message set with WithPublicMessage.
+	PublicMessage string `json:"public_message,omitempty"`
+	// GroupingKey is the fingerprint override set with WithGroupingKey.
+	GroupingKey string `json:"grouping_key,omitempty"`
+	// Code is the code set with WithCode.
+	Code Code `json:"code,omitempty"`
+	// Kind is the kind set with WithKind.
+	Kind Kind `json:"kind,omitempty"`
+	// Severity is the severity set with WithSeverity.
+	Severity Severity `json:"severity,omitempty"`
+	// Runbook is the playbook URL returned by Runbook.
+	Runbook string `json:"runbook,omitempty"`
+	// Hint is the next step for the user set with WithHint.
+	Hint string `json:"hint,omitempty"`
+	// FieldErrors holds the messages returned by FieldErrors.
+	FieldErrors map[string]string `json:"field_errors,omitempty"`
+	// Scopes lists the scopes returned by ScopesOf.
+	Scopes []string `json:"scopes,omitempty"`
+	// Runtime is the snapshot returned by RuntimeOf.
+	Runtime *RuntimeInfo `json:"runtime,omitempty"`
+	// Truncated is set when the capture budget cut the stack scan short.
+	Truncated bool `json:"truncated,omitempty"`
+	// Frames lists the captured frames, as returned by Frames.
+	Frames []Frame `json:"frames,omitempty"`
+	// Attrs lists the attributes, as returned by Attrs.
+	Attrs []Attr `json:"attrs,omitempty"`
+}
+
+// NewRecord captures err's chain into a Record.
+// Returns the zero Record if err is nil.
+func NewRecord(err error) Record {
+	if err == nil {
+		return Record{}
+	}
+
+	r := Record{
+		Error:     err.Error(),
+		Message:   Root(err).Error(),
+		CauseType: CauseType(err),
+		ID:        ID(err),
+		Frames:    recordFrames(err),
+		Attrs:     Attrs(err),
+	}
+	r.PublicMessage, _ = Value[string](err, keyPublicMessage)
+	r.GroupingKey, _ = Value[string](err, keyGroupingKey)
+	r.Code = CodeOf(err)
+	r.Kind = KindOf(err)
+	r.Severity, _ = Value[Severity](err, keySeverity)
+	r.Runbook = Runbook(err)
+	r.Hint = Hint(err)
+	r.FieldErrors = FieldErrors(err)
+	r.Truncated = Truncated(err)
+	r.Scopes = ScopesOf(err)
+	if info, ok := RuntimeOf(err); ok {
+		r.Runtime = &info
+	}
+	return r
+}
+
+// recordFrames returns err's frames with the fields selected by the
+// RecordFields of err's configuration.
+func recordFrames(err error) []Frame {
+	frames := Frames(err)
+	extErr := firstExtended(err)
+	if extErr == nil {
+		return frames
+	}
+
+	fields := extErr.config().recordFields()
+	if fields == AllFields {
+		return frames
+	}
+	for i := range frames {
+		frames[i] = frames[i].keep(fields)
+	}
+	return frames
+}
+
+// Err rebuilds an error from r. The result renders and reports like the
+// original chain, but its cause is a plain error holding the original
+// root message, so errors.Is and errors.As no longer match the original
+// cause. Attribute values keep the types they were decoded into.
+// Returns nil for a zero Record.
+func (r Record) Err() error {
+	if r.Message == "" && len(r.Frames) == 0 {
+		return nil
+	}
+
+	extErr := &extendedError{err: &remoteError{msg: r.Message, typ: r.CauseType}}
+	for _, frame := range r.Frames {
+		imported := importFrame(frame)
+		imported.remote = true
+		extErr.base = append(extErr.base, imported)
+	}
+
+	attrs := append([]Attr(nil), r.Attrs...)
+	if r.ID != "" {
+		attrs = append(attrs, Attr{Key: keyID, Value: r.ID})
+	}
+	if r.PublicMessage != "" {
+		attrs = append(attrs, Attr{Key: keyPublicMessage, Value: r.PublicMessage})
+	}
+	if r.GroupingKey != "" {
+		attrs = append(attrs, Attr{Key: keyGroupingKey, Value: r.GroupingKey})
+	}
+	if r.Code != "" {
+		attrs = append(attrs, Attr{Key: keyCode, Value: r.Code})
+	}
+	if r.Kind != "" {
+		attrs = append(attrs, Attr{Key: keyKind, Value: r.Kind})
+	}
+	if r.Severity != 0 {
+		attrs = append(attrs, Attr{Key: keySeverity, Value: r.Severity})
+	}
+	if r.Runbook != "" {
+		attrs = append(attrs, Attr{Key: keyRunbook, Value: r.Runbook})
+	}
+	if r.Hint != "" {
+		attrs = append(attrs, Attr{Key: keyHint, Value: r.Hint})
+	}
+	if r.Truncated {
+		attrs = append(attrs, Attr{Key: keyTruncated, Value: true})
+	}
+	for _, field := range slices.Sorted(maps.Keys(r.FieldErrors)) {
+		attrs = append(attrs, Attr{Key: keyFieldError, Value: fieldError{field: field, msg: r.FieldErrors[field]}})
+	}
+	if len(r.Scopes) > 0 {
+		attrs = append(attrs, Attr{Key: keyScopes, Value: r.Scopes})
+	}
+	if r.Runtime != nil {
+		attrs = append(attrs, Attr{Key: keyRuntime, Value: *r.Runtime})
+	}
+	extErr.attrs = attrs
+	return extErr
+}
+
+// Encode serializes err's chain as JSON.
+// Returns nil if err is nil.
+func Encode(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	return json.Marshal(NewRecord(err))
+}
+
+// Decode rebuilds an error from JSON produced by Encode.
+// See Record.Err for how the result relates to the original.
+func Decode(data []byte) (error, error) {
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("could not decode error record: %w", err)
+	}
+	return r.Err(), nil
+}
+
+// remoteError stands in for a cause that was serialized elsewhere.
+type remoteError struct {
+	msg string
+	// typ is the Go type of the original cause, if known
+	typ string
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+// export converts f into its public form.
+func (f contextFrame) export() Frame {
+	frame := Frame{
+		Function: f.funcName,
+		File:     f.file,
+		Line:     f.line,
+		Time:     f.time,
+		WrapSite: f.wrapSite,
+		Boundary: f.boundary,
+		Args:     f.args,
+		Tags:     f.tags,
+		Service:  f.service,
+	}
+	if f.msg != nil {
+		frame.Message = f.msg.String()
+	}
+	return frame
+}
+
+// importFrame converts a public frame back into its internal form.
+func importFrame(f Frame) contextFrame {
+	frame := contextFrame{
+		funcName: f.Function,
+		file:     f.File,
+		line:     f.Line,
+		time:     f.Time,
+		wrapSite: f.WrapSite,
+		boundary: f.Boundary,
+		args:     f.Args,
+		tags:     f.Tags,
+		service:  f.Service,
+	}
+	if f.Message != "" {
+		frame.msg = eagerMessage(f.Message)
+	}
+	return frame
+}
@@ -0,0 +1,225 @@
+package errx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no frames", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Frames(nil))
+		assert.Empty(t, Frames(errors.New("plain")))
+	})
+
+	t.Run("full names", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("boom"))
+		frames := Frames(err)
+
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "github.com/alesr/errx.TestFrames.func2", frames[0].Function)
+		assert.True(t, strings.HasSuffix(frames[0].File, "/record_test.go"))
+		assert.Positive(t, frames[0].Line)
+		assert.False(t, frames[0].Time.IsZero())
+	})
+
+	t.Run("nested errors", func(t *testing.T) {
+		t.Parallel()
+
+		inner := Wrap(errors.New("boom"))
+		outer := Wrap(fmt.Errorf("context: %w", inner))
+
+		frames := Frames(outer)
+
+		assert.Len(t, frames, len(Frames(inner))*2)
+		assert.Equal(t, Frames(inner), frames[len(frames)/2:])
+	})
+
+	t.Run("wrap messages", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapLazy(errors.New("boom"), func() string { return "loading user" })
+
+		assert.Equal(t, "loading user", Frames(err)[0].Message)
+	})
+}
+
+func TestFrameString(t *testing.T) {
+	t.Parallel()
+
+	frame := Frame{
+		Function: "github.com/me/app/internal/db.(*Store).Query",
+		File:     "/src/app/internal/db/db.go",
+		Line:     42,
+	}
+
+	assert.Equal(t, "db.(*Store).Query (db.go:42)", frame.String())
+}
+
+func TestFrameSource(t *testing.T) {
+	t.Parallel()
+
+	frame := Frame{
+		Function: "github.com/me/app/internal/db.(*Store).Query",
+		File:     "/src/app/internal/db/db.go",
+		Line:     42,
+		Message:  "querying",
+	}
+
+	assert.Equal(t, &slog.Source{
+		Function: "github.com/me/app/internal/db.(*Store).Query",
+		File:     "/src/app/internal/db/db.go",
+		Line:     42,
+	}, frame.Source())
+
+	// handlers render it like the source of the record itself
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("failed", "origin", frame.Source())
+	assert.Contains(t, buf.String(), `"origin":{"function":"github.com/me/app/internal/db.(*Store).Query","file":"/src/app/internal/db/db.go","line":42}`)
+}
+
+func TestFrameRuntime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("captured frame", func(t *testing.T) {
+		t.Parallel()
+
+		frame := Frames(Wrap(errors.New("boom")))[0]
+		rf := frame.Runtime()
+		assert.Equal(t, frame.Function, rf.Function)
+		assert.Equal(t, frame.File, rf.File)
+		assert.Equal(t, frame.Line, rf.Line)
+		require.NotZero(t, rf.PC)
+		require.NotNil(t, rf.Func)
+		assert.Equal(t, frame.Function, rf.Func.Name())
+
+		file, line := rf.Func.FileLine(rf.PC)
+		assert.Equal(t, frame.File, file)
+		assert.Equal(t, frame.Line, line)
+	})
+
+	t.Run("foreign frame", func(t *testing.T) {
+		t.Parallel()
+
+		frame := Frame{Function: "onSubmit", File: "checkout.js", Line: 88}
+		assert.Equal(t, runtime.Frame{Function: "onSubmit", File: "checkout.js", Line: 88}, frame.Runtime())
+	})
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Encode(nil)
+		require.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		original := With(errors.New("connection refused"), "host", "db-1")
+		original = WithPublicMessage(original, "try again later")
+		original = WithGroupingKey(original, "db.unavailable")
+		original = WithCode(original, "users.db_unavailable")
+		original = WithKind(original, KindUnavailable)
+		original = WithSeverity(original, SeverityCritical)
+		original = WithRunbook(original, "https://runbooks.example.com/db")
+		original = WithHint(original, "check the database is reachable")
+		original = WrapLazy(original, func() string { return "loading user" })
+
+		data, err := Encode(original)
+		require.NoError(t, err)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		require.NotNil(t, decoded)
+
+		assert.Equal(t, original.Error(), decoded.Error())
+		assert.Equal(t, fmt.Sprintf("%+v", original), fmt.Sprintf("%+v", decoded))
+		assert.Equal(t, ID(original), ID(decoded))
+		assert.Equal(t, PublicMessage(original), PublicMessage(decoded))
+		assert.Equal(t, "db.unavailable", Fingerprint(decoded))
+		assert.Equal(t, Summary(original), Summary(decoded))
+		assert.Equal(t, FirstSeen(original).UnixNano(), FirstSeen(decoded).UnixNano())
+		assert.Equal(t, Attrs(original), Attrs(decoded))
+		assert.Equal(t, Code("users.db_unavailable"), CodeOf(decoded))
+		assert.Equal(t, KindUnavailable, KindOf(decoded))
+		assert.Equal(t, SeverityCritical, SeverityOf(decoded))
+		assert.Equal(t, "https://runbooks.example.com/db", Runbook(decoded))
+		assert.Equal(t, "check the database is reachable", Hint(decoded))
+	})
+
+	t.Run("decoded cause is opaque", func(t *testing.T) {
+		t.Parallel()
+
+		sentinel := errors.New("not found")
+		data, err := Encode(Wrap(sentinel))
+		require.NoError(t, err)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+
+		assert.False(t, errors.Is(decoded, sentinel))
+		assert.Equal(t, "not found", errors.Unwrap(decoded).Error())
+	})
+
+	t.Run("plain errors", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Encode(errors.New("plain"))
+		require.NoError(t, err)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, "plain", decoded.Error())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Decode([]byte("{"))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty record", func(t *testing.T) {
+		t.Parallel()
+
+		decoded, err := Decode([]byte("{}"))
+		require.NoError(t, err)
+		assert.Nil(t, decoded)
+	})
+}
+
+func TestNewRecord(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	err := With(errors.New("boom"), "attempt", 2)
+	r := NewRecord(err)
+
+	assert.Equal(t, err.Error(), r.Error)
+	assert.Equal(t, "boom", r.Message)
+	assert.Equal(t, ID(err), r.ID)
+	assert.Equal(t, []Attr{{Key: "attempt", Value: 2}}, r.Attrs)
+	require.NotEmpty(t, r.Frames)
+	assert.WithinDuration(t, now, r.Frames[0].Time, time.Minute)
+
+	assert.Equal(t, Record{}, NewRecord(nil))
+}
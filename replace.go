@@ -0,0 +1,38 @@
+package errx
+
+import "errors"
+
+// ReplaceCause returns an error with err's frames and attributes but
+// newCause as its cause, e.g. to swap a driver-specific error for an
+// exported sentinel at an API boundary without losing the captured
+// context. The frames of every errx error along err's chain are kept;
+// context added with fmt.Errorf in between is dropped along with the
+// old cause. errors.Is and errors.As only match newCause afterwards.
+// If err holds no errx error, newCause is returned as is.
+// Returns nil if err or newCause is nil.
+func ReplaceCause(err, newCause error) error {
+	if err == nil || newCause == nil {
+		return nil
+	}
+
+	var layers []*extendedError
+	var chain visited
+	for e := err; e != nil && chain.add(e); e = errors.Unwrap(e) {
+		if extErr, ok := e.(*extendedError); ok {
+			layers = append(layers, extErr)
+		}
+	}
+	if len(layers) == 0 {
+		return newCause
+	}
+
+	replaced := &extendedError{err: newCause, cfg: layers[0].cfg}
+	for _, layer := range layers {
+		replaced.base = append(replaced.base, layer.dedupedFrames()...)
+	}
+	// attributes are stored innermost first, so outer values keep winning
+	for i := len(layers) - 1; i >= 0; i-- {
+		replaced.attrs = append(replaced.attrs, layers[i].attrs...)
+	}
+	return replaced
+}
@@ -0,0 +1,68 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceCause(t *testing.T) {
+	t.Parallel()
+
+	errDriver := errors.New("pq: duplicate key value violates unique constraint")
+	errConflict := errors.New("conflict")
+
+	t.Run("nil inputs", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, ReplaceCause(nil, errConflict))
+		assert.Nil(t, ReplaceCause(Wrap(errDriver), nil))
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, errConflict, ReplaceCause(errDriver, errConflict))
+	})
+
+	t.Run("keeps frames and attributes", func(t *testing.T) {
+		t.Parallel()
+
+		original := With(errDriver, "table", "users")
+		original = Wrapf(original, "inserting user")
+
+		err := ReplaceCause(original, errConflict)
+
+		assert.ErrorIs(t, err, errConflict)
+		assert.NotErrorIs(t, err, errDriver)
+		assert.Equal(t, Frames(original), Frames(err))
+		assert.Equal(t, Attrs(original), Attrs(err))
+		assert.Equal(t, ID(original), ID(err))
+
+		msg := err.Error()
+		assert.Contains(t, msg, ": inserting user: ")
+		assert.Regexp(t, `: conflict$`, msg)
+	})
+
+	t.Run("flattens nested errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		inner := With(errDriver, "attempt", 1)
+		outer := Wrap(fmt.Errorf("saving: %w", inner))
+		outer = With(outer, "attempt", 2)
+
+		err := ReplaceCause(outer, errConflict)
+
+		require.Len(t, Frames(err), len(Frames(outer)))
+		assert.Equal(t, Frames(outer), Frames(err))
+
+		attempt, ok := Value[int](err, "attempt")
+		require.True(t, ok)
+		assert.Equal(t, 2, attempt)
+		assert.Equal(t, ID(outer), ID(err))
+		assert.NotContains(t, err.Error(), "saving")
+	})
+}
@@ -0,0 +1,255 @@
+package errx
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attribute keys used for retry metadata.
+const (
+	AttrAttempt     = "retry.attempt"
+	AttrMaxAttempts = "retry.max_attempts"
+	AttrNextBackoff = "retry.next_backoff"
+	AttrAttempts    = "retry.attempts"
+)
+
+const keyRetryable = "errx.retryable"
+
+// RetryInfo describes where a failed operation stood in its retry loop.
+type RetryInfo struct {
+	// Attempt is the 1-based number of the attempt that failed.
+	Attempt int
+	// MaxAttempts is the total number of attempts allowed, 0 if unbounded.
+	MaxAttempts int
+	// NextBackoff is how long the caller waits before the next attempt,
+	// 0 if there is none.
+	NextBackoff time.Duration
+}
+
+// WithAttempt records that err was returned by attempt n of a retried operation.
+// Returns nil if err is nil.
+func WithAttempt(err error, n int) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: AttrAttempt, Value: n}, 3)
+}
+
+// WithRetryInfo records the full retry state in which err was returned.
+// Zero MaxAttempts and NextBackoff are left out.
+// Returns nil if err is nil.
+func WithRetryInfo(err error, info RetryInfo) error {
+	if err == nil {
+		return nil
+	}
+
+	extErr := withAttr(err, Attr{Key: AttrAttempt, Value: info.Attempt}, 3)
+	if info.MaxAttempts > 0 {
+		extErr = extErr.withAttr(Attr{Key: AttrMaxAttempts, Value: info.MaxAttempts})
+	}
+	if info.NextBackoff > 0 {
+		extErr = extErr.withAttr(Attr{Key: AttrNextBackoff, Value: info.NextBackoff})
+	}
+	return extErr
+}
+
+// RetryInfoOf returns the retry state recorded on err's chain.
+// It reports false if no attempt number was recorded.
+func RetryInfoOf(err error) (RetryInfo, bool) {
+	attempt, ok := Value[int](err, AttrAttempt)
+	if !ok {
+		return RetryInfo{}, false
+	}
+
+	maxAttempts, _ := Value[int](err, AttrMaxAttempts)
+	backoff, _ := Value[time.Duration](err, AttrNextBackoff)
+	return RetryInfo{
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+		NextBackoff: backoff,
+	}, true
+}
+
+// Permanent marks err as not worth retrying, whatever its kind, so Retry
+// gives up on it right away.
+// Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyRetryable, Value: false}, 3)
+}
+
+// Retryable marks err as worth retrying, whatever its kind.
+// Returns nil if err is nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyRetryable, Value: true}, 3)
+}
+
+// IsRetryable reports whether trying again may succeed where err failed.
+// The outermost Permanent or Retryable mark decides; without one, errors
+// are retryable unless they are cancellations or of a kind that blames
+// the request: invalid, not found, permission denied or unauthenticated.
+// It reports false if err is nil.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if retryable, ok := Value[bool](err, keyRetryable); ok {
+		return retryable
+	}
+	if is(err, context.Canceled) {
+		return false
+	}
+
+	switch KindOf(err) {
+	case KindInvalid, KindNotFound, KindPermissionDenied, KindUnauthenticated, KindCanceled:
+		return false
+	default:
+		return true
+	}
+}
+
+// RetryPolicy tells Retry how many times to try and how long to wait in
+// between. Zero fields take their default.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first.
+	// Defaults to 3.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt. Defaults to 100ms.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between attempts. Defaults to 10s.
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after each attempt. Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of each wait picked at random, from 0 to 1,
+	// so clients failing together don't retry together. Defaults to none.
+	Jitter float64
+}
+
+// withDefaults returns p with its zero fields set to their default.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// backoff returns the wait after the given 1-based attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.Backoff)
+	for range attempt - 1 {
+		d *= p.Multiplier
+		if d >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	d = min(d, float64(p.MaxBackoff))
+
+	if jitter := min(p.Jitter, 1); jitter > 0 {
+		d -= d * jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// Attempt summarizes a failed attempt of Retry.
+type Attempt struct {
+	// Attempt is the 1-based number of the attempt.
+	Attempt int `json:"attempt"`
+	// Origin is where the attempt's error started, see Origin.
+	// It is the zero Frame if the error holds no frames.
+	Origin Frame `json:"origin"`
+	// Message is the message of the attempt's root cause.
+	Message string `json:"message"`
+}
+
+// String renders a like Summary does, after the attempt number,
+// e.g. "#1 db.Query (db.go:42): connection refused".
+func (a Attempt) String() string {
+	s := "#" + strconv.Itoa(a.Attempt) + " "
+	if a.Origin.Function != "" {
+		s += a.Origin.String() + ": "
+	}
+	return s + a.Message
+}
+
+// Attempts lists the failed attempts of Retry, one per line when
+// rendered.
+type Attempts []Attempt
+
+// String renders the attempts one per line.
+func (a Attempts) String() string {
+	lines := make([]string, 0, len(a))
+	for _, attempt := range a {
+		lines = append(lines, attempt.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AttemptsOf returns the attempts Retry recorded on err, oldest first.
+func AttemptsOf(err error) Attempts {
+	attempts, _ := Value[Attempts](err, AttrAttempts)
+	return attempts
+}
+
+// Retry calls fn until it succeeds, backing off between attempts as
+// policy says. It gives up when the attempts run out, when fn returns an
+// error IsRetryable rejects, or when ctx is done while waiting.
+// Each attempt's error is recorded with WithRetryInfo. The error returned
+// is the last attempt's, carrying a summary of every attempt under
+// AttrAttempts, see AttemptsOf.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	var attempts Attempts
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		info := RetryInfo{Attempt: attempt, MaxAttempts: policy.MaxAttempts}
+		last := attempt == policy.MaxAttempts || !IsRetryable(err)
+		if !last {
+			info.NextBackoff = policy.backoff(attempt)
+		}
+
+		extErr := withAttr(err, Attr{Key: AttrAttempt, Value: info.Attempt}, 3).
+			withAttr(Attr{Key: AttrMaxAttempts, Value: info.MaxAttempts})
+		if info.NextBackoff > 0 {
+			extErr = extErr.withAttr(Attr{Key: AttrNextBackoff, Value: info.NextBackoff})
+		}
+
+		a := Attempt{Attempt: attempt, Message: Root(err).Error()}
+		a.Origin, _ = Origin(extErr)
+		attempts = append(attempts, a)
+
+		if !last {
+			timer := time.NewTimer(info.NextBackoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				last = true
+			case <-timer.C:
+			}
+		}
+		if last {
+			return extErr.withAttr(Attr{Key: AttrAttempts, Value: attempts})
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttempt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithAttempt(nil, 1))
+	})
+
+	t.Run("records the attempt", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithAttempt(errors.New("timeout"), 3)
+
+		info, ok := RetryInfoOf(Wrap(err))
+		require.True(t, ok)
+		assert.Equal(t, RetryInfo{Attempt: 3}, info)
+	})
+
+	t.Run("latest attempt wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithAttempt(errors.New("timeout"), 1)
+		err = WithAttempt(err, 2)
+
+		info, ok := RetryInfoOf(err)
+		require.True(t, ok)
+		assert.Equal(t, 2, info.Attempt)
+	})
+}
+
+func TestWithRetryInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithRetryInfo(nil, RetryInfo{Attempt: 1}))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		want := RetryInfo{Attempt: 2, MaxAttempts: 5, NextBackoff: 200 * time.Millisecond}
+		err := WithRetryInfo(errors.New("timeout"), want)
+
+		got, ok := RetryInfoOf(err)
+		require.True(t, ok)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("rendered in verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithRetryInfo(errors.New("timeout"), RetryInfo{Attempt: 2, MaxAttempts: 5, NextBackoff: time.Second})
+		verboseOutput := fmt.Sprintf("%+v", err)
+
+		assert.Contains(t, verboseOutput, "retry.attempt: 2\n")
+		assert.Contains(t, verboseOutput, "retry.max_attempts: 5\n")
+		assert.Contains(t, verboseOutput, "retry.next_backoff: 1s\n")
+		assert.NotContains(t, err.Error(), "retry.attempt")
+	})
+
+	t.Run("no retry info", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := RetryInfoOf(Wrap(errors.New("timeout")))
+		assert.False(t, ok)
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errors.New("timeout"), want: true},
+		{name: "canceled", err: Wrap(context.Canceled), want: false},
+		{name: "unavailable", err: WithKind(errors.New("down"), KindUnavailable), want: true},
+		{name: "invalid", err: WithKind(errors.New("bad input"), KindInvalid), want: false},
+		{name: "permanent", err: Permanent(errors.New("quota exceeded")), want: false},
+		{name: "retryable invalid", err: Retryable(WithKind(errors.New("stale"), KindConflict)), want: true},
+		{name: "outermost mark wins", err: Retryable(Permanent(errors.New("boom"))), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+
+	assert.Nil(t, Permanent(nil))
+	assert.Nil(t, Retryable(nil))
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	fast := RetryPolicy{MaxAttempts: 3, Backoff: time.Nanosecond}
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		err := Retry(context.Background(), fast, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("timeout")
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("records every attempt", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		err := Retry(context.Background(), fast, func(context.Context) error {
+			calls++
+			return Wrap(fmt.Errorf("timeout %d", calls))
+		})
+		require.Error(t, err)
+
+		info, ok := RetryInfoOf(err)
+		require.True(t, ok)
+		assert.Equal(t, RetryInfo{Attempt: 3, MaxAttempts: 3}, info)
+		assert.Equal(t, "timeout 3", Root(err).Error())
+
+		attempts := AttemptsOf(err)
+		require.Len(t, attempts, 3)
+		for i, attempt := range attempts {
+			assert.Equal(t, i+1, attempt.Attempt)
+			assert.Equal(t, fmt.Sprintf("timeout %d", i+1), attempt.Message)
+			assert.True(t, strings.HasPrefix(attempt.Origin.Function, "github.com/alesr/errx.TestRetry."), attempt.Origin.Function)
+		}
+
+		verbose := fmt.Sprintf("%+v", err)
+		assert.Regexp(t, `retry\.attempts: #1 errx\.TestRetry\.\S+ \(retry_test\.go:\d+\): timeout 1\n    #2 .*: timeout 2\n    #3 .*: timeout 3\n`, verbose)
+	})
+
+	t.Run("stops on permanent errors", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		err := Retry(context.Background(), fast, func(context.Context) error {
+			calls++
+			return Permanent(errors.New("quota exceeded"))
+		})
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, Attempts{{Attempt: 1, Origin: AttemptsOf(err)[0].Origin, Message: "quota exceeded"}}, AttemptsOf(err))
+
+		info, _ := RetryInfoOf(err)
+		assert.Zero(t, info.NextBackoff)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int
+		err := Retry(ctx, RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}, func(context.Context) error {
+			calls++
+			cancel()
+			return errors.New("timeout")
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Len(t, AttemptsOf(err), 1)
+	})
+
+	t.Run("plain errors get the caller's frame", func(t *testing.T) {
+		t.Parallel()
+
+		err := Retry(context.Background(), RetryPolicy{MaxAttempts: 1}, func(context.Context) error {
+			return errors.New("timeout")
+		})
+
+		frames := Frames(err)
+		require.NotEmpty(t, frames)
+		assert.True(t, strings.HasPrefix(frames[0].Function, "github.com/alesr/errx.TestRetry."), frames[0].Function)
+		assert.Equal(t, frames[0], AttemptsOf(err)[0].Origin)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}.withDefaults()
+	assert.Equal(t, 3, p.MaxAttempts)
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(100))
+
+	p.Jitter = 0.5
+	for range 100 {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
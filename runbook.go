@@ -0,0 +1,57 @@
+package errx
+
+import "sync"
+
+const keyRunbook = "errx.runbook"
+
+var (
+	runbooksMu   sync.RWMutex
+	codeRunbooks = make(map[Code]string)
+	kindRunbooks = make(map[Kind]string)
+)
+
+// RegisterRunbook associates the runbook at url with errors carrying code.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterRunbook(code Code, url string) {
+	runbooksMu.Lock()
+	defer runbooksMu.Unlock()
+	codeRunbooks[code] = url
+}
+
+// RegisterKindRunbook associates the runbook at url with errors of kind.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterKindRunbook(kind Kind, url string) {
+	runbooksMu.Lock()
+	defer runbooksMu.Unlock()
+	kindRunbooks[kind] = url
+}
+
+// WithRunbook attaches the runbook at url to err, taking precedence over
+// the ones registered for its code and kind.
+// Returns nil if err is nil.
+func WithRunbook(err error, url string) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keyRunbook, Value: url}, 3)
+}
+
+// Runbook returns the URL of the playbook for err: the one attached with
+// WithRunbook, or else the one registered for its code, or else the one
+// registered for its kind. It returns an empty string if there is none.
+func Runbook(err error) string {
+	if url, ok := Value[string](err, keyRunbook); ok {
+		return url
+	}
+
+	runbooksMu.RLock()
+	defer runbooksMu.RUnlock()
+
+	if url, ok := codeRunbooks[CodeOf(err)]; ok {
+		return url
+	}
+	if url, ok := kindRunbooks[KindOf(err)]; ok {
+		return url
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunbook(t *testing.T) {
+	t.Parallel()
+
+	// registrations are global, so the codes and kinds are unique to this test
+	RegisterRunbook("runbook_test.code", "https://runbooks.example.com/code")
+	RegisterKindRunbook("runbook_test_kind", "https://runbooks.example.com/kind")
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: "",
+		},
+		{
+			name:     "none",
+			err:      Wrap(errors.New("boom")),
+			expected: "",
+		},
+		{
+			name:     "registered for the kind",
+			err:      WithKind(errors.New("boom"), "runbook_test_kind"),
+			expected: "https://runbooks.example.com/kind",
+		},
+		{
+			name:     "code takes precedence over kind",
+			err:      WithCode(WithKind(errors.New("boom"), "runbook_test_kind"), "runbook_test.code"),
+			expected: "https://runbooks.example.com/code",
+		},
+		{
+			name:     "attached takes precedence over registered",
+			err:      WithRunbook(WithCode(errors.New("boom"), "runbook_test.code"), "https://runbooks.example.com/adhoc"),
+			expected: "https://runbooks.example.com/adhoc",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, Runbook(tc.err))
+		})
+	}
+
+	t.Run("verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithRunbook(errors.New("boom"), "https://runbooks.example.com/adhoc")
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nrunbook: https://runbooks.example.com/adhoc\n")
+	})
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithRunbook(nil, "https://runbooks.example.com/adhoc"))
+	})
+}
@@ -0,0 +1,74 @@
+package errx
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const keyRuntime = "errx.runtime"
+
+// RuntimeInfo is a snapshot of the process taken when an error was first
+// wrapped, attached when Config.Runtime is set.
+type RuntimeInfo struct {
+	// GOOS and GOARCH are the target the binary was built for.
+	GOOS   string `json:"goos"`
+	GOARCH string `json:"goarch"`
+	// GoVersion is the Go version the binary was built with.
+	GoVersion string `json:"go_version"`
+	// NumGoroutine is the number of goroutines alive at capture time.
+	NumGoroutine int `json:"num_goroutine"`
+	// Env holds the variables listed in Config.RuntimeEnv that were set.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// String renders r on one line, e.g.
+// "go1.24.0 linux/amd64, 12 goroutines, REGION=eu-west-1".
+func (r RuntimeInfo) String() string {
+	parts := []string{
+		fmt.Sprintf("%s %s/%s", r.GoVersion, r.GOOS, r.GOARCH),
+		fmt.Sprintf("%d goroutines", r.NumGoroutine),
+	}
+	for _, key := range slices.Sorted(maps.Keys(r.Env)) {
+		parts = append(parts, key+"="+r.Env[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CaptureRuntime attaches a RuntimeInfo snapshot, including the listed
+// environment variables, to the errors the Wrapper wraps first.
+func CaptureRuntime(env ...string) Option {
+	return optionFunc(func(c *Config) {
+		c.Runtime = true
+		c.RuntimeEnv = append(c.RuntimeEnv, env...)
+	})
+}
+
+// RuntimeOf returns the snapshot taken when err was first wrapped. It
+// reports false when none was, see Config.Runtime.
+func RuntimeOf(err error) (RuntimeInfo, bool) {
+	return Value[RuntimeInfo](err, keyRuntime)
+}
+
+// captureRuntime takes a snapshot of the process, reading only the
+// environment variables c allows.
+func captureRuntime(c *Config) RuntimeInfo {
+	r := RuntimeInfo{
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		GoVersion:    runtime.Version(),
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+	for _, key := range c.RuntimeEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			if r.Env == nil {
+				r.Env = make(map[string]string)
+			}
+			r.Env[key] = value
+		}
+	}
+	return r
+}
@@ -0,0 +1,57 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeOf(t *testing.T) {
+	t.Setenv("ERRX_TEST_REGION", "eu-west-1")
+	t.Setenv("ERRX_TEST_SECRET", "hunter2")
+
+	w := NewWrapper(CaptureRuntime("ERRX_TEST_REGION", "ERRX_TEST_UNSET"))
+
+	t.Run("captured on first wrap", func(t *testing.T) {
+		err := w.Wrap(w.Wrap(errors.New("disk full")))
+
+		info, ok := RuntimeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, runtime.GOOS, info.GOOS)
+		assert.Equal(t, runtime.GOARCH, info.GOARCH)
+		assert.Equal(t, runtime.Version(), info.GoVersion)
+		assert.Positive(t, info.NumGoroutine)
+		assert.Equal(t, map[string]string{"ERRX_TEST_REGION": "eu-west-1"}, info.Env, "only set, allowed variables are read")
+	})
+
+	t.Run("shown in verbose output", func(t *testing.T) {
+		err := w.Wrap(errors.New("disk full"))
+
+		assert.Regexp(t, `(?m)^runtime: go\S+ \w+/\w+, \d+ goroutines, ERRX_TEST_REGION=eu-west-1$`, fmt.Sprintf("%+v", err))
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "errx.runtime", "the attribute is reserved")
+	})
+
+	t.Run("survives encoding", func(t *testing.T) {
+		err := w.Wrap(errors.New("disk full"))
+
+		data, encErr := Encode(err)
+		require.NoError(t, encErr)
+		assert.Contains(t, string(data), `"runtime":{"goos":"`+runtime.GOOS+`"`)
+
+		decoded, decErr := Decode(data)
+		require.NoError(t, decErr)
+		want, _ := RuntimeOf(err)
+		got, ok := RuntimeOf(decoded)
+		require.True(t, ok)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		_, ok := RuntimeOf(NewWrapper().Wrap(errors.New("disk full")))
+		assert.False(t, ok)
+	})
+}
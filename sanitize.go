@@ -0,0 +1,93 @@
+package errx
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// substitute maps the causes match reports to their exported replacement.
+type substitute struct {
+	match    func(error) bool
+	exported error
+}
+
+var (
+	substitutesMu sync.Mutex
+	substitutes   atomic.Pointer[[]*substitute]
+)
+
+// RegisterSubstitute makes Sanitize replace the cause of the errors
+// matching internal, as errors.Is would, by exported, e.g. sql.ErrNoRows
+// by the storage package's ErrNotFound. The returned function removes the
+// substitute, for tests to register theirs with t.Cleanup.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterSubstitute(internal, exported error) (unregister func()) {
+	return registerSubstitute(&substitute{
+		match:    func(err error) bool { return is(err, internal) },
+		exported: exported,
+	})
+}
+
+// RegisterTypeSubstitute makes Sanitize replace the cause of the errors
+// holding a T, as errors.As would find it, by exported, e.g. every
+// *pgconn.PgError by the storage package's ErrConflict:
+//
+//	errx.RegisterTypeSubstitute[*pgconn.PgError](storage.ErrConflict)
+//
+// The returned function removes the substitute, like the one returned by
+// RegisterSubstitute.
+// It is meant to be called at startup, but is safe for concurrent use.
+func RegisterTypeSubstitute[T error](exported error) (unregister func()) {
+	return registerSubstitute(&substitute{
+		match:    as[T],
+		exported: exported,
+	})
+}
+
+// registerSubstitute adds s after the substitutes registered so far, and
+// returns the function removing it.
+func registerSubstitute(s *substitute) (unregister func()) {
+	substitutesMu.Lock()
+	defer substitutesMu.Unlock()
+
+	var registered []*substitute
+	if p := substitutes.Load(); p != nil {
+		registered = *p
+	}
+	registered = append(slices.Clip(registered), s)
+	substitutes.Store(&registered)
+
+	return func() {
+		substitutesMu.Lock()
+		defer substitutesMu.Unlock()
+
+		if p := substitutes.Load(); p != nil {
+			kept := slices.DeleteFunc(slices.Clone(*p), func(r *substitute) bool { return r == s })
+			substitutes.Store(&kept)
+		}
+	}
+}
+
+// Sanitize swaps err's cause for the exported error registered with
+// RegisterSubstitute or RegisterTypeSubstitute for it, as ReplaceCause
+// does, so driver errors don't leak through a public API while the frames
+// and attributes captured so far are kept. Substitutes are tried in the
+// order they were registered, the first matching one wins. Errors no
+// substitute matches are returned as is; wrap them with Barrier to hide
+// whatever is left.
+// Returns nil if err is nil.
+func Sanitize(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if p := substitutes.Load(); p != nil {
+		for _, s := range *p {
+			if s.match(err) {
+				return ReplaceCause(err, s.exported)
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driverError stands in for the error type of a database driver.
+type driverError struct{ code string }
+
+func (e *driverError) Error() string { return "driver error " + e.code }
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errNoRows    = errors.New("no rows in result set")
+		errNotFound  = errors.New("not found")
+		errConflict  = errors.New("conflict")
+		errUnmatched = errors.New("unmatched")
+	)
+	t.Cleanup(RegisterSubstitute(errNoRows, errNotFound))
+	t.Cleanup(RegisterTypeSubstitute[*driverError](errConflict))
+
+	t.Run("sentinels", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithCode(Wrap(fmt.Errorf("querying invoice: %w", errNoRows)), "billing.invoice.missing")
+		sanitized := Sanitize(Wrap(err))
+
+		assert.ErrorIs(t, sanitized, errNotFound)
+		assert.NotErrorIs(t, sanitized, errNoRows)
+		assert.NotContains(t, sanitized.Error(), "no rows")
+		assert.Equal(t, ID(err), ID(sanitized))
+		assert.Equal(t, Code("billing.invoice.missing"), CodeOf(sanitized))
+		require.Len(t, Frames(sanitized), len(Frames(Wrap(err))))
+	})
+
+	t.Run("types", func(t *testing.T) {
+		t.Parallel()
+
+		sanitized := Sanitize(Wrap(&driverError{code: "23505"}))
+
+		assert.ErrorIs(t, sanitized, errConflict)
+		var driverErr *driverError
+		assert.False(t, errors.As(sanitized, &driverErr))
+		assert.NotEmpty(t, Frames(sanitized))
+	})
+
+	t.Run("unmatched and nil", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errUnmatched)
+		assert.Same(t, err, Sanitize(err))
+		assert.Nil(t, Sanitize(nil))
+	})
+
+	t.Run("cyclic chains", func(t *testing.T) {
+		t.Parallel()
+
+		// both matchers walk past the loop without finding a substitute
+		err := Wrap(fmt.Errorf("loading: %w", newLoop(3)))
+		assert.Same(t, err, Sanitize(err))
+
+		sentinel := &joinLoop{}
+		sentinel.errs = []error{sentinel, errNoRows}
+		assert.ErrorIs(t, Sanitize(Wrap(sentinel)), errNotFound)
+
+		typed := &joinLoop{}
+		typed.errs = []error{typed, &driverError{code: "23505"}}
+		assert.ErrorIs(t, Sanitize(Wrap(typed)), errConflict)
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		t.Parallel()
+
+		errTimeout := errors.New("timeout")
+		unregister := RegisterSubstitute(errTimeout, errors.New("unavailable"))
+		unregister()
+		unregister()
+
+		err := Wrap(errTimeout)
+		assert.Same(t, err, Sanitize(err))
+	})
+}
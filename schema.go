@@ -0,0 +1,151 @@
+package errx
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaJSON is the JSON Schema (draft 2020-12) of the records produced by
+// Encode and NewRecord, for services written in other languages to
+// validate errx errors or generate types from. Fields may be added in
+// later versions, so consumers should ignore the ones they don't know.
+//
+//go:embed schema.json
+var SchemaJSON string
+
+// schemaNode is the subset of JSON Schema used by SchemaJSON.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []string               `json:"enum"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	AdditionalProperties *schemaNode            `json:"additionalProperties"`
+	Items                *schemaNode            `json:"items"`
+	Defs                 map[string]*schemaNode `json:"$defs"`
+}
+
+// schema returns SchemaJSON, parsed once.
+var schema = sync.OnceValue(func() *schemaNode {
+	var root schemaNode
+	if err := json.Unmarshal([]byte(SchemaJSON), &root); err != nil {
+		panic("errx: invalid embedded schema: " + err.Error())
+	}
+	return &root
+})
+
+// Validate reports whether data is an error record matching SchemaJSON,
+// as produced by Encode, returning an error naming the first field that
+// doesn't match. Unknown fields are accepted.
+func Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("could not decode error record: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("could not decode error record: unexpected data after the record")
+	}
+
+	root := schema()
+	if err := root.validate(root, v, "record"); err != nil {
+		return fmt.Errorf("invalid error record: %w", err)
+	}
+	return nil
+}
+
+// validate checks v against n, path naming v in errors.
+func (n *schemaNode) validate(root *schemaNode, v any, path string) error {
+	if n.Ref != "" {
+		name, _ := strings.CutPrefix(n.Ref, "#/$defs/")
+		def, ok := root.Defs[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema reference %q", path, n.Ref)
+		}
+		n = def
+	}
+
+	if len(n.Enum) > 0 {
+		s, ok := v.(string)
+		if !ok || !slices.Contains(n.Enum, s) {
+			return fmt.Errorf("%s: must be one of %q", path, n.Enum)
+		}
+	}
+
+	switch n.Type {
+	case "":
+		return nil
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: must be a string", path)
+		}
+		if n.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: must be an RFC 3339 date-time", path)
+			}
+		}
+	case "integer":
+		num, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: must be an integer", path)
+		}
+		if _, err := num.Int64(); err != nil {
+			return fmt.Errorf("%s: must be an integer", path)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: must be a boolean", path)
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: must be an array", path)
+		}
+		if n.Items == nil {
+			return nil
+		}
+		for i, item := range items {
+			if err := n.Items.validate(root, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: must be an object", path)
+		}
+		for _, key := range n.Required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("%s: missing %q", path, key)
+			}
+		}
+		// sorted so the first mismatch reported doesn't change between runs
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			prop, ok := n.Properties[key]
+			if !ok {
+				prop = n.AdditionalProperties
+			}
+			if prop == nil {
+				continue
+			}
+			if err := prop.validate(root, obj[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, n.Type)
+	}
+	return nil
+}
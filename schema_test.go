@@ -0,0 +1,104 @@
+package errx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaJSON(t *testing.T) {
+	t.Parallel()
+
+	// jsonFields returns the JSON names of the fields of typ
+	jsonFields := func(typ reflect.Type) []string {
+		var names []string
+		for i := range typ.NumField() {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			names = append(names, name)
+		}
+		return names
+	}
+	// propertyNames returns the properties declared by node
+	propertyNames := func(node *schemaNode) []string {
+		var names []string
+		for name := range node.Properties {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	root := schema()
+	assert.ElementsMatch(t, jsonFields(reflect.TypeFor[Record]()), propertyNames(root))
+	assert.ElementsMatch(t, jsonFields(reflect.TypeFor[Frame]()), propertyNames(root.Defs["frame"]))
+	assert.ElementsMatch(t, jsonFields(reflect.TypeFor[Attr]()), propertyNames(root.Defs["attr"]))
+	assert.ElementsMatch(t, jsonFields(reflect.TypeFor[RuntimeInfo]()), propertyNames(root.Defs["runtime"]))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts encoded errors", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(CaptureRuntime("HOME"))
+		ctx := PushScope(context.Background(), "checkout")
+		err := WrapCtx(ctx, w.Wrap(errors.New("boom")))
+		err = WithSeverity(With(err, "user_id", 42), SeverityCritical)
+		data, encErr := Encode(err)
+		require.NoError(t, encErr)
+
+		assert.NoError(t, Validate(data))
+
+		plain, encErr := Encode(errors.New("plain"))
+		require.NoError(t, encErr)
+		assert.NoError(t, Validate(plain))
+	})
+
+	t.Run("accepts unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, Validate([]byte(`{"error": "boom", "message": "boom", "added_later": 1}`)))
+	})
+
+	t.Run("rejects invalid records", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			data string
+			want string
+		}{
+			"not json":          {`{`, "could not decode error record"},
+			"trailing data":     {`{"error": "a", "message": "a"} {}`, "unexpected data"},
+			"not an object":     {`[]`, "record: must be an object"},
+			"missing message":   {`{"error": "boom"}`, `record: missing "message"`},
+			"wrong type":        {`{"error": "boom", "message": 1}`, "record.message: must be a string"},
+			"unknown severity":  {`{"error": "a", "message": "a", "severity": "fatal"}`, "record.severity: must be one of"},
+			"frame line":        {`{"error": "a", "message": "a", "frames": [{"function": "f", "file": "f.go", "line": 1.5, "time": "2024-05-01T12:00:00Z"}]}`, "record.frames[0].line: must be an integer"},
+			"frame time":        {`{"error": "a", "message": "a", "frames": [{"function": "f", "file": "f.go", "line": 1, "time": "yesterday"}]}`, "record.frames[0].time: must be an RFC 3339 date-time"},
+			"attr without key":  {`{"error": "a", "message": "a", "attrs": [{"value": 1}]}`, `record.attrs[0]: missing "key"`},
+			"runtime env value": {`{"error": "a", "message": "a", "runtime": {"goos": "linux", "goarch": "amd64", "go_version": "go1.24", "num_goroutine": 1, "env": {"REGION": 1}}}`, "record.runtime.env.REGION: must be a string"},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				err := Validate([]byte(tt.data))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+
+	t.Run("schema is valid json", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, json.Valid([]byte(SchemaJSON)))
+	})
+}
@@ -0,0 +1,75 @@
+package errx
+
+import (
+	"context"
+	"strings"
+)
+
+const keyScopes = "errx.scopes"
+
+// scopeKey is the context key under which PushScope stores scopes.
+type scopeKey struct{}
+
+// scope is an operation pushed with PushScope, linked to the one it
+// runs in.
+type scope struct {
+	name   string
+	parent *scope
+	depth  int
+}
+
+// PushScope returns a copy of ctx describing the operation name, such as
+// "import batch 42", as running within the scopes already in ctx. Errors
+// wrapped with WrapCtx carry the scopes active in their context, giving
+// breadcrumbs of what the program was doing beyond function names.
+func PushScope(ctx context.Context, name string) context.Context {
+	parent, _ := ctx.Value(scopeKey{}).(*scope)
+	s := &scope{name: name, parent: parent, depth: 1}
+	if parent != nil {
+		s.depth += parent.depth
+	}
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// Scopes returns the scopes pushed on ctx, outermost first.
+func Scopes(ctx context.Context) []string {
+	s, _ := ctx.Value(scopeKey{}).(*scope)
+	if s == nil {
+		return nil
+	}
+
+	scopes := make([]string, s.depth)
+	for ; s != nil; s = s.parent {
+		scopes[s.depth-1] = s.name
+	}
+	return scopes
+}
+
+// WrapCtx wraps err like Wrap and attaches the scopes active in ctx.
+// When err already carries scopes, they are kept: the ones closest to
+// the failure tell the most.
+// Returns nil if err is nil, and err itself if it matches Config.Ignore.
+func WrapCtx(ctx context.Context, err error) error {
+	if err == nil || loadConfig().ignored(err) {
+		return err
+	}
+
+	// scopes are attached before the OnWrap hooks see the error
+	if _, ok := Value[[]string](err, keyScopes); !ok {
+		if scopes := Scopes(ctx); len(scopes) > 0 {
+			return wrap(nil, err, 2, contextFrame{}, Attr{Key: keyScopes, Value: scopes})
+		}
+	}
+	return wrap(nil, err, 2, contextFrame{})
+}
+
+// ScopesOf returns the scopes attached to err by WrapCtx, outermost first.
+func ScopesOf(err error) []string {
+	scopes, _ := Value[[]string](err, keyScopes)
+	return scopes
+}
+
+// formatScopes renders scopes as a breadcrumb trail.
+func formatScopes(scopes []string) string {
+	return strings.Join(scopes, " > ")
+}
@@ -0,0 +1,78 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("outermost first", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := PushScope(context.Background(), "import batch 42")
+		inner := PushScope(ctx, "row 17")
+
+		assert.Equal(t, []string{"import batch 42", "row 17"}, Scopes(inner))
+		assert.Equal(t, []string{"import batch 42"}, Scopes(ctx), "pushing doesn't change the parent")
+		assert.Nil(t, Scopes(context.Background()))
+	})
+}
+
+func TestWrapCtx(t *testing.T) {
+	t.Parallel()
+
+	ctx := PushScope(PushScope(context.Background(), "import batch 42"), "row 17")
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WrapCtx(ctx, nil))
+	})
+
+	t.Run("attaches the active scopes", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapCtx(ctx, errors.New("invalid date"))
+
+		assert.Equal(t, []string{"import batch 42", "row 17"}, ScopesOf(err))
+		assert.Regexp(t, `^errx\.TestWrapCtx\.\S+ \(scope_test\.go:\d+\): `, err.Error(), "records the caller's frame")
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nscopes: import batch 42 > row 17\n")
+		assert.Empty(t, Attrs(err), "the attribute is reserved")
+	})
+
+	t.Run("keeps the innermost scopes", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapCtx(ctx, errors.New("invalid date"))
+		err = WrapCtx(PushScope(context.Background(), "import"), err)
+
+		assert.Equal(t, []string{"import batch 42", "row 17"}, ScopesOf(err))
+	})
+
+	t.Run("without scopes", func(t *testing.T) {
+		t.Parallel()
+
+		err := WrapCtx(context.Background(), errors.New("invalid date"))
+
+		require.Error(t, err)
+		assert.Nil(t, ScopesOf(err))
+	})
+
+	t.Run("survives encoding", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := Encode(WrapCtx(ctx, errors.New("invalid date")))
+		require.NoError(t, err)
+
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"import batch 42", "row 17"}, ScopesOf(decoded))
+	})
+}
@@ -0,0 +1,46 @@
+package errx
+
+import (
+	"errors"
+	"time"
+)
+
+// FirstSeen returns the earliest capture time recorded along err's chain,
+// usually the moment it was first wrapped. It returns the zero time if
+// err carries no errx frames.
+func FirstSeen(err error) time.Time {
+	first, _ := seen(err)
+	return first
+}
+
+// LastSeen returns the latest capture time recorded along err's chain,
+// usually the moment it was last wrapped. It returns the zero time if
+// err carries no errx frames.
+func LastSeen(err error) time.Time {
+	_, last := seen(err)
+	return last
+}
+
+// seen returns the earliest and latest frame times along err's chain.
+func seen(err error) (first, last time.Time) {
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		extErr, ok := err.(*extendedError)
+		if !ok {
+			continue
+		}
+
+		for _, frame := range extErr.frames() {
+			if frame.time.IsZero() {
+				continue
+			}
+			if first.IsZero() || frame.time.Before(first) {
+				first = frame.time
+			}
+			if frame.time.After(last) {
+				last = frame.time
+			}
+		}
+	}
+	return first, last
+}
@@ -0,0 +1,54 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstSeenLastSeen(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	WithTestConfig(t, Config{Clock: func() time.Time { return now }})
+
+	t.Run("no frames", func(t *testing.T) {
+		assert.True(t, FirstSeen(nil).IsZero())
+		assert.True(t, LastSeen(errors.New("plain")).IsZero())
+	})
+
+	t.Run("single wrap", func(t *testing.T) {
+		err := Wrap(errors.New("boom"))
+
+		assert.Equal(t, start, FirstSeen(err))
+		assert.Equal(t, start, LastSeen(err))
+	})
+
+	t.Run("across wraps", func(t *testing.T) {
+		now = start
+		err := Wrap(errors.New("boom"))
+
+		now = start.Add(time.Second)
+		err = fmt.Errorf("context: %w", Wrap(err))
+
+		now = start.Add(3 * time.Second)
+		err = Wrap(err)
+
+		assert.Equal(t, start, FirstSeen(err))
+		assert.Equal(t, start.Add(3*time.Second), LastSeen(err))
+		assert.Equal(t, 3*time.Second, LastSeen(err).Sub(FirstSeen(err)))
+	})
+}
+
+func TestDeterministicClock(t *testing.T) {
+	WithTestConfig(t, Config{
+		Deterministic: true,
+		Clock:         func() time.Time { return time.Now().Add(time.Hour) },
+	})
+
+	err := Wrap(errors.New("boom"))
+
+	assert.Equal(t, time.Unix(0, 0).UTC(), FirstSeen(err))
+}
@@ -0,0 +1,159 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+const keySeverity = "errx.severity"
+
+// Severity tells how urgently a failure needs attention, for routing
+// errors to log levels, alerting tiers and exporters.
+type Severity int
+
+// Severities, from the least to the most urgent. The zero Severity means
+// none was set.
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lowercase name of s, e.g. "warning".
+func (s Severity) String() string {
+	if s > 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Level returns the slog level matching s. SeverityCritical maps to
+// slog.LevelError+4, which slog renders as "ERROR+4", and the zero
+// Severity to slog.LevelError like SeverityOf.
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
+
+// MarshalText encodes s as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	if s <= 0 || int(s) >= len(severityNames) {
+		return nil, fmt.Errorf("invalid severity %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a name produced by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for i, name := range severityNames {
+		if name != "" && name == string(text) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// WithSeverity sets err's severity. If err is not an errx error yet it is
+// wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func WithSeverity(err error, severity Severity) error {
+	if err == nil {
+		return nil
+	}
+	return withAttr(err, Attr{Key: keySeverity, Value: severity}, 3)
+}
+
+// SeverityOf returns the severity set on err with WithSeverity, or
+// SeverityError when none was set, errors being errors unless said
+// otherwise. When set more than once, the outermost severity wins.
+func SeverityOf(err error) Severity {
+	if severity, ok := Value[Severity](err, keySeverity); ok {
+		return severity
+	}
+	return SeverityError
+}
+
+// appendToken appends the "[code:severity] " prefix of Config.Token to
+// dst, or nothing when e has neither a code nor a severity.
+func (e *extendedError) appendToken(dst []byte) []byte {
+	code := CodeOf(e)
+	severity, ok := Value[Severity](e, keySeverity)
+	if code == "" && !ok {
+		return dst
+	}
+	if !ok {
+		severity = SeverityError
+	}
+
+	dst = append(dst, '[')
+	dst = append(dst, code...)
+	dst = append(dst, ':')
+	dst = append(dst, severity.String()...)
+	return append(dst, "] "...)
+}
+
+// LogWithLevel logs err with logger at the level matching its severity,
+// instead of always logging errors at slog.LevelError. The message is
+// err's rendering, followed by its support ID, code and kind when set,
+// and its attributes. Handlers adding the source report the call to
+// LogWithLevel. A nil logger logs with slog.Default.
+// It does nothing if err is nil.
+func LogWithLevel(logger *slog.Logger, err error) {
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level := SeverityOf(err).Level()
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var attrs []slog.Attr
+	if id := ID(err); id != "" {
+		attrs = append(attrs, slog.String("id", id))
+	}
+	if code := CodeOf(err); code != "" {
+		attrs = append(attrs, slog.String("code", string(code)))
+	}
+	if kind := KindOf(err); kind != "" {
+		attrs = append(attrs, slog.String("kind", string(kind)))
+	}
+	for _, attr := range Attrs(err) {
+		attrs = append(attrs, slog.Any(attr.Key, attr.Value))
+	}
+
+	// skip runtime.Callers and LogWithLevel
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, err.Error(), pcs[0])
+	r.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,183 @@
+package errx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithSeverity(nil, SeverityWarning))
+		assert.Equal(t, SeverityError, SeverityOf(nil))
+		assert.Equal(t, SeverityError, SeverityOf(errors.New("plain")))
+	})
+
+	t.Run("preserved through wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithSeverity(errors.New("cache miss"), SeverityDebug)
+		err = Wrap(fmt.Errorf("loading user: %w", err))
+
+		assert.Equal(t, SeverityDebug, SeverityOf(err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nseverity: debug\n")
+		assert.NotContains(t, fmt.Sprintf("%+v", Wrap(errors.New("boom"))), "severity:")
+	})
+
+	t.Run("outermost wins", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithSeverity(errors.New("disk full"), SeverityWarning)
+		err = WithSeverity(err, SeverityCritical)
+
+		assert.Equal(t, SeverityCritical, SeverityOf(err))
+	})
+
+	t.Run("text form", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "warning", SeverityWarning.String())
+		assert.Equal(t, "Severity(9)", Severity(9).String())
+
+		data, err := json.Marshal(SeverityInfo)
+		require.NoError(t, err)
+		assert.JSONEq(t, `"info"`, string(data))
+
+		var s Severity
+		require.NoError(t, json.Unmarshal([]byte(`"critical"`), &s))
+		assert.Equal(t, SeverityCritical, s)
+
+		assert.Error(t, json.Unmarshal([]byte(`"loud"`), &s))
+		_, err = json.Marshal(Severity(0))
+		assert.Error(t, err)
+	})
+}
+
+func TestSeverityLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[Severity]slog.Level{
+		0:                slog.LevelError,
+		SeverityDebug:    slog.LevelDebug,
+		SeverityInfo:     slog.LevelInfo,
+		SeverityWarning:  slog.LevelWarn,
+		SeverityError:    slog.LevelError,
+		SeverityCritical: slog.LevelError + 4,
+	}
+	for severity, want := range tests {
+		assert.Equal(t, want, severity.Level(), severity.String())
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(Config{Deterministic: true}, Token())
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "code and severity",
+			err:      WithSeverity(WithCode(w.Wrap(errors.New("boom")), "billing.charge_failed"), SeverityCritical),
+			expected: `^\[billing\.charge_failed:critical\] errx\.TestToken \(severity_test\.go:NN\): boom$`,
+		},
+		{
+			name:     "code only",
+			err:      WithCode(w.Wrap(errors.New("boom")), "billing.charge_failed"),
+			expected: `^\[billing\.charge_failed:error\] errx\.TestToken`,
+		},
+		{
+			name:     "severity only",
+			err:      WithSeverity(w.Wrap(errors.New("boom")), SeverityWarning),
+			expected: `^\[:warning\] errx\.TestToken`,
+		},
+		{
+			name:     "neither",
+			err:      w.Wrap(errors.New("boom")),
+			expected: `^errx\.TestToken`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Regexp(t, tc.expected, tc.err.Error())
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithCode(Wrap(errors.New("boom")), "billing.charge_failed")
+		assert.NotContains(t, err.Error(), "[")
+	})
+}
+
+func TestLogWithLevel(t *testing.T) {
+	t.Parallel()
+
+	newLogger := func(buf *bytes.Buffer) *slog.Logger {
+		return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+	}
+
+	t.Run("logs at the error's level", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := WithSeverity(With(errors.New("cache miss"), "key", "user:42"), SeverityWarning)
+		err = WithCode(err, "cache.miss")
+		LogWithLevel(newLogger(&buf), err)
+
+		assert.Regexp(t, `^level=WARN msg="errx.TestLogWithLevel\.\S+ \(severity_test.go:\d+\): .*cache miss" id=\w{4}-\w{4} code=cache.miss key=user:42\n$`, buf.String())
+	})
+
+	t.Run("reports the caller as source", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+		LogWithLevel(logger, errors.New("disk full"))
+
+		assert.Regexp(t, `source=\S+/severity_test\.go:\d+ `, buf.String())
+	})
+
+	t.Run("defaults to the error level", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		LogWithLevel(newLogger(&buf), errors.New("disk full"))
+
+		assert.Equal(t, "level=ERROR msg=\"disk full\"\n", buf.String())
+	})
+
+	t.Run("skips disabled levels", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		LogWithLevel(newLogger(&buf), WithSeverity(errors.New("retrying"), SeverityDebug))
+		LogWithLevel(newLogger(&buf), nil)
+
+		assert.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,107 @@
+package errx
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// Sizes of the fixed parts of a chain, used by SizeOf.
+const (
+	extendedErrorSize = int(unsafe.Sizeof(extendedError{}))
+	contextFrameSize  = int(unsafe.Sizeof(contextFrame{}))
+	lazyMessageSize   = int(unsafe.Sizeof(lazyMessage{}))
+	attrSize          = int(unsafe.Sizeof(Attr{}))
+)
+
+// SizeOf estimates the bytes held by the frames and attributes of the errx
+// errors along err's chain, for deciding whether errors are cheap enough
+// to keep around, e.g. in job records. Strings the runtime shares, such as
+// function names, are counted as if each frame held its own copy, lazy
+// messages count once built, and attribute values count by their kind
+// only. The causes themselves are left out.
+// Returns 0 if err is nil.
+func SizeOf(err error) int {
+	var size int
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		if extErr, ok := err.(*extendedError); ok {
+			size += extErr.size()
+		}
+	}
+	return size
+}
+
+// size estimates the bytes held by e itself.
+func (e *extendedError) size() int {
+	size := extendedErrorSize + (cap(e.base)-len(e.base))*contextFrameSize
+	for _, frame := range e.base {
+		size += frame.size()
+	}
+
+	// frames added by re-wraps live in the errors that added them, which
+	// the chain keeps alive
+	for n := e.top; n != nil; n = n.next {
+		size += n.frame.size() - contextFrameSize
+		if n != &e.node {
+			size += extendedErrorSize
+		}
+	}
+
+	size += cap(e.attrs) * attrSize
+	for _, attr := range e.attrs {
+		size += len(attr.Key)
+		switch v := attr.Value.(type) {
+		case string:
+			size += len(v)
+		case contextFrame:
+			size += v.size()
+		}
+	}
+	return size
+}
+
+// size estimates the bytes held by f.
+func (f contextFrame) size() int {
+	size := contextFrameSize + len(f.funcName) + len(f.file) + len(f.args) + len(f.tags) + len(f.service)
+	if f.msg != nil {
+		size += lazyMessageSize
+		if f.msg.eager {
+			size += len(f.msg.msg)
+		}
+	}
+	return size
+}
+
+// SizeBudget sets the number of bytes past which the Wrapper's errors
+// have their frames compacted, see Config.SizeBudget.
+func SizeBudget(n int) Option {
+	return optionFunc(func(c *Config) {
+		c.SizeBudget = n
+	})
+}
+
+// compact drops frames from e until it fits in budget bytes: first the
+// ones found by scanning the stack, then the wrap sites between the
+// newest one and the origin. It never drops those two.
+func (e *extendedError) compact(budget int) {
+	size := e.size()
+	if size <= budget {
+		return
+	}
+
+	frames := e.frames()
+	kept := make([]contextFrame, 0, len(frames))
+	for _, frame := range frames {
+		if frame.wrapSite {
+			kept = append(kept, frame)
+		} else {
+			size -= frame.size()
+		}
+	}
+
+	for len(kept) > 2 && size > budget {
+		size -= kept[1].size()
+		kept = append(kept[:1], kept[2:]...)
+	}
+	e.top, e.depth, e.base = nil, 0, kept
+}
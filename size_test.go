@@ -0,0 +1,99 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeOf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no errx error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Zero(t, SizeOf(nil))
+		assert.Zero(t, SizeOf(errors.New("boom")))
+	})
+
+	t.Run("grows with frames and attributes", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.New("boom"))
+		base := SizeOf(err)
+		assert.Greater(t, base, len(Frames(err))*contextFrameSize)
+
+		rewrapped := Wrap(err)
+		assert.Greater(t, SizeOf(rewrapped), base)
+
+		withAttr := With(err, "request", strings.Repeat("x", 1000))
+		assert.GreaterOrEqual(t, SizeOf(withAttr), base+1000)
+	})
+
+	t.Run("counts built messages", func(t *testing.T) {
+		t.Parallel()
+
+		short := Wrapf(errors.New("boom"), "a")
+		long := Wrapf(errors.New("boom"), "%s", strings.Repeat("a", 1001))
+		assert.Equal(t, 1000, SizeOf(long)-SizeOf(short))
+
+		// lazy messages are only counted once someone built them,
+		// and building them isn't SizeOf's job
+		lazy := WrapLazy(errors.New("boom"), func() string { return strings.Repeat("a", 1001) })
+		assert.Less(t, SizeOf(lazy), SizeOf(long))
+	})
+
+	t.Run("sums nested errx errors", func(t *testing.T) {
+		t.Parallel()
+
+		inner := Wrap(errors.New("boom"))
+		outer := Wrap(fmt.Errorf("context: %w", inner))
+
+		assert.Equal(t, SizeOf(inner)+outer.(*extendedError).size(), SizeOf(outer))
+	})
+}
+
+func TestSizeBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops scanned frames first", func(t *testing.T) {
+		t.Parallel()
+
+		unbounded := Wrap(Wrap(errors.New("boom")))
+		require.Greater(t, len(Frames(unbounded)), 2)
+
+		w := NewWrapper(SizeBudget(1))
+		err := w.Wrap(errors.New("boom"))
+		err = w.Wrap(err)
+
+		frames := Frames(err)
+		require.Len(t, frames, 2)
+		assert.True(t, frames[0].WrapSite)
+		assert.True(t, frames[1].WrapSite)
+	})
+
+	t.Run("keeps the newest wrap site and the origin", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(SizeBudget(1))
+		origin := w.Wrapf(errors.New("boom"), "origin")
+		err := w.Wrapf(origin, "middle")
+		err = w.Wrapf(err, "newest")
+
+		frames := Frames(err)
+		require.Len(t, frames, 2)
+		assert.Equal(t, "newest", frames[0].Message)
+		assert.Equal(t, "origin", frames[1].Message)
+	})
+
+	t.Run("left alone within budget", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper(SizeBudget(1 << 20)).Wrap(errors.New("boom"))
+		assert.Greater(t, len(Frames(err)), 1)
+	})
+}
@@ -0,0 +1,90 @@
+package errx
+
+import "time"
+
+// skewMarker follows the frames in verbose output when their capture
+// times go backwards from the cause to the outermost wrap, which happens
+// when frames captured on machines with different clocks are chained.
+const skewMarker = "... frame times out of order: clocks may be skewed between services"
+
+// NormalizeClockSkew shifts the capture times of frames received from
+// other services in the Wrapper's %+v output, so remote frames end where
+// the local frame that received them begins. See Config.NormalizeSkew.
+func NormalizeClockSkew() Option {
+	return optionFunc(func(c *Config) {
+		c.NormalizeSkew = true
+	})
+}
+
+// clockOf identifies the clock that captured f: frames labeled with the
+// same service, or decoded from the same record, share one.
+type clockOf struct {
+	service string
+	remote  bool
+}
+
+func (f contextFrame) clock() clockOf {
+	return clockOf{service: f.service, remote: f.remote || f.service != ""}
+}
+
+// normalizeSkew offsets the capture times of each run of remote frames
+// so the latest of them matches the time of the frame right above the
+// run, the one that received them. Frames go from the newest to the
+// oldest; remote runs with no timed local frame above them are kept.
+// The durations between frames of the same run are preserved, while the
+// time the error spent between services is dropped.
+func normalizeSkew(frames []contextFrame) {
+	for end := len(frames); end > 0; {
+		clock := frames[end-1].clock()
+		start := end - 1
+		for start > 0 && frames[start-1].clock() == clock {
+			start--
+		}
+		if clock.remote && start > 0 {
+			shiftRun(frames[start:end], frames[start-1].time)
+		}
+		end = start
+	}
+}
+
+// shiftRun offsets the non-zero times of run so the latest one is at.
+func shiftRun(run []contextFrame, at time.Time) {
+	if at.IsZero() {
+		return
+	}
+
+	var latest time.Time
+	for _, frame := range run {
+		if frame.time.After(latest) {
+			latest = frame.time
+		}
+	}
+	if latest.IsZero() {
+		return
+	}
+
+	offset := at.Sub(latest)
+	for i := range run {
+		if !run[i].time.IsZero() {
+			run[i].time = run[i].time.Add(offset)
+		}
+	}
+}
+
+// skewed reports whether the capture times of frames, newest first, go
+// backwards somewhere from the oldest frame to the newest. Frames
+// without a time are ignored.
+func skewed(frames []contextFrame) bool {
+	var last time.Time
+	for i := len(frames) - 1; i >= 0; i-- {
+		t := frames[i].time
+		if t.IsZero() {
+			continue
+		}
+		if t.Before(last) {
+			return true
+		}
+		last = t
+	}
+	return false
+}
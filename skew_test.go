@@ -0,0 +1,73 @@
+package errx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSkew(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// the upstream service's clock runs an hour ahead of ours
+	remote := func() error {
+		return Record{
+			Message: "connection refused",
+			Frames: []Frame{
+				{Function: "billing.Charge", File: "charge.go", Line: 12, Time: base.Add(time.Hour), WrapSite: true},
+				{Function: "billing.dial", File: "dial.go", Line: 40, Time: base.Add(time.Hour - 200*time.Millisecond), WrapSite: true},
+			},
+		}.Err()
+	}
+
+	render := func(t *testing.T, opts ...Option) []string {
+		t.Helper()
+
+		opts = append([]Option{Config{Clock: func() time.Time { return base }}, FrameTimes(TimeElapsed)}, opts...)
+		w := NewWrapper(opts...)
+		return strings.Split(strings.TrimSuffix(fmt.Sprintf("%+v", w.Wrap(remote())), "\n"), "\n")
+	}
+
+	t.Run("warns about times going backwards", func(t *testing.T) {
+		t.Parallel()
+
+		lines := render(t)
+		require.Len(t, lines, 4)
+		assert.Regexp(t, `^\[0\] errx\.TestClockSkew\.func\d+ \(skew_test\.go:\d+\) \[\+0s\]: connection refused$`, lines[0])
+		assert.Equal(t, "[1] billing.Charge (charge.go:12) [+1h0m0s]: connection refused", lines[1])
+		assert.Equal(t, skewMarker, lines[3])
+	})
+
+	t.Run("normalizes remote frames to the boundary", func(t *testing.T) {
+		t.Parallel()
+
+		lines := render(t, NormalizeClockSkew())
+		require.Len(t, lines, 3)
+		assert.Regexp(t, `\[\+200ms\]: connection refused$`, lines[0])
+		assert.Equal(t, "[1] billing.Charge (charge.go:12) [+200ms]: connection refused", lines[1])
+		assert.Equal(t, "[2] billing.dial (dial.go:40) [+0s]: connection refused", lines[2])
+		assert.NotContains(t, lines, skewMarker)
+	})
+
+	t.Run("no warning when times are hidden", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper(Config{Clock: func() time.Time { return base }}).Wrap(remote())
+		assert.NotContains(t, fmt.Sprintf("%+v", err), skewMarker)
+	})
+
+	t.Run("frames keep their recorded times", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Clock: func() time.Time { return base }}, FrameTimes(TimeElapsed), NormalizeClockSkew())
+		err := w.Wrap(remote())
+		_ = fmt.Sprintf("%+v", err)
+		assert.Equal(t, base.Add(time.Hour), Frames(err)[1].Time)
+	})
+}
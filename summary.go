@@ -0,0 +1,81 @@
+package errx
+
+import "errors"
+
+// Summary returns a one-line description of err made of its origin frame
+// and root cause, e.g. "db.Query (db.go:42): connection refused".
+// It is meant for alert titles and metric labels, where the full chain
+// is too long. The origin is the first wrap site of the innermost errx
+// error in the chain; without one, only the root message is returned.
+// Returns an empty string if err is nil.
+func Summary(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	msg := Root(err).Error()
+	origin, c, ok := originOf(err)
+	if !ok {
+		return msg
+	}
+	return origin.location(c) + ": " + msg
+}
+
+// Origin returns the frame where err's chain started: the first wrap site
+// of the innermost errx error, as used by Summary. It reports false when
+// the chain holds no errx error.
+func Origin(err error) (Frame, bool) {
+	origin, _, ok := originOf(err)
+	if !ok {
+		return Frame{}, false
+	}
+	return origin.export(), true
+}
+
+// IsOrigin reports whether err comes straight from the first wrap of its
+// chain, the origin being its newest frame. OnWrap hooks use it to act
+// once per chain, rather than on every wrap. Errors without captured
+// frames, and errors wrapped again since, whether by errx or another
+// package, report false.
+func IsOrigin(err error) bool {
+	extErr, ok := err.(*extendedError)
+	if !ok || extErr.top != nil || len(extErr.base) == 0 || hasExtendedError(extErr.err) {
+		return false
+	}
+	origin, ok := extErr.origin()
+	return ok && origin == extErr.base[0]
+}
+
+// originOf returns the origin frame of err's chain along with the
+// configuration of the error holding it.
+func originOf(err error) (contextFrame, *Config, bool) {
+	var (
+		origin contextFrame
+		c      *Config
+		found  bool
+		chain  visited
+	)
+	for e := err; e != nil && chain.add(e); e = errors.Unwrap(e) {
+		if extErr, ok := e.(*extendedError); ok {
+			if frame, ok := extErr.origin(); ok {
+				origin, c, found = frame, extErr.config(), true
+			}
+		}
+	}
+	return origin, c, found
+}
+
+// origin returns the frame recorded by the first wrap of e.
+func (e *extendedError) origin() (contextFrame, bool) {
+	var (
+		frame contextFrame
+		found bool
+	)
+	for _, f := range e.frames() {
+		if !f.wrapSite {
+			break
+		}
+		frame, found = f, true
+	}
+	return frame, found
+}
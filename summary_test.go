@@ -0,0 +1,104 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	origin := func() error {
+		return Wrap(errors.New("connection refused"))
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: `^$`,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("connection refused"),
+			expected: `^connection refused$`,
+		},
+		{
+			name:     "single wrap",
+			err:      origin(),
+			expected: `^errx\.TestSummary\.func1 \(summary_test\.go:\d+\): connection refused$`,
+		},
+		{
+			name:     "re-wrapped",
+			err:      Wrap(Wrap(origin())),
+			expected: `^errx\.TestSummary\.func1 \(summary_test\.go:\d+\): connection refused$`,
+		},
+		{
+			name:     "manual context in between",
+			err:      Wrap(fmt.Errorf("loading user: %w", origin())),
+			expected: `^errx\.TestSummary\.func1 \(summary_test\.go:\d+\): connection refused$`,
+		},
+		{
+			name:     "attributes only",
+			err:      WithID(errors.New("connection refused"), "ID"),
+			expected: `^errx\.TestSummary \(summary_test\.go:\d+\): connection refused$`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Regexp(t, tc.expected, Summary(tc.err))
+		})
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no errx error", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := Origin(errors.New("connection refused"))
+		assert.False(t, ok)
+
+		_, ok = Origin(nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("innermost first wrap site", func(t *testing.T) {
+		t.Parallel()
+
+		origin := func() error {
+			return Wrapf(errors.New("connection refused"), "querying")
+		}
+		err := Wrap(fmt.Errorf("loading user: %w", Wrap(origin())))
+
+		frame, ok := Origin(err)
+		assert.True(t, ok)
+		assert.Equal(t, "github.com/alesr/errx.TestOrigin.func2.1", frame.Function)
+		assert.Equal(t, "querying", frame.Message)
+		assert.True(t, frame.WrapSite)
+	})
+}
+
+func TestIsOrigin(t *testing.T) {
+	t.Parallel()
+
+	err := Wrap(errors.New("connection refused"))
+	assert.True(t, IsOrigin(err))
+	assert.True(t, IsOrigin(WithCode(err, "db.unavailable")), "attributes don't add wraps")
+
+	assert.False(t, IsOrigin(Wrap(err)))
+	assert.False(t, IsOrigin(fmt.Errorf("loading user: %w", err)))
+	assert.False(t, IsOrigin(Wrap(fmt.Errorf("loading user: %w", err))))
+	assert.False(t, IsOrigin(errors.New("connection refused")))
+	assert.False(t, IsOrigin(nil))
+}
@@ -0,0 +1,43 @@
+package errx
+
+import "strings"
+
+// Tag is a short label passed to Wrap and stored on the frame it records,
+// e.g. "cache-miss-path", telling apart the branches of a function that
+// wrap in several places without relying on line numbers, which shift as
+// the code changes. Tags show in %+v output after their frame's location
+// as "#cache-miss-path", and in records.
+type Tag string
+
+// joinTags returns tags separated by commas, as frames store them.
+func joinTags(tags []Tag) string {
+	switch len(tags) {
+	case 0:
+		return ""
+	case 1:
+		return string(tags[0])
+	}
+
+	var b strings.Builder
+	for i, tag := range tags {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(string(tag))
+	}
+	return b.String()
+}
+
+// appendTags appends the tags of f to dst as "#tag", separated by spaces
+// and preceded by one when sep is set.
+func (f contextFrame) appendTags(dst []byte, sep bool) []byte {
+	for tag := range strings.SplitSeq(f.tags, ",") {
+		if sep {
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, TextTagPrefix...)
+		dst = append(dst, tag...)
+		sep = true
+	}
+	return dst
+}
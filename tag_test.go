@@ -0,0 +1,60 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTag(t *testing.T) {
+	t.Parallel()
+
+	w := NewWrapper(Config{Deterministic: true})
+	load := func(cached bool) error {
+		err := errors.New("timeout")
+		if cached {
+			return w.Wrap(err, "cache-hit-path")
+		}
+		return w.Wrap(err, "cache-miss-path", "cold")
+	}
+
+	t.Run("shown in verbose output", func(t *testing.T) {
+		t.Parallel()
+
+		lines := strings.Split(fmt.Sprintf("%+v", load(false)), "\n")
+		assert.Regexp(t, `^\[0\] errx\.TestTag\.func1 \(tag_test\.go:NN\) #cache-miss-path #cold: timeout$`, lines[0])
+
+		lines = strings.Split(fmt.Sprintf("%+v", load(true)), "\n")
+		assert.Regexp(t, `^\[0\] errx\.TestTag\.func1 \(tag_test\.go:NN\) #cache-hit-path: timeout$`, lines[0])
+	})
+
+	t.Run("left out of Error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotContains(t, load(false).Error(), "#")
+	})
+
+	t.Run("kept by records", func(t *testing.T) {
+		t.Parallel()
+
+		frames := Frames(load(false))
+		require.NotEmpty(t, frames)
+		assert.Equal(t, "cache-miss-path,cold", frames[0].Tags)
+
+		data, err := Encode(load(false))
+		require.NoError(t, err)
+		decoded, err := Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, "cache-miss-path,cold", Frames(decoded)[0].Tags)
+	})
+
+	t.Run("untagged", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Frames(Wrap(errors.New("boom")))[0].Tags)
+	})
+}
@@ -0,0 +1,50 @@
+package errx
+
+import "time"
+
+// ErrorEvent describes a wrap, as streamed by Tee.
+type ErrorEvent struct {
+	// Err is the result of the wrap.
+	Err error
+	// Frames and Attrs are Err's frames and attributes at the time of
+	// the wrap, as returned by Frames and Attrs.
+	Frames []Frame
+	Attrs  []Attr
+	// Time is when the wrap happened, as recorded on its frame.
+	Time time.Time
+}
+
+// Tee streams every wrap from now on to ch, for live debugging views or
+// custom aggregation. Events are sent without blocking: when ch is full,
+// they are dropped, so give it a buffer sized for bursts and keep
+// receiving. Built on OnWrap, it costs every wrap the extraction of the
+// frames and attributes. The returned function stops streaming; ch is
+// not closed.
+func Tee(ch chan<- ErrorEvent) (remove func()) {
+	return OnWrap(func(err error) {
+		event := ErrorEvent{
+			Err:    err,
+			Frames: Frames(err),
+			Attrs:  Attrs(err),
+			Time:   wrapTime(err),
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+}
+
+// wrapTime returns when err, the result of a wrap, was wrapped: the
+// capture time of its newest frame, or now when it has none.
+func wrapTime(err error) time.Time {
+	if extErr, ok := err.(*extendedError); ok {
+		switch {
+		case extErr.top != nil:
+			return extErr.top.frame.time
+		case len(extErr.base) > 0:
+			return extErr.base[0].time
+		}
+	}
+	return time.Now()
+}
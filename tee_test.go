@@ -0,0 +1,57 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWrapper(Config{Clock: func() time.Time { return clock }})
+
+	cause := With(errors.New("disk full"), "volume", "data")
+
+	events := make(chan ErrorEvent, 2)
+	remove := Tee(events)
+	defer remove()
+
+	first := w.Wrap(cause)
+	clock = clock.Add(time.Second)
+	second := w.Wrap(first)
+
+	t.Run("streams each wrap", func(t *testing.T) {
+		require.Len(t, events, 2)
+
+		event := <-events
+		assert.Same(t, first, event.Err)
+		assert.Equal(t, Frames(first), event.Frames)
+		assert.Equal(t, []Attr{{Key: "volume", Value: "data"}}, event.Attrs)
+
+		event = <-events
+		assert.Same(t, second, event.Err)
+		assert.Equal(t, clock, event.Time)
+	})
+
+	t.Run("drops events when full", func(t *testing.T) {
+		full := make(chan ErrorEvent)
+		removeFull := Tee(full)
+		defer removeFull()
+
+		assert.NotPanics(t, func() { _ = Wrap(errors.New("disk full")) })
+		assert.Empty(t, full)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		for range len(events) {
+			<-events
+		}
+		remove()
+		_ = Wrap(errors.New("disk full"))
+
+		assert.Empty(t, events)
+	})
+}
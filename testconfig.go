@@ -0,0 +1,29 @@
+package errx
+
+// testConfigEnv is set for the duration of the tests using WithTestConfig.
+const testConfigEnv = "ERRX_TEST_CONFIG"
+
+// TestingT is the part of *testing.T and *testing.B used by WithTestConfig.
+type TestingT interface {
+	Helper()
+	Setenv(key, value string)
+	Cleanup(func())
+}
+
+// WithTestConfig sets the package-wide configuration to c for the rest of
+// t, and restores the previous one when t and its subtests are done.
+// Since the configuration is shared by every goroutine, it enforces what
+// keeps other tests from seeing c: like t.Setenv, it fails tests that run
+// in parallel or have parallel ancestors, and keeps t and its subtests
+// from calling t.Parallel afterwards. Tests running in parallel only
+// start once the sequential ones are done, so they never observe c.
+func WithTestConfig(t TestingT, c Config) {
+	t.Helper()
+
+	t.Setenv(testConfigEnv, "1")
+	prev := globalConfig.Load()
+	SetConfig(c)
+	t.Cleanup(func() {
+		globalConfig.Store(prev)
+	})
+}
@@ -0,0 +1,126 @@
+package errx
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The separators of the text format Error renders. With the default
+// Config, the format reads:
+//
+//	error    = [token] { segment TextSeparator } cause
+//	token    = "[" [code] ":" severity "] "
+//	segment  = frame | omitted | message
+//	frame    = ["[" service "] "] function " (" file TextLineSeparator line ")"
+//	           { " " TextTagPrefix tag } [" [" time "]"] [TextSeparator message]
+//	omitted  = "… " count " frames omitted …"
+//
+// where function is the name shortened to its package, file the base
+// name, line a number or TextDeterministicLine, and cause the rendering
+// of the root cause. The token only shows with Config.Token and the time
+// with FieldTime in Config.ErrorFields. Other ErrorFields leave parts of
+// frames out.
+const (
+	// TextSeparator separates frames from each other, from their
+	// message and from the cause.
+	TextSeparator = ": "
+	// TextLineSeparator separates a frame's file from its line.
+	TextLineSeparator = ":"
+	// TextTagPrefix starts each tag following a frame's location.
+	TextTagPrefix = "#"
+	// TextDeterministicLine stands for line numbers in deterministic mode.
+	TextDeterministicLine = "NN"
+)
+
+var (
+	textToken = regexp.MustCompile(`^\[([^\s:\]]*):([a-z]+)\] `)
+	textFrame = regexp.MustCompile(`^(?:\[([^\]]+)\] )?(\S+) \(([^\s()]+)` + TextLineSeparator +
+		`(\d+|` + TextDeterministicLine + `)\)((?: ` + TextTagPrefix + `[^\s:]+)*)(?: \[([^\]]+)\])?(?:` + TextSeparator + `|$)`)
+	textOmitted = regexp.MustCompile(`^… \d+ frames omitted …(?:` + TextSeparator + `|$)`)
+)
+
+// ParseError reconstructs what it can of a chain from its Error output
+// in the text format above, for tooling that only has plain-text logs:
+// the code and severity of the token, the frames, and the message of the
+// root cause. Frames keep the shortened function names and file base
+// names they were rendered with, and their time when it was rendered as
+// a timestamp. Text between two frames is taken as the message of the
+// first one; text after the last frame is taken as the cause, so the
+// message of the outermost wrap site, if any, ends up in it. Frames
+// rendered with fewer fields and omitted frames are skipped.
+// Calling Err on the result rebuilds an error rendering like s.
+func ParseError(s string) Record {
+	r := Record{Error: s}
+
+	rest := s
+	if m := textToken.FindStringSubmatch(rest); m != nil {
+		var severity Severity
+		if severity.UnmarshalText([]byte(m[2])) == nil {
+			r.Code = Code(m[1])
+			r.Severity = severity
+			rest = rest[len(m[0]):]
+		}
+	}
+
+	for rest != "" {
+		if m := textOmitted.FindString(rest); m != "" {
+			rest = rest[len(m):]
+			continue
+		}
+		if m := textFrame.FindStringSubmatch(rest); m != nil {
+			r.Frames = append(r.Frames, parseFrame(m))
+			rest = rest[len(m[0]):]
+			continue
+		}
+
+		// text up to the next frame is the message of the last one, text
+		// after the last frame the cause
+		next := nextSegment(rest)
+		if next < 0 {
+			break
+		}
+		if n := len(r.Frames); n > 0 && r.Frames[n-1].Message == "" {
+			r.Frames[n-1].Message = rest[:next]
+			r.Frames[n-1].WrapSite = true
+		}
+		rest = rest[next+len(TextSeparator):]
+	}
+	r.Message = rest
+	return r
+}
+
+// nextSegment returns the index of the first separator in s followed by
+// a frame or an omitted marker, or -1 if there is none.
+func nextSegment(s string) int {
+	for i := 0; ; {
+		j := strings.Index(s[i:], TextSeparator)
+		if j < 0 {
+			return -1
+		}
+		i += j
+		after := s[i+len(TextSeparator):]
+		if textFrame.MatchString(after) || textOmitted.MatchString(after) {
+			return i
+		}
+		i += len(TextSeparator)
+	}
+}
+
+// parseFrame converts the submatches of textFrame into a frame.
+func parseFrame(m []string) Frame {
+	frame := Frame{
+		Service:  m[1],
+		Function: m[2],
+		File:     m[3],
+	}
+	frame.Line, _ = strconv.Atoi(m[4])
+	if m[5] != "" {
+		frame.Tags = strings.ReplaceAll(strings.TrimPrefix(m[5], " "+TextTagPrefix), " "+TextTagPrefix, ",")
+	}
+	if t, err := time.Parse(time.RFC3339Nano, m[6]); err == nil {
+		frame.Time = t
+	}
+	return frame
+}
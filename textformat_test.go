@@ -0,0 +1,75 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rendered chain", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Depth: 1, Token: true})
+		err := w.Wrap(errors.New("connection refused: dial tcp"))
+		err = w.Wrapf(err, "loading invoice %d", 42)
+		err = WithCode(err, "billing.invoice.unavailable")
+		err = w.Wrap(err)
+
+		r := ParseError(err.Error())
+		assert.Equal(t, err.Error(), r.Error)
+		assert.Equal(t, Code("billing.invoice.unavailable"), r.Code)
+		assert.Equal(t, SeverityError, r.Severity)
+		assert.Equal(t, "connection refused: dial tcp", r.Message)
+
+		want := Frames(err)
+		require.Len(t, r.Frames, len(want))
+		for i, frame := range r.Frames {
+			assert.Equal(t, shortenFuncName(want[i].Function), frame.Function)
+			assert.Equal(t, "textformat_test.go", frame.File)
+			assert.Equal(t, want[i].Line, frame.Line)
+		}
+		assert.Equal(t, "loading invoice 42", r.Frames[1].Message)
+
+		rendered, ok := strings.CutPrefix(err.Error(), "[billing.invoice.unavailable:error] ")
+		require.True(t, ok)
+		assert.Equal(t, rendered, r.Err().Error())
+	})
+
+	t.Run("tags, timestamps and services", func(t *testing.T) {
+		t.Parallel()
+
+		r := ParseError("[billing] db.Query (query.go:17) #replica #retry [2026-03-01T12:00:00Z]: api.Get (get.go:" + TextDeterministicLine + "): timeout")
+		assert.Equal(t, []Frame{
+			{Service: "billing", Function: "db.Query", File: "query.go", Line: 17, Tags: "replica,retry", Time: time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)},
+			{Function: "api.Get", File: "get.go"},
+		}, r.Frames)
+		assert.Equal(t, "timeout", r.Message)
+	})
+
+	t.Run("omitted frames and prefixes", func(t *testing.T) {
+		t.Parallel()
+
+		r := ParseError("syncing: a.F (a.go:1): … 3 frames omitted …: b.G (b.go:2): EOF")
+		require.Len(t, r.Frames, 2)
+		assert.Empty(t, r.Frames[0].Message)
+		assert.Equal(t, "EOF", r.Message)
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		t.Parallel()
+
+		r := ParseError("[not a token] something: went wrong")
+		assert.Empty(t, r.Frames)
+		assert.Empty(t, r.Code)
+		assert.Equal(t, "[not a token] something: went wrong", r.Message)
+		assert.Equal(t, fmt.Sprint(r.Err()), r.Message)
+	})
+}
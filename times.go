@@ -0,0 +1,79 @@
+package errx
+
+import (
+	"time"
+)
+
+// TimeFormat selects how %+v output shows when frames were captured.
+type TimeFormat int
+
+const (
+	// TimeHidden leaves capture times out. It is the default.
+	TimeHidden TimeFormat = iota
+	// TimeAbsolute shows capture times as RFC3339 timestamps, in UTC
+	// on builds with the tinygo tag.
+	TimeAbsolute
+	// TimeAgo shows how long before now each frame was captured,
+	// e.g. "3.2s ago".
+	TimeAgo
+	// TimeElapsed shows how long after the earliest frame each frame
+	// was captured, e.g. "+120ms".
+	TimeElapsed
+)
+
+// FrameTimes sets how the Wrapper's errors show capture times in %+v output.
+func FrameTimes(f TimeFormat) Option {
+	return optionFunc(func(c *Config) {
+		c.TimeFormat = f
+	})
+}
+
+// frameTime renders t under c's TimeFormat, relative to the earliest
+// capture time first where needed. It returns an empty string when times
+// are hidden, which deterministic mode always does.
+func (c *Config) frameTime(t, first time.Time) string {
+	return c.formatTime(c.TimeFormat, t, first)
+}
+
+// formatTime renders t under format, like frameTime.
+func (c *Config) formatTime(format TimeFormat, t, first time.Time) string {
+	if c.Deterministic || t.IsZero() {
+		return ""
+	}
+
+	switch format {
+	case TimeAbsolute:
+		return absoluteTime(t)
+	case TimeAgo:
+		return roundDuration(c.now().Sub(t)).String() + " ago"
+	case TimeElapsed:
+		return "+" + roundDuration(t.Sub(first)).String()
+	default:
+		return ""
+	}
+}
+
+// earliest returns the earliest non-zero capture time among frames.
+func earliest(frames []contextFrame) time.Time {
+	var first time.Time
+	for _, frame := range frames {
+		if !frame.time.IsZero() && (first.IsZero() || frame.time.Before(first)) {
+			first = frame.time
+		}
+	}
+	return first
+}
+
+// roundDuration trims d to a precision a human reading it cares about.
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Minute:
+		return d.Round(time.Second)
+	case d >= time.Second:
+		return d.Round(100 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
@@ -0,0 +1,119 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeFormat(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// the Wrapper's clock reads *now, both when wrapping and when printing
+	newWrapper := func(format TimeFormat, now *time.Time) *Wrapper {
+		return NewWrapper(Config{
+			Clock:      func() time.Time { return *now },
+			TimeFormat: format,
+			Depth:      1,
+		})
+	}
+
+	testCases := []struct {
+		name     string
+		format   TimeFormat
+		expected []string
+	}{
+		{
+			name:   "hidden",
+			format: TimeHidden,
+			expected: []string{
+				`^\[0\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\): retrying: boom$`,
+				`^\[1\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\): boom$`,
+			},
+		},
+		{
+			name:   "absolute",
+			format: TimeAbsolute,
+			expected: []string{
+				`^\[0\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[2024-05-01T12:00:01Z\]: retrying: boom$`,
+				`^\[1\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[2024-05-01T12:00:00Z\]: boom$`,
+			},
+		},
+		{
+			name:   "ago",
+			format: TimeAgo,
+			expected: []string{
+				`^\[0\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[2\.1s ago\]: retrying: boom$`,
+				`^\[1\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[3\.2s ago\]: boom$`,
+			},
+		},
+		{
+			name:   "elapsed",
+			format: TimeElapsed,
+			expected: []string{
+				`^\[0\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[\+1\.1s\]: retrying: boom$`,
+				`^\[1\] errx\.TestTimeFormat\.func2 \(times_test\.go:\d+\) \[\+0s\]: boom$`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := base
+			w := newWrapper(tc.format, &now)
+
+			err := w.Wrap(errors.New("boom"))
+			now = base.Add(1123 * time.Millisecond)
+			err = w.Wrapf(err, "retrying")
+			now = base.Add(3234 * time.Millisecond)
+
+			lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+			for i, expected := range tc.expected {
+				assert.Regexp(t, expected, lines[i])
+			}
+		})
+	}
+
+	t.Run("hidden in deterministic mode", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Deterministic: true, TimeFormat: TimeAbsolute})
+		assert.NotContains(t, fmt.Sprintf("%+v", w.Wrap(errors.New("boom"))), "1970")
+	})
+
+	t.Run("compact output unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper(FrameTimes(TimeAbsolute)).Wrap(errors.New("boom"))
+		assert.NotContains(t, err.Error(), "[")
+	})
+}
+
+func TestRoundDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		given    time.Duration
+		expected string
+	}{
+		{given: 1500 * time.Nanosecond, expected: "2µs"},
+		{given: 120*time.Millisecond + 400*time.Microsecond, expected: "120ms"},
+		{given: 3249 * time.Millisecond, expected: "3.2s"},
+		{given: 90*time.Second + 600*time.Millisecond, expected: "1m31s"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, roundDuration(tc.given).String())
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package errx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// traceEvent is an event of the Trace Event format read by Perfetto and
+// chrome://tracing.
+type traceEvent struct {
+	Name string `json:"name"`
+	Cat  string `json:"cat,omitempty"`
+	// Ph is the phase: "X" for slices, "i" for instants, "M" for metadata.
+	Ph string `json:"ph"`
+	// TS and Dur are in microseconds.
+	TS   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	PID  int            `json:"pid"`
+	TID  int            `json:"tid"`
+	S    string         `json:"s,omitempty"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// traceBranch is a branch of an error tree: the wrap sites along one
+// chain, up to its root cause or the join it ends in.
+type traceBranch struct {
+	name   string
+	frames []Frame
+	// cause is the root cause, nil when the branch ends in a join
+	cause error
+}
+
+// WriteTrace writes err's chain as Trace Event JSON, to be opened in
+// Perfetto (ui.perfetto.dev) or chrome://tracing as a timeline of the
+// path to the failure. Each wrap site is a slice starting when it was
+// captured and ending with the last capture, so slices stack up as the
+// error travels up the calls; the root cause is an instant at the start.
+// Errors joined with errors.Join get a track each, under the one of the
+// chain joining them. Frames captured without a time, such as imported
+// ones, are left out.
+func WriteTrace(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		branches []traceBranch
+		chain    visited
+	)
+	var walk func(err error, name string)
+	walk = func(err error, name string) {
+		b := traceBranch{name: name}
+		i := len(branches)
+		branches = append(branches, b)
+		for err != nil && chain.add(err) {
+			b.cause = err
+			switch x := err.(type) {
+			case *extendedError:
+				for _, frame := range x.dedupedFrames() {
+					if frame.wrapSite && !frame.time.IsZero() {
+						b.frames = append(b.frames, frame.export())
+					}
+				}
+				err = x.err
+			case interface{ Unwrap() error }:
+				err = x.Unwrap()
+			case interface{ Unwrap() []error }:
+				b.cause = nil
+				for j, joined := range x.Unwrap() {
+					walk(joined, name+"."+strconv.Itoa(j+1))
+				}
+				err = nil
+			default:
+				err = nil
+			}
+		}
+		branches[i] = b
+	}
+	walk(err, "error")
+
+	// slices end with the last capture of the whole tree
+	var first, last time.Time
+	for _, b := range branches {
+		for _, frame := range b.frames {
+			if first.IsZero() || frame.Time.Before(first) {
+				first = frame.Time
+			}
+			if frame.Time.After(last) {
+				last = frame.Time
+			}
+		}
+	}
+
+	process := "error"
+	if id := ID(err); id != "" {
+		process += " " + id
+	}
+	events := []traceEvent{{Name: "process_name", Ph: "M", PID: 1, Args: map[string]any{"name": process}}}
+	for i, b := range branches {
+		tid := i + 1
+		events = append(events, traceEvent{Name: "thread_name", Ph: "M", PID: 1, TID: tid, Args: map[string]any{"name": b.name}})
+
+		// innermost first, the order they happened in
+		slices.SortStableFunc(b.frames, func(x, y Frame) int { return x.Time.Compare(y.Time) })
+		for _, frame := range b.frames {
+			args := map[string]any{"function": frame.Function, "file": frame.File, "line": frame.Line}
+			if frame.Message != "" {
+				args["message"] = frame.Message
+			}
+			events = append(events, traceEvent{
+				Name: frame.String(),
+				Cat:  "wrap",
+				Ph:   "X",
+				TS:   frame.Time.UnixMicro(),
+				// at least a microsecond, or the slice doesn't show
+				Dur:  max(last.Sub(frame.Time).Microseconds(), 1),
+				PID:  1,
+				TID:  tid,
+				Args: args,
+			})
+		}
+
+		if b.cause != nil {
+			at := first
+			if len(b.frames) > 0 {
+				at = b.frames[0].Time
+			}
+			events = append(events, traceEvent{
+				Name: b.cause.Error(),
+				Cat:  "cause",
+				Ph:   "i",
+				TS:   at.UnixMicro(),
+				PID:  1,
+				TID:  tid,
+				S:    "t",
+			})
+		}
+	}
+
+	data, encErr := json.Marshal(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"})
+	if encErr != nil {
+		return fmt.Errorf("could not encode trace: %w", encErr)
+	}
+	if _, wErr := w.Write(data); wErr != nil {
+		return fmt.Errorf("could not write trace: %w", wErr)
+	}
+	return nil
+}
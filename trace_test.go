@@ -0,0 +1,125 @@
+package errx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTrace(t *testing.T) {
+	t.Parallel()
+
+	// newWrapper returns a Wrapper whose clock moves 10ms per capture
+	newWrapper := func() *Wrapper {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		return NewWrapper(Config{Clock: func() time.Time {
+			now = now.Add(10 * time.Millisecond)
+			return now
+		}})
+	}
+
+	decode := func(t *testing.T, err error) []traceEvent {
+		t.Helper()
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteTrace(&buf, err))
+
+		var trace struct {
+			TraceEvents     []traceEvent `json:"traceEvents"`
+			DisplayTimeUnit string       `json:"displayTimeUnit"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+		assert.Equal(t, "ms", trace.DisplayTimeUnit)
+		return trace.TraceEvents
+	}
+
+	// byPhase returns the events of phase ph
+	byPhase := func(events []traceEvent, ph string) []traceEvent {
+		var matching []traceEvent
+		for _, e := range events {
+			if e.Ph == ph {
+				matching = append(matching, e)
+			}
+		}
+		return matching
+	}
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		assert.NoError(t, WriteTrace(&buf, nil))
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("wrap sites stack up to the last capture", func(t *testing.T) {
+		t.Parallel()
+
+		w := newWrapper()
+		err := w.Wrap(errors.New("connection refused"))
+		err = w.Wrapf(err, "loading user")
+
+		events := decode(t, err)
+
+		slices := byPhase(events, "X")
+		require.Len(t, slices, 2)
+		assert.Equal(t, int64(10_000), slices[0].Dur)
+		assert.Equal(t, int64(1), slices[1].Dur, "the last capture still shows")
+		assert.Equal(t, slices[0].TS+10_000, slices[1].TS)
+		assert.Equal(t, "wrap", slices[0].Cat)
+		assert.Equal(t, "loading user", slices[1].Args["message"])
+
+		instants := byPhase(events, "i")
+		require.Len(t, instants, 1)
+		assert.Equal(t, "connection refused", instants[0].Name)
+		assert.Equal(t, slices[0].TS, instants[0].TS)
+
+		meta := byPhase(events, "M")
+		require.Len(t, meta, 2)
+		assert.Equal(t, "error "+ID(err), meta[0].Args["name"])
+		assert.Equal(t, "error", meta[1].Args["name"])
+	})
+
+	t.Run("joined errors get a track each", func(t *testing.T) {
+		t.Parallel()
+
+		w := newWrapper()
+		joined := errors.Join(w.Wrap(errors.New("db down")), w.Wrap(errors.New("cache down")))
+		err := w.Wrap(fmt.Errorf("checkout: %w", joined))
+
+		events := decode(t, err)
+
+		var tracks []string
+		for _, e := range byPhase(events, "M")[1:] {
+			tracks = append(tracks, e.Args["name"].(string))
+		}
+		assert.Equal(t, []string{"error", "error.1", "error.2"}, tracks)
+
+		instants := byPhase(events, "i")
+		require.Len(t, instants, 2)
+		assert.Equal(t, "db down", instants[0].Name)
+		assert.Equal(t, 2, instants[0].TID)
+		assert.Equal(t, "cache down", instants[1].Name)
+		assert.Equal(t, 3, instants[1].TID)
+
+		// every slice ends with the outermost wrap
+		slices := byPhase(events, "X")
+		require.Len(t, slices, 3)
+		end := slices[0].TS
+		for _, s := range slices[1:] {
+			assert.Equal(t, end, s.TS+s.Dur)
+		}
+	})
+
+	t.Run("reports write errors", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorContains(t, WriteTrace(failingWriter{}, Wrap(errors.New("boom"))), "could not write trace")
+	})
+}
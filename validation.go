@@ -0,0 +1,92 @@
+package errx
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const keyFieldError = "errx.field_error"
+
+// ErrValidation is the cause of the errors WithFieldError starts from nil.
+var ErrValidation = errors.New("validation failed")
+
+// fieldError is a message recorded against an input field.
+type fieldError struct {
+	field, msg string
+}
+
+// WithFieldError records that the input field failed validation with
+// msg, e.g. WithFieldError(err, "email", "must be a valid address"), and
+// sets err's kind to KindInvalid unless it has one. Calls accumulate, so
+// a validation pass can collect every problem before returning:
+//
+//	var err error
+//	if req.Email == "" {
+//	    err = errx.WithFieldError(err, "email", "is required")
+//	}
+//	if req.Age < 0 {
+//	    err = errx.WithFieldError(err, "age", "must be positive")
+//	}
+//	return err
+//
+// When err is nil, the error starts as a wrapped ErrValidation. If err is
+// not an errx error yet it is wrapped first as if by Wrap.
+func WithFieldError(err error, field, msg string) error {
+	if err == nil {
+		err = ErrValidation
+	}
+
+	extErr := withAttr(err, Attr{Key: keyFieldError, Value: fieldError{field: field, msg: msg}}, 3)
+	if KindOf(extErr) == "" {
+		extErr = extErr.withAttr(Attr{Key: keyKind, Value: KindInvalid})
+	}
+	return extErr
+}
+
+// FieldErrors returns the messages recorded with WithFieldError along
+// err's chain, keyed by field. Messages recorded more than once for a
+// field are joined with "; ", innermost first.
+// Returns nil if there are none.
+func FieldErrors(err error) map[string]string {
+	var layers []*extendedError
+	var chain visited
+	for ; err != nil && chain.add(err); err = errors.Unwrap(err) {
+		if extErr, ok := err.(*extendedError); ok {
+			layers = append(layers, extErr)
+		}
+	}
+
+	var fields map[string]string
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, attr := range layers[i].attrs {
+			fe, ok := attr.Value.(fieldError)
+			if !ok || attr.Key != keyFieldError {
+				continue
+			}
+			if fields == nil {
+				fields = make(map[string]string)
+			}
+			if prev, ok := fields[fe.field]; ok {
+				fields[fe.field] = prev + "; " + fe.msg
+			} else {
+				fields[fe.field] = fe.msg
+			}
+		}
+	}
+	return fields
+}
+
+// formatFieldErrors renders fields sorted by name, as
+// "age: must be positive, email: is required".
+func formatFieldErrors(fields map[string]string) string {
+	var b strings.Builder
+	for i, field := range slices.Sorted(maps.Keys(fields)) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(field + ": " + fields[field])
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accumulated from nil", func(t *testing.T) {
+		t.Parallel()
+
+		var err error
+		err = WithFieldError(err, "email", "must be a valid address")
+		err = WithFieldError(err, "age", "must be positive")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Equal(t, KindInvalid, KindOf(err))
+		assert.Equal(t, map[string]string{
+			"email": "must be a valid address",
+			"age":   "must be positive",
+		}, FieldErrors(err))
+		assert.Empty(t, Attrs(err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "\nfields: age: must be positive, email: must be a valid address\n")
+	})
+
+	t.Run("repeated fields are joined innermost first", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithFieldError(errors.New("bad signup"), "password", "is too short")
+		err = Wrap(fmt.Errorf("signing up: %w", err))
+		err = WithFieldError(err, "password", "must contain a digit")
+
+		assert.Equal(t, map[string]string{"password": "is too short; must contain a digit"}, FieldErrors(err))
+	})
+
+	t.Run("kind already set", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithKind(errors.New("conflict"), KindConflict)
+		err = WithFieldError(err, "name", "is taken")
+
+		assert.Equal(t, KindConflict, KindOf(err))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, FieldErrors(nil))
+		assert.Nil(t, FieldErrors(Wrap(errors.New("plain"))))
+	})
+
+	t.Run("survive records", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithFieldError(nil, "email", "is required")
+		err = WithFieldError(err, "age", "must be positive")
+
+		data, encErr := Encode(err)
+		require.NoError(t, encErr)
+		assert.Contains(t, string(data), `"field_errors":{"age":"must be positive","email":"is required"}`)
+
+		decoded, decErr := Decode(data)
+		require.NoError(t, decErr)
+		assert.Equal(t, FieldErrors(err), FieldErrors(decoded))
+		assert.Contains(t, fmt.Sprintf("%#v", err), `FieldErrors: map[string]string{"age":"must be positive", "email":"is required"},`)
+	})
+}
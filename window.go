@@ -0,0 +1,31 @@
+package errx
+
+import "strconv"
+
+// FrameWindow makes the Wrapper's errors show only their first head and
+// last tail frames, see Config.HeadFrames.
+func FrameWindow(head, tail int) Option {
+	return optionFunc(func(c *Config) {
+		c.HeadFrames = head
+		c.TailFrames = tail
+	})
+}
+
+// appendOmitted appends the marker standing for the k frames left out
+// between the head and the tail of a windowed chain.
+func appendOmitted(dst []byte, k int) []byte {
+	dst = append(dst, "… "...)
+	dst = strconv.AppendInt(dst, int64(k), 10)
+	return append(dst, " frames omitted …"...)
+}
+
+// window returns the number of leading frames shown out of n, and the
+// index where the trailing ones start. Both are n when nothing is left
+// out.
+func (c *Config) window(n int) (head, tail int) {
+	h, t := max(c.HeadFrames, 0), max(c.TailFrames, 0)
+	if h+t == 0 || n <= h+t {
+		return n, n
+	}
+	return h, n - t
+}
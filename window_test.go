@@ -0,0 +1,55 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameWindow(t *testing.T) {
+	t.Parallel()
+
+	wrapSteps := func(w *Wrapper, n int) error {
+		err := errors.New("boom")
+		for i := range n {
+			err = w.Wrapf(err, "step %d", i)
+		}
+		return err
+	}
+
+	t.Run("keeps both ends of long chains", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Deterministic: true}, FrameWindow(2, 1))
+		err := wrapSteps(w, 6)
+
+		msg := err.Error()
+		assert.Contains(t, msg, "step 5: ")
+		assert.Contains(t, msg, "step 4: … 3 frames omitted …: ")
+		assert.True(t, strings.HasSuffix(msg, "step 0: boom"), msg)
+		assert.NotContains(t, msg, "step 2")
+
+		verbose := fmt.Sprintf("%+v", err)
+		assert.Contains(t, verbose, "step 4: boom\n… 3 frames omitted …\n[5] ")
+		assert.NotContains(t, verbose, "[2]")
+		assert.Equal(t, "step 3", Frames(err)[2].Message, "windowing only changes the output")
+	})
+
+	t.Run("short chains are shown whole", func(t *testing.T) {
+		t.Parallel()
+
+		err := wrapSteps(NewWrapper(Config{Deterministic: true}, FrameWindow(2, 1)), 3)
+		assert.NotContains(t, err.Error(), "omitted")
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "omitted")
+	})
+
+	t.Run("head only", func(t *testing.T) {
+		t.Parallel()
+
+		err := wrapSteps(NewWrapper(Config{Deterministic: true}, FrameWindow(1, 0)), 4)
+		assert.Contains(t, err.Error(), "step 3: … 3 frames omitted …: boom")
+	})
+}
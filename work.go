@@ -0,0 +1,59 @@
+package errx
+
+// Attribute keys used for the unit of work an error happened in. The
+// HTTP ones follow the OpenTelemetry semantic conventions.
+const (
+	AttrJobQueue      = "job.queue"
+	AttrJobID         = "job.id"
+	AttrHTTPMethod    = "http.request.method"
+	AttrHTTPRoute     = "http.route"
+	AttrHTTPRequestID = "http.request.id"
+)
+
+// WithJob records that err happened while processing job jobID taken from
+// queue, under the keys AttrJobQueue and AttrJobID. Empty values are left
+// out. If err is not an errx error yet it is wrapped first as if by Wrap.
+// Returns nil if err is nil.
+func WithJob(err error, queue, jobID string) error {
+	if err == nil {
+		return nil
+	}
+	return withWork(err, Attr{Key: AttrJobQueue, Value: queue}, Attr{Key: AttrJobID, Value: jobID})
+}
+
+// WithRequest records that err happened while serving the HTTP request
+// requestID, matched by route with method, under the keys AttrHTTPMethod,
+// AttrHTTPRoute and AttrHTTPRequestID. route is the pattern, such as
+// "/invoices/{id}", not the path, so errors group by endpoint. Empty
+// values are left out. If err is not an errx error yet it is wrapped
+// first as if by Wrap.
+// Returns nil if err is nil.
+func WithRequest(err error, method, route, requestID string) error {
+	if err == nil {
+		return nil
+	}
+	return withWork(err,
+		Attr{Key: AttrHTTPMethod, Value: method},
+		Attr{Key: AttrHTTPRoute, Value: route},
+		Attr{Key: AttrHTTPRequestID, Value: requestID},
+	)
+}
+
+// withWork attaches the attrs with a non-empty value to err, wrapping it
+// for the caller of its caller when it isn't an errx error yet.
+func withWork(err error, attrs ...Attr) error {
+	extErr, ok := err.(*extendedError)
+	if !ok {
+		if extErr, ok = wrap(nil, err, 3, contextFrame{}).(*extendedError); !ok {
+			extErr = &extendedError{err: err}
+		}
+	}
+
+	kept := attrs[:0]
+	for _, attr := range attrs {
+		if attr.Value != "" {
+			kept = append(kept, attr)
+		}
+	}
+	return extErr.withAttr(kept...)
+}
@@ -0,0 +1,47 @@
+package errx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, WithJob(nil, "emails", "42"))
+		assert.Nil(t, WithRequest(nil, "GET", "/invoices/{id}", "req-1"))
+	})
+
+	t.Run("job attributes", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithJob(errors.New("smtp timeout"), "emails", "job-42")
+
+		assert.Equal(t, []Attr{
+			{Key: AttrJobQueue, Value: "emails"},
+			{Key: AttrJobID, Value: "job-42"},
+		}, Attrs(err))
+		require.NotEmpty(t, Frames(err))
+		assert.Equal(t, "github.com/alesr/errx.TestWithJob.func2", Frames(err)[0].Function)
+	})
+
+	t.Run("request attributes skip empty values", func(t *testing.T) {
+		t.Parallel()
+
+		err := WithRequest(Wrap(errors.New("boom")), "POST", "/invoices/{id}", "")
+
+		assert.Equal(t, []Attr{
+			{Key: AttrHTTPMethod, Value: "POST"},
+			{Key: AttrHTTPRoute, Value: "/invoices/{id}"},
+		}, Attrs(err))
+		id, ok := Value[string](err, AttrHTTPRequestID)
+		assert.False(t, ok)
+		assert.Empty(t, id)
+	})
+}
@@ -0,0 +1,129 @@
+package errx
+
+import "fmt"
+
+// Reporter sends errors to an external system such as an error tracker.
+type Reporter interface {
+	Report(err error)
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(err error)
+
+// Report calls f(err).
+func (f ReporterFunc) Report(err error) {
+	f(err)
+}
+
+// Report passes err to the Reporter of the package-wide configuration.
+// It does nothing if err is nil or no Reporter is configured.
+func Report(err error) {
+	report(loadConfig(), err)
+}
+
+// report passes err to c's Reporter, if any.
+func report(c *Config, err error) {
+	if err != nil && c.Reporter != nil {
+		Handled(err)
+		c.Reporter.Report(err)
+	}
+}
+
+// Option configures a Wrapper.
+type Option interface {
+	apply(c *Config)
+}
+
+// apply makes a Config usable as an Option replacing the whole configuration.
+func (c Config) apply(dst *Config) {
+	*dst = c.clone()
+}
+
+type optionFunc func(c *Config)
+
+func (f optionFunc) apply(c *Config) {
+	f(c)
+}
+
+// Depth sets the maximum number of frames captured on first wrap.
+func Depth(n int) Option {
+	return optionFunc(func(c *Config) {
+		c.Depth = n
+	})
+}
+
+// Token prefixes the Error output of the Wrapper's errors with their code
+// and severity, see Config.Token.
+func Token() Option {
+	return optionFunc(func(c *Config) {
+		c.Token = true
+	})
+}
+
+// Quiet makes the Wrapper's errors show only their outermost frame in
+// Error, see Config.Quiet.
+func Quiet() Option {
+	return optionFunc(func(c *Config) {
+		c.Quiet = true
+	})
+}
+
+// DefaultAttrs attaches attrs to every error the Wrapper wraps.
+func DefaultAttrs(attrs ...Attr) Option {
+	return optionFunc(func(c *Config) {
+		c.DefaultAttrs = append(c.DefaultAttrs, attrs...)
+	})
+}
+
+// ReportTo sets the Reporter used by Wrapper.Report.
+func ReportTo(r Reporter) Option {
+	return optionFunc(func(c *Config) {
+		c.Reporter = r
+	})
+}
+
+// Wrapper wraps errors using its own configuration instead of the
+// package-wide one, so libraries embedded in larger binaries don't need
+// to touch global state. Errors render with the configuration of the
+// Wrapper, or package-level function, that wrapped them last.
+// A Wrapper is safe for concurrent use.
+type Wrapper struct {
+	cfg *Config
+}
+
+// NewWrapper returns a Wrapper configured by opts, applied in order on
+// top of the zero Config. A Config is itself an Option.
+func NewWrapper(opts ...Option) *Wrapper {
+	var c Config
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	return &Wrapper{cfg: &c}
+}
+
+// Config returns a copy of the Wrapper's configuration.
+func (w *Wrapper) Config() Config {
+	return w.cfg.clone()
+}
+
+// Wrap works like the package-level Wrap using w's configuration.
+func (w *Wrapper) Wrap(err error, tags ...Tag) error {
+	if err == nil || w.cfg.ignored(err) {
+		return err
+	}
+	return wrap(w.cfg, err, 2, contextFrame{tags: joinTags(tags)})
+}
+
+// Wrapf works like the package-level Wrapf using w's configuration.
+func (w *Wrapper) Wrapf(err error, format string, args ...any) error {
+	if err == nil || w.cfg.ignored(err) {
+		return err
+	}
+	return wrap(w.cfg, err, 2, contextFrame{msg: eagerMessage(fmt.Sprintf(format, args...))})
+}
+
+// Report passes err to w's Reporter.
+// It does nothing if err is nil or no Reporter is configured.
+func (w *Wrapper) Report(err error) {
+	report(w.cfg, err)
+}
@@ -0,0 +1,156 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper()
+		assert.Nil(t, w.Wrap(nil))
+		assert.Nil(t, w.Wrapf(nil, "context"))
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper(Depth(1)).Wrap(errors.New("boom"))
+
+		assert.Len(t, Frames(err), 1)
+		assert.GreaterOrEqual(t, len(Frames(Wrap(errors.New("boom")))), 2)
+	})
+
+	t.Run("scoped rendering", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Deterministic: true})
+		err := w.Wrap(errors.New("boom"))
+
+		assert.Equal(t, "errx.TestWrapper.func3 (wrapper_test.go:NN): boom", err.Error())
+		assert.Equal(t, "0000-0000", ID(err))
+		assert.NotContains(t, Wrap(errors.New("boom")).Error(), ":NN)")
+	})
+
+	t.Run("rendering follows the last wrap", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Deterministic: true})
+		err := Wrap(w.Wrap(errors.New("boom")))
+
+		assert.NotContains(t, err.Error(), ":NN)")
+		assert.Contains(t, w.Wrap(err).Error(), ":NN)")
+	})
+
+	t.Run("default attributes", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(DefaultAttrs(Attr{Key: "component", Value: "billing"}))
+		err := w.Wrap(errors.New("boom"))
+
+		component, ok := Value[string](err, "component")
+		require.True(t, ok)
+		assert.Equal(t, "billing", component)
+		assert.Len(t, Attrs(w.Wrap(w.Wrap(err))), 1)
+	})
+
+	t.Run("default attributes when entering another wrapper", func(t *testing.T) {
+		t.Parallel()
+
+		lib := NewWrapper(DefaultAttrs(Attr{Key: "component", Value: "lib"}))
+		app := NewWrapper(DefaultAttrs(Attr{Key: "component", Value: "app"}))
+
+		err := app.Wrap(lib.Wrap(errors.New("boom")))
+
+		component, _ := Value[string](err, "component")
+		assert.Equal(t, "app", component)
+	})
+
+	t.Run("ignore list", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Config{Ignore: []error{io.EOF}})
+
+		assert.Equal(t, io.EOF, w.Wrap(io.EOF))
+		assert.NotEqual(t, io.EOF, Wrap(io.EOF))
+	})
+
+	t.Run("wrapf", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewWrapper().Wrapf(errors.New("boom"), "loading user %d", 42)
+
+		assert.Contains(t, err.Error(), "): loading user 42: ")
+		assert.True(t, strings.HasSuffix(err.Error(), ": boom"))
+	})
+
+	t.Run("report", func(t *testing.T) {
+		t.Parallel()
+
+		var reported []error
+		w := NewWrapper(ReportTo(ReporterFunc(func(err error) {
+			reported = append(reported, err)
+		})))
+
+		err := w.Wrap(errors.New("boom"))
+		w.Report(err)
+		w.Report(nil)
+		NewWrapper().Report(err)
+
+		assert.Equal(t, []error{err}, reported)
+	})
+
+	t.Run("options apply in order", func(t *testing.T) {
+		t.Parallel()
+
+		w := NewWrapper(Depth(3), Config{Deterministic: true}, Depth(5))
+
+		assert.Equal(t, Config{Deterministic: true, Depth: 5}, w.Config())
+	})
+}
+
+func TestReport(t *testing.T) {
+	var reported []error
+	WithTestConfig(t, Config{Reporter: ReporterFunc(func(err error) {
+		reported = append(reported, err)
+	})})
+
+	err := Wrap(errors.New("boom"))
+	Report(err)
+	Report(nil)
+
+	assert.Equal(t, []error{err}, reported)
+}
+
+func TestWrapf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil input", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Wrapf(nil, "context"))
+	})
+
+	t.Run("formats the message", func(t *testing.T) {
+		t.Parallel()
+
+		originalErr := errors.New("boom")
+		err := Wrapf(originalErr, "loading user %d", 42)
+
+		assert.True(t, errors.Is(err, originalErr))
+		assert.Contains(t, err.Error(), "TestWrapf.func2 (wrapper_test.go:")
+		assert.Contains(t, err.Error(), "): loading user 42: ")
+		assert.True(t, strings.HasSuffix(err.Error(), ": boom"))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "): loading user 42: boom\n")
+	})
+}